@@ -1,15 +1,24 @@
 package main
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	_ "time/tzdata" // 内置时区数据，用户时区（每日采集额度）不依赖运行环境的 zoneinfo
 
 	"github.com/gin-gonic/gin"
+	"github.com/keenchase/edit-business/internal/archiver"
 	"github.com/keenchase/edit-business/internal/config"
 	"github.com/keenchase/edit-business/internal/handler"
 	"github.com/keenchase/edit-business/internal/repository"
 	"github.com/keenchase/edit-business/internal/router"
 	"github.com/keenchase/edit-business/internal/service"
+	"github.com/keenchase/edit-business/internal/storage"
 	"github.com/keenchase/edit-business/pkg/database"
 )
 
@@ -32,19 +41,45 @@ func main() {
 	// 初始化仓库层
 	db := database.GetDB()
 	noteRepo := repository.NewNoteRepository(db)
+	noteMetricRepo := repository.NewNoteMetricRepository(db)
 	bloggerRepo := repository.NewBloggerRepository(db)
+	bloggerMetricRepo := repository.NewBloggerMetricRepository(db)
 	userRepo := repository.NewUserRepository(db)
 	apiKeyRepo := repository.NewAPIKeyRepository(db)
 	userSettingsRepo := repository.NewUserSettingsRepository(db)
+	trashRepo := repository.NewTrashRepository(db)
+	collectionRepo := repository.NewCollectionRepository(db)
+	noteEditorialRepo := repository.NewNoteEditorialRepository(db)
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+	noteMediaRepo := repository.NewNoteMediaRepository(db)
+	uploadedObjectRepo := repository.NewUploadedObjectRepository(db)
+	storageDeletionRepo := repository.NewStorageDeletionRepository(db)
+
+	// 初始化对象存储（直传凭证和媒体归档共用）
+	objectStorage, err := storage.New(storageConfig(cfg), &http.Client{Timeout: cfg.MediaDownloadTimeout})
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+	log.Printf("Object storage driver: %s", objectStorage.Driver())
 
 	// 初始化服务层
 	// Note: UserSettingsService must be created before NoteService and BloggerService since they depend on it
-	userSettingsService := service.NewUserSettingsService(userSettingsRepo, userRepo, noteRepo)
-	noteService := service.NewNoteService(noteRepo, userSettingsService)
-	bloggerService := service.NewBloggerService(bloggerRepo, userSettingsService)
+	userSettingsService := service.NewUserSettingsService(userSettingsRepo, userRepo, noteRepo, bloggerRepo)
+	noteService := service.NewNoteService(noteRepo, noteMetricRepo, userSettingsService, collectionRepo, noteEditorialRepo)
+	bloggerService := service.NewBloggerService(bloggerRepo, bloggerMetricRepo, userSettingsService, noteRepo)
 	userService := service.NewUserService(userRepo)
 	statsService := service.NewStatsService(noteRepo, bloggerRepo, userSettingsService)
 	apiKeyService := service.NewAPIKeyService(apiKeyRepo, userRepo)
+	importService := service.NewImportService(noteRepo, bloggerRepo, userSettingsService)
+	trashService := service.NewTrashService(trashRepo, noteRepo, bloggerRepo, userSettingsService, cfg.TrashRetentionDays)
+	collectionService := service.NewCollectionService(collectionRepo, noteRepo, userSettingsService)
+	noteEditorialService := service.NewNoteEditorialService(noteEditorialRepo, noteRepo, userSettingsService)
+	idempotencyService := service.NewIdempotencyService(idempotencyRepo, userSettingsService, cfg.IdempotencyKeyTTL)
+	storageService := service.NewStorageService(objectStorage, uploadedObjectRepo, noteRepo, userSettingsService, service.UploadLimits{
+		MaxBytes:  cfg.StorageUploadMaxBytes,
+		MimeTypes: cfg.StorageUploadMimeTypes,
+		TokenTTL:  cfg.StorageUploadTokenTTL,
+	})
 	authCenterService := service.NewAuthCenterService() // 账号中心服务
 
 	// 初始化处理器
@@ -57,11 +92,15 @@ func main() {
 	userSettingsHandler := handler.NewUserSettingsHandler(userSettingsService)
 	adminService := service.NewAdminService(userRepo, apiKeyRepo, userSettingsRepo, noteRepo, bloggerRepo, statsService, apiKeyService)
 	adminHandler := handler.NewAdminHandler(adminService, cfg.AdminAuthCenterUserIDs)
-	qiniuHandler := handler.NewQiniuHandler()
+	storageHandler := handler.NewStorageHandler(storageService)
+	importHandler := handler.NewImportHandler(importService)
+	trashHandler := handler.NewTrashHandler(trashService)
+	collectionHandler := handler.NewCollectionHandler(collectionService)
+	noteEditorialHandler := handler.NewNoteEditorialHandler(noteEditorialService)
 
 	// 设置路由
 	gin.SetMode(gin.ReleaseMode)
-	router := router.SetupRouter(noteHandler, bloggerHandler, userHandler, authHandler, statsHandler, apiKeyHandler, userSettingsHandler, adminHandler, qiniuHandler, authCenterService, userRepo, cfg.AdminAuthCenterUserIDs)
+	router := router.SetupRouter(noteHandler, bloggerHandler, userHandler, authHandler, statsHandler, apiKeyHandler, userSettingsHandler, adminHandler, storageHandler, importHandler, trashHandler, collectionHandler, noteEditorialHandler, authCenterService, idempotencyService, userRepo, cfg.AdminAuthCenterUserIDs)
 
 	// 打印路由信息
 	log.Printf("Router initialized. Registered routes:")
@@ -69,6 +108,36 @@ func main() {
 		log.Printf("  %s %s", route.Method, route.Path)
 	}
 
+	// 回收站后台清理：永久删除超过保留期的记录
+	trashService.StartPurger(cfg.TrashPurgeInterval)
+
+	// 幂等键后台清理：删除过期的插件同步幂等键
+	idempotencyService.StartCleaner(cfg.IdempotencyCleanupInterval)
+
+	// 存储清理：删除永久删除笔记后不再被引用的归档文件和直传文件
+	service.NewStorageCleanupService(storageDeletionRepo, objectStorage).StartCleaner(cfg.StorageCleanupInterval)
+
+	// 本地存储驱动：以静态文件提供对象访问
+	if local, ok := objectStorage.(*storage.Local); ok {
+		if u, err := url.Parse(local.BaseURL()); err == nil && strings.HasPrefix(u.Path, "/") {
+			router.Static(u.Path, local.Dir())
+		}
+	}
+
+	// 媒体归档：把笔记引用的小红书 CDN 图片、视频下载到对象存储
+	if cfg.MediaArchiveEnabled {
+		mediaArchiver := archiver.New(archiver.NewHTTPClient(cfg.MediaDownloadTimeout), objectStorage, archiver.Options{
+			MaxBytes:      cfg.MediaMaxBytes,
+			Retries:       2,
+			Backoff:       time.Second,
+			Referer:       "https://www.xiaohongshu.com/",
+			AllowedHosts:  cfg.MediaAllowedHosts,
+			ThumbnailSize: cfg.MediaThumbnailSize,
+		})
+		mediaArchiveService := service.NewMediaArchiveService(noteMediaRepo, mediaArchiver, cfg.MediaArchiveBatchSize, cfg.MediaArchiveMaxAttempts, cfg.MediaArchiveRetryDelay)
+		mediaArchiveService.StartArchiver(cfg.MediaArchiveInterval)
+	}
+
 	// 启动服务器
 	addr := fmt.Sprintf("%s:%s", cfg.ServerHost, cfg.ServerPort)
 	log.Printf("Starting server on %s", addr)
@@ -77,3 +146,36 @@ func main() {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
+
+// storageConfig 对象存储配置；本地驱动未设置签名密钥时随机生成（重启后已签发的直传凭证失效）
+func storageConfig(cfg *config.Config) storage.Config {
+	secret := cfg.StorageSigningSecret
+	if secret == "" && cfg.StorageDriver == storage.DriverLocal {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			log.Fatalf("Failed to generate storage signing secret: %v", err)
+		}
+		secret = hex.EncodeToString(buf)
+		log.Printf("STORAGE_SIGNING_SECRET not set, using a random secret; upload tokens will not survive restarts")
+	}
+
+	return storage.Config{
+		Driver:         cfg.StorageDriver,
+		LocalDir:       cfg.StorageLocalDir,
+		LocalBaseURL:   cfg.StorageLocalBaseURL,
+		LocalUploadURL: cfg.StorageLocalUploadURL,
+		SigningSecret:  secret,
+		QiniuAccessKey: cfg.QiniuAccessKey,
+		QiniuSecretKey: cfg.QiniuSecretKey,
+		QiniuBucket:    cfg.QiniuBucket,
+		QiniuDomain:    cfg.QiniuDomain,
+		QiniuUploadURL: cfg.QiniuUploadURL,
+		S3Endpoint:     cfg.S3Endpoint,
+		S3Region:       cfg.S3Region,
+		S3Bucket:       cfg.S3Bucket,
+		S3AccessKey:    cfg.S3AccessKey,
+		S3SecretKey:    cfg.S3SecretKey,
+		S3PublicURL:    cfg.S3PublicURL,
+		S3PathStyle:    cfg.S3PathStyle,
+	}
+}
@@ -0,0 +1,206 @@
+// Package analyzer 笔记内容分析：从标题和正文中提取话题、@提及、表情和关键词
+// 小红书正文中的话题为 #话题[话题]#，表情为 [笑哭R] 形式的文字表情或 Unicode 表情
+package analyzer
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/keenchase/edit-business/internal/search"
+)
+
+// 分析结果中的词项类型
+const (
+	KindHashtag = "hashtag"
+	KindMention = "mention"
+	KindEmoji   = "emoji"
+	KindKeyword = "keyword"
+)
+
+// Kinds 全部词项类型
+var Kinds = []string{KindHashtag, KindMention, KindEmoji, KindKeyword}
+
+const (
+	// MaxKeywords 每篇笔记保留的关键词数量
+	MaxKeywords = 20
+	// maxTermRunes 词项最大长度（与存储列宽一致）
+	maxTermRunes = 64
+	// titleWeight 标题中关键词的权重（相对正文）
+	titleWeight = 2
+)
+
+var (
+	// richHashtag 小红书富文本话题：#话题[话题]#（方括号内为类型标记）
+	richHashtag = regexp.MustCompile(`#([^#\[\]\r\n]{1,50})\[[^\[\]#\r\n]{1,10}\]#`)
+	// plainHashtag 手动输入的话题：#话题，以空白或标点结束
+	plainHashtag = regexp.MustCompile(`(?:^|[^\p{L}\p{N}&])#([^\s#@\[\]，。！？、；：,.!?;:]{1,30})`)
+	// mention @提及，可带 [用户] 等类型标记；前面是字母数字时视为邮箱，不提取
+	mention = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_.])@([^\s@#\[\]，。！？、；：,!?;:]{1,30})(?:\[[^\[\]\r\n]{1,10}\])?`)
+	// textEmoji 小红书文字表情：[笑哭R]
+	textEmoji = regexp.MustCompile(`\[[^\[\]\s]{1,8}R\]`)
+	// link 链接和邮箱不参与提及和关键词统计
+	link = regexp.MustCompile(`https?://\S+|[\w.+-]+@[\w-]+(?:\.[\w-]+)+`)
+)
+
+// Result 分析结果：各类词项及其出现次数
+type Result struct {
+	Hashtags map[string]int
+	Mentions map[string]int
+	Emoji    map[string]int
+	// Keywords 出现次数最多的 MaxKeywords 个关键词；中文不做词典分词，按二元组统计，
+	// 跨笔记聚合后有意义的词会排在前面
+	Keywords map[string]int
+}
+
+// Terms 按类型返回词项
+func (r *Result) Terms(kind string) map[string]int {
+	switch kind {
+	case KindHashtag:
+		return r.Hashtags
+	case KindMention:
+		return r.Mentions
+	case KindEmoji:
+		return r.Emoji
+	case KindKeyword:
+		return r.Keywords
+	}
+	return nil
+}
+
+// Analyze 分析笔记标题和正文；tags 为插件采集的标签，合并到话题中
+func Analyze(title, content string, tags []string) *Result {
+	r := &Result{
+		Hashtags: make(map[string]int),
+		Mentions: make(map[string]int),
+		Emoji:    make(map[string]int),
+	}
+
+	keywords := make(map[string]int)
+	for _, part := range []struct {
+		text   string
+		weight int
+	}{{title, titleWeight}, {content, 1}} {
+		rest := r.extract(part.text)
+		for _, t := range search.Tokens(rest) {
+			if isKeyword(t) {
+				keywords[t] += part.weight
+			}
+		}
+	}
+	r.Keywords = topTerms(keywords, MaxKeywords)
+
+	for _, tag := range tags {
+		if t := normalize(strings.TrimLeft(tag, "#")); t != "" && r.Hashtags[t] == 0 {
+			r.Hashtags[t] = 1
+		}
+	}
+	return r
+}
+
+// extract 提取话题、提及和表情，返回去掉这些内容后的文本（用于关键词统计）
+func (r *Result) extract(text string) string {
+	text = link.ReplaceAllString(text, " ")
+	text = replaceSubmatch(richHashtag, text, r.Hashtags)
+	text = replaceSubmatch(plainHashtag, text, r.Hashtags)
+	text = replaceSubmatch(mention, text, r.Mentions)
+	text = textEmoji.ReplaceAllStringFunc(text, func(m string) string {
+		r.Emoji[m]++
+		return " "
+	})
+	return r.extractUnicodeEmoji(text)
+}
+
+// replaceSubmatch 统计第一个分组，并把整个匹配替换为空格
+func replaceSubmatch(re *regexp.Regexp, text string, counts map[string]int) string {
+	return re.ReplaceAllStringFunc(text, func(m string) string {
+		if t := normalize(re.FindStringSubmatch(m)[1]); t != "" {
+			counts[t]++
+		}
+		return " "
+	})
+}
+
+// extractUnicodeEmoji 统计 Unicode 表情（ZWJ 组合、肤色、变体选择符视为同一个表情）
+func (r *Result) extractUnicodeEmoji(text string) string {
+	var rest strings.Builder
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		if !isEmoji(runes[i]) {
+			rest.WriteRune(runes[i])
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(runes) {
+			if isEmojiModifier(runes[j]) {
+				j++
+			} else if runes[j] == 0x200D && j+1 < len(runes) && isEmoji(runes[j+1]) {
+				j += 2
+			} else {
+				break
+			}
+		}
+		r.Emoji[string(runes[i:j])]++
+		rest.WriteRune(' ')
+		i = j
+	}
+	return rest.String()
+}
+
+func isEmoji(r rune) bool {
+	return (r >= 0x1F300 && r <= 0x1FAFF) || // 符号和象形文字、表情、交通、补充符号
+		(r >= 0x1F000 && r <= 0x1F2FF) || // 麻将、扑克、带圈字母数字、区域旗帜
+		(r >= 0x2600 && r <= 0x27BF) || // 杂项符号、装饰符号
+		r == 0x2B50 || r == 0x2B55 || r == 0x2764
+}
+
+func isEmojiModifier(r rune) bool {
+	return r == 0xFE0F || r == 0x20E3 || (r >= 0x1F3FB && r <= 0x1F3FF) || (r >= 0x1F1E6 && r <= 0x1F1FF)
+}
+
+// normalize 去掉首尾空白，英文转小写，截断到列宽
+func normalize(term string) string {
+	term = strings.ToLower(strings.TrimSpace(term))
+	if utf8.RuneCountInString(term) > maxTermRunes {
+		term = string([]rune(term)[:maxTermRunes])
+	}
+	return term
+}
+
+// isKeyword 过滤单字、纯数字和停用词
+func isKeyword(t string) bool {
+	if utf8.RuneCountInString(t) < 2 || stopwords[t] {
+		return false
+	}
+	for _, r := range t {
+		if !unicode.IsDigit(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// topTerms 取出现次数最多的 n 个词项（次数相同按字典序）
+func topTerms(counts map[string]int, n int) map[string]int {
+	terms := make([]string, 0, len(counts))
+	for t := range counts {
+		terms = append(terms, t)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if counts[terms[i]] != counts[terms[j]] {
+			return counts[terms[i]] > counts[terms[j]]
+		}
+		return terms[i] < terms[j]
+	})
+	if len(terms) > n {
+		terms = terms[:n]
+	}
+	top := make(map[string]int, len(terms))
+	for _, t := range terms {
+		top[t] = counts[t]
+	}
+	return top
+}
@@ -0,0 +1,28 @@
+package analyzer
+
+// stopwords 不作为关键词的常见虚词（中文二元组和英文单词）
+var stopwords = toSet(
+	// 中文
+	"一个", "一些", "一下", "一样", "一直", "一定", "一起", "不是", "不会", "不要", "不过", "也是", "也不",
+	"了一", "了我", "了就", "了吧", "人的", "什么", "今天", "他们", "以后", "以前", "但是", "你们", "你的",
+	"其实", "到了", "只是", "只有", "可以", "可能", "和我", "因为", "大家", "如果", "她们", "就是", "就会",
+	"已经", "并且", "很多", "怎么", "我们", "我的", "所以", "所有", "是一", "是我", "是不", "有一", "有点",
+	"没有", "然后", "现在", "的时", "时候", "的话", "的人", "的是", "真的", "而且", "自己", "还是", "还有",
+	"这个", "这些", "这样", "这是", "这么", "那个", "那些", "那么", "都是", "非常", "需要", "觉得", "一次",
+	"还要", "就像", "我也", "我在", "在这", "里的", "也没", "就不", "要是", "起来", "出来", "之后", "之前",
+	// 英文
+	"the", "and", "for", "are", "but", "not", "you", "all", "any", "can", "her", "was", "one", "our", "out",
+	"his", "has", "had", "how", "its", "may", "new", "now", "see", "who", "did", "get", "let", "she", "too",
+	"use", "that", "with", "have", "this", "will", "your", "from", "they", "been", "were", "what", "when",
+	"there", "their", "which", "would", "about", "into", "than", "them", "then", "these", "some", "just",
+	"is", "it", "in", "on", "of", "to", "a", "an", "at", "as", "be", "by", "do", "if", "my", "me", "so",
+	"up", "we", "or", "no", "go", "am", "he",
+)
+
+func toSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
@@ -0,0 +1,282 @@
+// Package archiver 把笔记引用的小红书 CDN 图片、视频下载到自有存储
+// CDN 链接会过期，归档后的副本用于长期展示
+package archiver
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/keenchase/edit-business/internal/phash"
+	"github.com/keenchase/edit-business/internal/storage"
+)
+
+const (
+	// maxThumbnailPixels 超过该像素数的图片不生成缩略图（避免解码占用过多内存）
+	maxThumbnailPixels = 40_000_000
+	thumbnailQuality   = 80
+)
+
+// 媒体类型：决定允许的 Content-Type
+const (
+	KindImage = "image"
+	KindVideo = "video"
+)
+
+var (
+	ErrTooLarge    = errors.New("media exceeds size limit")
+	ErrContentType = errors.New("unexpected media content type")
+)
+
+// Options 归档参数
+type Options struct {
+	MaxBytes int64         // 单个文件大小上限
+	Retries  int           // 网络错误、5xx、429 时的重试次数
+	Backoff  time.Duration // 首次重试前的等待时间，之后每次翻倍
+	Referer  string        // 请求 CDN 时携带的 Referer
+
+	// AllowedHosts 允许下载的主机（包含子域名），只请求 https；不在列表中的链接返回 ErrForbiddenURL
+	AllowedHosts []string
+
+	// ThumbnailSize 图片缩略图最长边，0 表示不生成缩略图和感知哈希
+	ThumbnailSize int
+}
+
+// Archiver 下载媒体文件并写入存储
+type Archiver struct {
+	client  *http.Client
+	storage storage.Storage
+	opts    Options
+}
+
+// New 创建归档器；client 通常由 NewHTTPClient 创建，跳转策略会被替换为 checkRedirect
+func New(client *http.Client, store storage.Storage, opts Options) *Archiver {
+	a := &Archiver{storage: store, opts: opts}
+	c := *client
+	c.CheckRedirect = a.checkRedirect
+	a.client = &c
+	return a
+}
+
+// Result 归档结果
+type Result struct {
+	URL         string
+	ContentType string
+	Size        int64
+	Thumbnail   *Thumbnail // 图片无法解码（如 webp、heic）时为 nil
+}
+
+// Thumbnail 图片缩略图和感知哈希
+type Thumbnail struct {
+	URL   string
+	DHash uint64
+	AHash uint64
+}
+
+// permanentError 重试也不会成功的错误（4xx、超出大小、类型不符）
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// IsPermanent 错误是否不值得再次尝试
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// Archive 下载 sourceURL 并以 keyPrefix/{链接摘要}{扩展名} 写入存储
+// kind 为 KindImage 或 KindVideo，Content-Type 不符时返回 ErrContentType；
+// 链接不在允许列表中或解析到内网地址时返回 ErrForbiddenURL / ErrForbiddenAddress，均不重试
+func (a *Archiver) Archive(ctx context.Context, sourceURL, kind, keyPrefix string) (*Result, error) {
+	fetchURL, err := a.sourceURL(sourceURL)
+	if err != nil {
+		return nil, &permanentError{err}
+	}
+
+	tmp, err := os.CreateTemp("", "media-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	var contentType string
+	var size int64
+	backoff := a.opts.Backoff
+	for attempt := 0; ; attempt++ {
+		contentType, size, err = a.download(ctx, fetchURL, tmp)
+		if err == nil || IsPermanent(err) || attempt >= a.opts.Retries {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(contentType, kind+"/") {
+		return nil, &permanentError{fmt.Errorf("%w: %s", ErrContentType, contentType)}
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	key := objectKey(keyPrefix, sourceURL, contentType)
+	url, err := a.storage.Put(ctx, key, tmp, size, contentType)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{URL: url, ContentType: contentType, Size: size}
+
+	if kind == KindImage && a.opts.ThumbnailSize > 0 {
+		result.Thumbnail, err = a.thumbnail(ctx, tmp, key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// thumbnail 解码图片，计算感知哈希并写入 JPEG 缩略图（key 为原图 key 加 _thumb 后缀）
+// 标准库无法解码的格式返回 nil，不视为归档失败
+func (a *Archiver) thumbnail(ctx context.Context, f *os.File, key string) (*Thumbnail, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil || cfg.Width*cfg.Height > maxThumbnailPixels {
+		return nil, nil
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, phash.Thumbnail(img, a.opts.ThumbnailSize), &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, err
+	}
+	thumbKey := strings.TrimSuffix(key, path.Ext(key)) + "_thumb.jpg"
+	url, err := a.storage.Put(ctx, thumbKey, &buf, int64(buf.Len()), "image/jpeg")
+	if err != nil {
+		return nil, err
+	}
+	return &Thumbnail{URL: url, DHash: phash.DHash(img), AHash: phash.AHash(img)}, nil
+}
+
+// download 下载到 w（先清空），返回 Content-Type 和大小
+func (a *Archiver) download(ctx context.Context, sourceURL string, w *os.File) (string, int64, error) {
+	if err := w.Truncate(0); err != nil {
+		return "", 0, err
+	}
+	if _, err := w.Seek(0, io.SeekStart); err != nil {
+		return "", 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sourceURL, nil)
+	if err != nil {
+		return "", 0, &permanentError{err}
+	}
+	if a.opts.Referer != "" {
+		req.Header.Set("Referer", a.opts.Referer)
+	}
+	// 优先请求标准库可解码的格式，以便生成缩略图和感知哈希
+	req.Header.Set("Accept", "image/jpeg,image/png;q=0.9,image/*;q=0.8,*/*;q=0.5")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrForbiddenURL) || errors.Is(err, ErrForbiddenAddress) {
+			return "", 0, &permanentError{err}
+		}
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("unexpected status %d", resp.StatusCode)
+		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+			return "", 0, err
+		}
+		return "", 0, &permanentError{err}
+	}
+	if a.opts.MaxBytes > 0 && resp.ContentLength > a.opts.MaxBytes {
+		return "", 0, &permanentError{ErrTooLarge}
+	}
+
+	// 多读 1 字节判断是否超出上限（Content-Length 可能缺失或不准确）
+	body := io.Reader(resp.Body)
+	if a.opts.MaxBytes > 0 {
+		body = io.LimitReader(resp.Body, a.opts.MaxBytes+1)
+	}
+	size, err := io.Copy(w, body)
+	if err != nil {
+		return "", 0, err
+	}
+	if a.opts.MaxBytes > 0 && size > a.opts.MaxBytes {
+		return "", 0, &permanentError{ErrTooLarge}
+	}
+
+	contentType, err := detectContentType(resp.Header.Get("Content-Type"), w)
+	if err != nil {
+		return "", 0, err
+	}
+	return contentType, size, nil
+}
+
+// detectContentType 优先使用响应头，缺失或为 application/octet-stream 时按文件内容判断
+func detectContentType(header string, f *os.File) (string, error) {
+	if mediaType, _, err := mime.ParseMediaType(header); err == nil && mediaType != "application/octet-stream" {
+		return mediaType, nil
+	}
+	buf := make([]byte, 512)
+	n, err := f.ReadAt(buf, 0)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(buf[:n]))
+	return mediaType, nil
+}
+
+// extensions 常见媒体类型的扩展名（mime.ExtensionsByType 按字母序返回，不一定是常用的那个）
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+	"image/heic": ".heic",
+	"video/mp4":  ".mp4",
+}
+
+// objectKey 按链接摘要生成存储路径，同一链接重复归档时覆盖同一对象
+func objectKey(prefix, sourceURL, contentType string) string {
+	sum := sha256.Sum256([]byte(sourceURL))
+	ext, ok := extensions[contentType]
+	if !ok {
+		if exts, err := mime.ExtensionsByType(contentType); err == nil && len(exts) > 0 {
+			ext = exts[0]
+		}
+	}
+	return strings.Trim(prefix, "/") + "/" + hex.EncodeToString(sum[:16]) + ext
+}
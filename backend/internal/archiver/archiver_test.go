@@ -0,0 +1,281 @@
+package archiver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/keenchase/edit-business/internal/storage"
+)
+
+const testBaseURL = "http://media.test/media"
+
+// newTestArchiver 本地磁盘存储 + 信任 srv 证书的客户端（不检查内网地址），允许请求 srv 的主机，
+// 返回归档器和存储目录
+func newTestArchiver(t *testing.T, srv *httptest.Server, opts Options) (*Archiver, string) {
+	t.Helper()
+	dir := t.TempDir()
+	store := storage.NewLocal(dir, testBaseURL, "/upload", "secret")
+	if opts.Backoff == 0 {
+		opts.Backoff = time.Millisecond
+	}
+	if opts.AllowedHosts == nil {
+		opts.AllowedHosts = []string{"127.0.0.1"}
+	}
+	return New(srv.Client(), store, opts), dir
+}
+
+// storedFile 读取归档结果 URL 对应的本地文件
+func storedFile(t *testing.T, dir, url string) []byte {
+	t.Helper()
+	key := strings.TrimPrefix(url, testBaseURL+"/")
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(key)))
+	if err != nil {
+		t.Fatalf("read stored file for %s: %v", url, err)
+	}
+	return data
+}
+
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 255 / w), G: uint8(y * 255 / h), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestArchiveRetriesTransientErrors(t *testing.T) {
+	body := testPNG(t, 4, 4)
+	var calls int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			if r.Header.Get("Referer") != "https://www.xiaohongshu.com/" {
+				t.Errorf("Referer = %q", r.Header.Get("Referer"))
+			}
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(body)
+		}
+	}))
+	defer srv.Close()
+
+	a, dir := newTestArchiver(t, srv, Options{Retries: 2, Referer: "https://www.xiaohongshu.com/"})
+	result, err := a.Archive(context.Background(), srv.URL+"/a.png", KindImage, "notes/u1")
+	if err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+	if result.ContentType != "image/png" || result.Size != int64(len(body)) {
+		t.Errorf("result = %+v", result)
+	}
+	if !strings.HasPrefix(result.URL, testBaseURL+"/notes/u1/") || !strings.HasSuffix(result.URL, ".png") {
+		t.Errorf("URL = %s", result.URL)
+	}
+	if got := storedFile(t, dir, result.URL); !bytes.Equal(got, body) {
+		t.Errorf("stored %d bytes, want %d", len(got), len(body))
+	}
+}
+
+func TestArchiveGivesUpAfterRetries(t *testing.T) {
+	var calls int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	a, _ := newTestArchiver(t, srv, Options{Retries: 2})
+	_, err := a.Archive(context.Background(), srv.URL+"/a.jpg", KindImage, "notes/u1")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if IsPermanent(err) {
+		t.Errorf("5xx should not be permanent: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestArchiveDoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	a, _ := newTestArchiver(t, srv, Options{Retries: 3})
+	_, err := a.Archive(context.Background(), srv.URL+"/a.jpg", KindImage, "notes/u1")
+	if !IsPermanent(err) {
+		t.Fatalf("want permanent error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestArchiveSizeCap(t *testing.T) {
+	body := bytes.Repeat([]byte{0xff}, 64)
+	tests := []struct {
+		name          string
+		contentLength bool
+	}{
+		{"declared content length", true},
+		{"chunked body", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.Header().Set("Content-Type", "image/jpeg")
+				if !tt.contentLength {
+					// 先 Flush 使响应以 chunked 编码发送，不带 Content-Length
+					w.(http.Flusher).Flush()
+				}
+				w.Write(body)
+			}))
+			defer srv.Close()
+
+			a, dir := newTestArchiver(t, srv, Options{MaxBytes: 32, Retries: 2})
+			_, err := a.Archive(context.Background(), srv.URL+"/big.jpg", KindImage, "notes/u1")
+			if !errors.Is(err, ErrTooLarge) || !IsPermanent(err) {
+				t.Fatalf("want permanent ErrTooLarge, got %v", err)
+			}
+			if calls != 1 {
+				t.Errorf("calls = %d, want 1 (no retry)", calls)
+			}
+			assertEmptyDir(t, dir)
+		})
+	}
+}
+
+func TestArchiveRejectsContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		kind        string
+		contentType string
+		body        []byte
+	}{
+		{"html for image", KindImage, "text/html; charset=utf-8", []byte("<html>blocked</html>")},
+		{"image for video", KindVideo, "image/jpeg", []byte{0xff, 0xd8, 0xff}},
+		{"sniffed html for image", KindImage, "", []byte("<!DOCTYPE html><html></html>")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				} else {
+					w.Header().Set("Content-Type", "application/octet-stream")
+				}
+				w.Write(tt.body)
+			}))
+			defer srv.Close()
+
+			a, dir := newTestArchiver(t, srv, Options{Retries: 2})
+			_, err := a.Archive(context.Background(), srv.URL+"/x", tt.kind, "notes/u1")
+			if !errors.Is(err, ErrContentType) || !IsPermanent(err) {
+				t.Fatalf("want permanent ErrContentType, got %v", err)
+			}
+			assertEmptyDir(t, dir)
+		})
+	}
+}
+
+func TestArchiveSniffsOctetStream(t *testing.T) {
+	body := testPNG(t, 2, 2)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(body)
+	}))
+	defer srv.Close()
+
+	a, _ := newTestArchiver(t, srv, Options{})
+	result, err := a.Archive(context.Background(), srv.URL+"/x", KindImage, "notes/u1")
+	if err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+	if result.ContentType != "image/png" || !strings.HasSuffix(result.URL, ".png") {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestArchiveThumbnail(t *testing.T) {
+	body := testPNG(t, 64, 32)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(body)
+	}))
+	defer srv.Close()
+
+	a, dir := newTestArchiver(t, srv, Options{ThumbnailSize: 16})
+	result, err := a.Archive(context.Background(), srv.URL+"/cover.png", KindImage, "notes/u1")
+	if err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+	if result.Thumbnail == nil {
+		t.Fatal("expected thumbnail")
+	}
+	if want := strings.TrimSuffix(result.URL, ".png") + "_thumb.jpg"; result.Thumbnail.URL != want {
+		t.Errorf("thumbnail URL = %s, want %s", result.Thumbnail.URL, want)
+	}
+	thumb, _, err := image.Decode(bytes.NewReader(storedFile(t, dir, result.Thumbnail.URL)))
+	if err != nil {
+		t.Fatalf("decode thumbnail: %v", err)
+	}
+	if b := thumb.Bounds(); b.Dx() != 16 || b.Dy() != 8 {
+		t.Errorf("thumbnail size = %dx%d, want 16x8", b.Dx(), b.Dy())
+	}
+}
+
+func TestObjectKeyIsStablePerURL(t *testing.T) {
+	a := objectKey("/notes/u1/", "https://cdn.example/a.jpg", "image/jpeg")
+	b := objectKey("notes/u1", "https://cdn.example/a.jpg", "image/jpeg")
+	c := objectKey("notes/u1", "https://cdn.example/b.jpg", "image/jpeg")
+	if a != b {
+		t.Errorf("same URL produced different keys: %s, %s", a, b)
+	}
+	if a == c {
+		t.Errorf("different URLs produced the same key: %s", a)
+	}
+	if !strings.HasPrefix(a, "notes/u1/") || !strings.HasSuffix(a, ".jpg") {
+		t.Errorf("key = %s", a)
+	}
+}
+
+// assertEmptyDir 失败的归档不应在存储中留下文件
+func assertEmptyDir(t *testing.T, dir string) {
+	t.Helper()
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			t.Errorf("unexpected stored file %s", path)
+		}
+		return nil
+	})
+}
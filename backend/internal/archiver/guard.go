@@ -0,0 +1,134 @@
+package archiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// maxRedirects 下载时最多跟随的跳转次数
+const maxRedirects = 5
+
+var (
+	// ErrForbiddenURL 链接不是 https 或主机不在允许列表中
+	ErrForbiddenURL = errors.New("media URL is not allowed")
+	// ErrForbiddenAddress 主机解析到内网、回环、链路本地等地址
+	ErrForbiddenAddress = errors.New("media host resolves to a forbidden address")
+)
+
+// blockedPrefixes IsPrivate / IsLoopback 等方法之外需要拒绝的网段
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // 本网络
+	netip.MustParsePrefix("100.64.0.0/10"), // 运营商级 NAT，部分云厂商的元数据服务在此网段
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF 协议分配
+	netip.MustParsePrefix("198.18.0.0/15"), // 基准测试
+	netip.MustParsePrefix("240.0.0.0/4"),   // 保留
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64，可映射到任意 IPv4
+	netip.MustParsePrefix("2002::/16"),     // 6to4，可映射到任意 IPv4
+}
+
+// isPublicAddr 是否为可以访问的公网地址
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	if addr.Is4() && addr == netip.AddrFrom4([4]byte{255, 255, 255, 255}) {
+		return false
+	}
+	for _, p := range blockedPrefixes {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// dialControl 在建立连接前检查实际要连接的 IP（DNS 解析之后），防止解析到内网地址或 DNS 重绑定
+func dialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !isPublicAddr(addr) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return nil
+}
+
+// NewHTTPClient 创建归档用的 HTTP 客户端：只连接公网地址（每次建立连接时检查，跳转同样受限），
+// 不使用环境变量中的代理（否则检查的是代理地址）
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   dialControl,
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy: nil,
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, addr)
+			},
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          20,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: time.Second,
+		},
+	}
+}
+
+// allowedHost 主机是否在允许列表中：与列表项相同或为其子域名
+func (a *Archiver) allowedHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, h := range a.opts.AllowedHosts {
+		h = strings.TrimPrefix(strings.ToLower(h), ".")
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+// sourceURL 校验待归档链接并返回实际请求的地址：允许列表中主机的 http 链接升级为 https，
+// 其他协议、不在允许列表中的主机和带用户信息的链接返回 ErrForbiddenURL
+func (a *Archiver) sourceURL(raw string) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrForbiddenURL, err)
+	}
+	if u.Scheme == "http" {
+		u.Scheme = "https"
+	}
+	if err := a.checkURL(u); err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// checkURL 只允许 https 且主机在允许列表中的链接（初始请求和每次跳转都检查）
+func (a *Archiver) checkURL(u *url.URL) error {
+	if u.Scheme != "https" || u.User != nil || !a.allowedHost(u.Hostname()) {
+		return fmt.Errorf("%w: %s", ErrForbiddenURL, u.Redacted())
+	}
+	return nil
+}
+
+// checkRedirect 跳转目标同样需要通过 checkURL，且不超过 maxRedirects 次
+func (a *Archiver) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	return a.checkURL(req.URL)
+}
@@ -3,7 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Config 应用配置
@@ -25,6 +27,48 @@ type Config struct {
 
 	// 管理后台：管理员 auth_center_user_id 列表，逗号分隔
 	AdminAuthCenterUserIDs []string
+
+	// 回收站：保留天数，以及后台清理过期记录的间隔
+	TrashRetentionDays int
+	TrashPurgeInterval time.Duration
+
+	// 插件同步幂等键：有效期，以及后台清理过期键的间隔
+	IdempotencyKeyTTL          time.Duration
+	IdempotencyCleanupInterval time.Duration
+
+	// 对象存储：驱动（local / qiniu / s3）及各驱动的配置，启动时读取一次
+	StorageDriver          string
+	StorageLocalDir        string
+	StorageLocalBaseURL    string
+	StorageLocalUploadURL  string
+	StorageSigningSecret   string
+	StorageUploadMaxBytes  int64
+	StorageUploadMimeTypes []string
+	StorageUploadTokenTTL  time.Duration
+	StorageCleanupInterval time.Duration
+	QiniuAccessKey         string
+	QiniuSecretKey         string
+	QiniuBucket            string
+	QiniuDomain            string
+	QiniuUploadURL         string
+	S3Endpoint             string
+	S3Region               string
+	S3Bucket               string
+	S3AccessKey            string
+	S3SecretKey            string
+	S3PublicURL            string
+	S3PathStyle            bool
+
+	// 媒体归档：下载笔记引用的图片、视频到对象存储
+	MediaArchiveEnabled     bool
+	MediaMaxBytes           int64
+	MediaThumbnailSize      int
+	MediaDownloadTimeout    time.Duration
+	MediaAllowedHosts       []string
+	MediaArchiveInterval    time.Duration
+	MediaArchiveBatchSize   int
+	MediaArchiveMaxAttempts int
+	MediaArchiveRetryDelay  time.Duration
 }
 
 // LoadConfig 从环境变量加载配置
@@ -45,24 +89,78 @@ func LoadConfig() *Config {
 		AuthCenterURL: getEnv("AUTH_CENTER_URL", "https://os.crazyaigc.com"),
 
 		// 管理后台：EDIT_ADMIN_AUTH_CENTER_USER_IDS=id1,id2,id3
-		AdminAuthCenterUserIDs: parseAdminIDs(getEnv("EDIT_ADMIN_AUTH_CENTER_USER_IDS", "")),
+		AdminAuthCenterUserIDs: parseList(getEnv("EDIT_ADMIN_AUTH_CENTER_USER_IDS", "")),
+
+		// 回收站：TRASH_RETENTION_DAYS=30，TRASH_PURGE_INTERVAL=1h
+		TrashRetentionDays: getEnvInt("TRASH_RETENTION_DAYS", 30),
+		TrashPurgeInterval: getEnvDuration("TRASH_PURGE_INTERVAL", time.Hour),
+
+		// 幂等键：IDEMPOTENCY_KEY_TTL=24h，IDEMPOTENCY_CLEANUP_INTERVAL=1h
+		IdempotencyKeyTTL:          getEnvDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		IdempotencyCleanupInterval: getEnvDuration("IDEMPOTENCY_CLEANUP_INTERVAL", time.Hour),
+
+		// 对象存储：STORAGE_DRIVER 未设置时，配置了七牛云密钥则使用 qiniu，否则使用 local
+		// 直传限制：STORAGE_UPLOAD_MAX_BYTES（默认 20MB），STORAGE_UPLOAD_MIME_TYPES=image/*,video/mp4
+		// STORAGE_CLEANUP_INTERVAL 为后台删除已永久删除笔记的归档和直传文件的间隔
+		StorageDriver:          getEnv("STORAGE_DRIVER", defaultStorageDriver()),
+		StorageLocalDir:        getEnv("STORAGE_LOCAL_DIR", "./data/media"),
+		StorageLocalBaseURL:    getEnv("STORAGE_LOCAL_BASE_URL", "/media"),
+		StorageLocalUploadURL:  getEnv("STORAGE_LOCAL_UPLOAD_URL", "/api/v1/storage/local/upload"),
+		StorageSigningSecret:   getEnv("STORAGE_SIGNING_SECRET", ""),
+		StorageUploadMaxBytes:  int64(getEnvInt("STORAGE_UPLOAD_MAX_BYTES", 20<<20)),
+		StorageUploadMimeTypes: parseList(getEnv("STORAGE_UPLOAD_MIME_TYPES", "image/*")),
+		StorageUploadTokenTTL:  getEnvDuration("STORAGE_UPLOAD_TOKEN_TTL", time.Hour),
+		StorageCleanupInterval: getEnvDuration("STORAGE_CLEANUP_INTERVAL", 10*time.Minute),
+		QiniuAccessKey:         getEnv("QINIU_ACCESS_KEY", ""),
+		QiniuSecretKey:         getEnv("QINIU_SECRET_KEY", ""),
+		QiniuBucket:            getEnv("QINIU_BUCKET", ""),
+		QiniuDomain:            getEnv("QINIU_DOMAIN", ""),
+		QiniuUploadURL:         getEnv("QINIU_UPLOAD_URL", "https://upload.qiniup.com"),
+		S3Endpoint:             getEnv("S3_ENDPOINT", ""),
+		S3Region:               getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:               getEnv("S3_BUCKET", ""),
+		S3AccessKey:            getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:            getEnv("S3_SECRET_KEY", ""),
+		S3PublicURL:            getEnv("S3_PUBLIC_URL", ""),
+		S3PathStyle:            getEnv("S3_PATH_STYLE", "false") == "true",
+
+		// 媒体归档：MEDIA_ARCHIVE_ENABLED=false 关闭；MEDIA_MAX_BYTES 为单个文件上限（默认 100MB）
+		// MEDIA_THUMBNAIL_SIZE 为图片缩略图最长边（默认 320），0 表示不生成缩略图和封面感知哈希
+		// MEDIA_ALLOWED_HOSTS 为允许下载的主机（逗号分隔，包含子域名），其他链接不会被请求
+		MediaArchiveEnabled:     getEnv("MEDIA_ARCHIVE_ENABLED", "true") == "true",
+		MediaMaxBytes:           int64(getEnvInt("MEDIA_MAX_BYTES", 100<<20)),
+		MediaThumbnailSize:      getEnvInt("MEDIA_THUMBNAIL_SIZE", 320),
+		MediaDownloadTimeout:    getEnvDuration("MEDIA_DOWNLOAD_TIMEOUT", 2*time.Minute),
+		MediaAllowedHosts:       parseList(getEnv("MEDIA_ALLOWED_HOSTS", "xhscdn.com,xiaohongshu.com")),
+		MediaArchiveInterval:    getEnvDuration("MEDIA_ARCHIVE_INTERVAL", time.Minute),
+		MediaArchiveBatchSize:   getEnvInt("MEDIA_ARCHIVE_BATCH_SIZE", 20),
+		MediaArchiveMaxAttempts: getEnvInt("MEDIA_ARCHIVE_MAX_ATTEMPTS", 5),
+		MediaArchiveRetryDelay:  getEnvDuration("MEDIA_ARCHIVE_RETRY_DELAY", 5*time.Minute),
+	}
+}
+
+// defaultStorageDriver 未指定存储驱动时的默认值：兼容只配置了七牛云密钥的部署
+func defaultStorageDriver() string {
+	if os.Getenv("QINIU_ACCESS_KEY") != "" {
+		return "qiniu"
 	}
+	return "local"
 }
 
-// parseAdminIDs 解析逗号分隔的 admin IDs
-func parseAdminIDs(s string) []string {
+// parseList 解析逗号分隔的列表（忽略空项）
+func parseList(s string) []string {
 	if s == "" {
 		return nil
 	}
 	parts := strings.Split(s, ",")
-	var ids []string
+	var items []string
 	for _, p := range parts {
 		p = strings.TrimSpace(p)
 		if p != "" {
-			ids = append(ids, p)
+			items = append(items, p)
 		}
 	}
-	return ids
+	return items
 }
 
 // GetDSN 获取数据库连接字符串
@@ -84,3 +182,23 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvInt 获取整数环境变量，不存在或格式错误时返回默认值
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+// getEnvDuration 获取时长环境变量（如 30m、1h），不存在或格式错误时返回默认值
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,90 @@
+// Package dbtest 为需要 PostgreSQL 的测试创建独立的 schema
+// 连接串取自 TEST_DATABASE_URL，未设置时跳过测试
+package dbtest
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keenchase/edit-business/internal/model"
+	"github.com/keenchase/edit-business/pkg/database"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Open 在新建的 schema 中迁移全部模型并返回连接，测试结束时删除该 schema
+func Open(t testing.TB) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	config := &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)}
+
+	admin, err := gorm.Open(postgres.Open(dsn), config)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+
+	db, err := gorm.Open(postgres.Open(withSearchPath(dsn, schema)), config)
+	if err != nil {
+		t.Fatalf("connect to schema %s: %v", schema, err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	models := append(database.Models(), &model.UserSettings{})
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// withSearchPath 让连接默认使用 schema（支持 URL 和 key=value 两种连接串）
+func withSearchPath(dsn, schema string) string {
+	if strings.Contains(dsn, "://") {
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		return dsn + sep + "search_path=" + schema
+	}
+	return dsn + " search_path=" + schema
+}
+
+// CreateUser 创建测试用户（账号中心 ID 与内部 ID 相同），并按 settings 写入采集设置
+// settings 为 nil 时开启采集，使用默认上限
+func CreateUser(t testing.TB, db *gorm.DB, id string, settings *model.UserSettings) *model.User {
+	t.Helper()
+	user := &model.User{ID: id, AuthCenterUserID: id}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	if settings == nil {
+		settings = &model.UserSettings{CollectionEnabled: true, CollectionDailyLimit: 500, CollectionBatchLimit: 50}
+	}
+	settings.UserID = id
+	if settings.Timezone == "" {
+		settings.Timezone = model.DefaultTimezone
+	}
+	if err := db.Create(settings).Error; err != nil {
+		t.Fatalf("create settings: %v", err)
+	}
+	return user
+}
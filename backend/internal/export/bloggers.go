@@ -0,0 +1,100 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/keenchase/edit-business/internal/model"
+)
+
+// bloggerColumns 表格导出列（与 API JSON 字段名一致，便于再次导入）
+var bloggerColumns = []string{
+	"id", "xhsId", "bloggerName", "avatarUrl", "description",
+	"followersCount", "followingCount", "likesCollectsCount", "ipLocation",
+	"verified", "verifiedLabel", "category", "bloggerUrl", "captureTimestamp", "createdAt",
+}
+
+func bloggerCells(b *model.Blogger) []interface{} {
+	return []interface{}{
+		b.ID, b.XhsID, b.BloggerName, b.AvatarURL, b.Description,
+		b.FollowersCount, b.FollowingCount, b.LikesCollectsCount, b.IPLocation,
+		b.Verified, b.VerifiedLabel, b.Category, b.BloggerURL, b.CaptureTimestamp, b.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// BloggerWriter 博主逐条导出
+type BloggerWriter interface {
+	Write(b *model.Blogger) error
+	Close() error
+}
+
+// NewBloggerWriter 创建博主导出写入器
+func NewBloggerWriter(format Format, w io.Writer) (BloggerWriter, error) {
+	switch format {
+	case FormatCSV, FormatXLSX:
+		t, err := newTableWriter(format, w, bloggerColumns)
+		if err != nil {
+			return nil, err
+		}
+		return &bloggerTableWriter{t: t}, nil
+	case FormatJSONL:
+		return &bloggerJSONLWriter{j: newJSONLWriter(w)}, nil
+	case FormatMarkdown:
+		return &bloggerMarkdownWriter{m: newMarkdownZip(w)}, nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+type bloggerTableWriter struct{ t tableWriter }
+
+func (w *bloggerTableWriter) Write(b *model.Blogger) error { return w.t.WriteRow(bloggerCells(b)) }
+func (w *bloggerTableWriter) Close() error                 { return w.t.Close() }
+
+type bloggerJSONLWriter struct{ j *jsonlWriter }
+
+func (w *bloggerJSONLWriter) Write(b *model.Blogger) error { return w.j.write(b) }
+func (w *bloggerJSONLWriter) Close() error                 { return nil }
+
+type bloggerMarkdownWriter struct{ m *markdownZip }
+
+func (w *bloggerMarkdownWriter) Write(b *model.Blogger) error {
+	return w.m.writeFile(b.BloggerName, b.ID, bloggerMarkdown(b))
+}
+func (w *bloggerMarkdownWriter) Close() error { return w.m.close() }
+
+// bloggerMarkdown 渲染单个博主：front-matter + 名称 + 简介
+func bloggerMarkdown(b *model.Blogger) string {
+	fm := newFrontMatter()
+	fm.field("id", b.ID)
+	fm.field("xhsId", b.XhsID)
+	fm.field("bloggerName", b.BloggerName)
+	fm.field("followersCount", b.FollowersCount)
+	fm.field("followingCount", b.FollowingCount)
+	fm.field("likesCollectsCount", b.LikesCollectsCount)
+	fm.field("ipLocation", b.IPLocation)
+	fm.field("verified", b.Verified)
+	fm.field("verifiedLabel", b.VerifiedLabel)
+	fm.field("category", b.Category)
+	fm.field("bloggerUrl", b.BloggerURL)
+	fm.field("avatarUrl", b.AvatarURL)
+	fm.field("capturedAt", formatMillis(b.CaptureTimestamp))
+
+	var s strings.Builder
+	s.WriteString(fm.String())
+	s.WriteString("\n")
+	fmt.Fprintf(&s, "# %s\n\n", b.BloggerName)
+	if b.AvatarURL != "" {
+		fmt.Fprintf(&s, "![头像](%s)\n\n", b.AvatarURL)
+	}
+	if b.Description != "" {
+		s.WriteString(b.Description)
+		s.WriteString("\n\n")
+	}
+	if b.BloggerURL != "" {
+		fmt.Fprintf(&s, "主页：%s\n", b.BloggerURL)
+	}
+	return s.String()
+}
@@ -0,0 +1,49 @@
+// Package export 笔记/博主数据流式导出（CSV / XLSX / JSONL / Markdown）
+// 所有写入器逐行写出，不在内存中缓存整个结果集
+package export
+
+import "errors"
+
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// Format 导出格式
+type Format string
+
+const (
+	FormatCSV      Format = "csv"
+	FormatXLSX     Format = "xlsx"
+	FormatJSONL    Format = "jsonl"
+	FormatMarkdown Format = "md" // 每条记录一个 Markdown 文件，打包为 zip
+)
+
+// ParseFormat 解析导出格式
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case FormatCSV, FormatXLSX, FormatJSONL, FormatMarkdown:
+		return f, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// ContentType 响应的 Content-Type
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatJSONL:
+		return "application/x-ndjson; charset=utf-8"
+	default:
+		return "application/zip"
+	}
+}
+
+// FileExtension 下载文件扩展名
+func (f Format) FileExtension() string {
+	if f == FormatMarkdown {
+		return "zip"
+	}
+	return string(f)
+}
@@ -0,0 +1,21 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// jsonlWriter 每行一个 JSON 对象
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) *jsonlWriter {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &jsonlWriter{enc: enc}
+}
+
+func (j *jsonlWriter) write(v interface{}) error {
+	return j.enc.Encode(v)
+}
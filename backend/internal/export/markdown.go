@@ -0,0 +1,104 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// markdownZip 将每条记录写为一个 Markdown 文件并打包为 zip
+type markdownZip struct {
+	zw    *zip.Writer
+	names map[string]int
+}
+
+func newMarkdownZip(w io.Writer) *markdownZip {
+	return &markdownZip{zw: zip.NewWriter(w), names: make(map[string]int)}
+}
+
+// unsafeFileChars 文件名中不允许的字符
+var unsafeFileChars = regexp.MustCompile(`[\\/:*?"<>|\x00-\x1f]+`)
+
+// maxFileNameRunes 文件名（不含扩展名）最大长度
+const maxFileNameRunes = 60
+
+// writeFile 写入一个 Markdown 文件，文件名由 title 生成，重名时追加序号
+func (m *markdownZip) writeFile(title, fallback, content string) error {
+	name := strings.TrimSpace(unsafeFileChars.ReplaceAllString(title, "_"))
+	if r := []rune(name); len(r) > maxFileNameRunes {
+		name = string(r[:maxFileNameRunes])
+	}
+	if name == "" {
+		name = fallback
+	}
+	m.names[name]++
+	if n := m.names[name]; n > 1 {
+		name = fmt.Sprintf("%s (%d)", name, n)
+	}
+
+	f, err := m.zw.CreateHeader(&zip.FileHeader{
+		Name:     name + ".md",
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(f, content)
+	return err
+}
+
+func (m *markdownZip) close() error {
+	return m.zw.Close()
+}
+
+// frontMatter 生成 YAML front-matter，字符串值使用 JSON 引号形式（合法的 YAML 双引号标量）
+type frontMatter struct {
+	b strings.Builder
+}
+
+func newFrontMatter() *frontMatter {
+	fm := &frontMatter{}
+	fm.b.WriteString("---\n")
+	return fm
+}
+
+func (fm *frontMatter) field(key string, value interface{}) {
+	fmt.Fprintf(&fm.b, "%s: %s\n", key, yamlScalar(value))
+}
+
+func (fm *frontMatter) list(key string, values []string) {
+	if len(values) == 0 {
+		fmt.Fprintf(&fm.b, "%s: []\n", key)
+		return
+	}
+	fmt.Fprintf(&fm.b, "%s:\n", key)
+	for _, v := range values {
+		fmt.Fprintf(&fm.b, "  - %s\n", yamlScalar(v))
+	}
+}
+
+// yamlScalar 以 JSON 形式输出标量（不转义 HTML 字符）
+func yamlScalar(v interface{}) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(v)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (fm *frontMatter) String() string {
+	return fm.b.String() + "---\n"
+}
+
+// formatMillis 毫秒时间戳转 RFC3339，0 返回空字符串
+func formatMillis(ms int64) string {
+	if ms == 0 {
+		return ""
+	}
+	return time.UnixMilli(ms).Format(time.RFC3339)
+}
@@ -0,0 +1,119 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/keenchase/edit-business/internal/model"
+)
+
+// noteColumns 表格导出列（与 API JSON 字段名一致，便于再次导入）
+var noteColumns = []string{
+	"id", "url", "title", "author", "authorXhsId", "content", "tags", "noteType",
+	"likes", "collects", "comments", "publishDate", "source", "captureTimestamp",
+	"coverImageUrl", "imageUrls", "videoUrl", "createdAt",
+}
+
+// listSeparator 表格中数组字段的分隔符
+const listSeparator = "\n"
+
+func noteCells(n *model.Note) []interface{} {
+	videoURL := ""
+	if n.VideoURL != nil {
+		videoURL = *n.VideoURL
+	}
+	return []interface{}{
+		n.ID, n.URL, n.Title, n.Author, n.AuthorXhsID, n.Content, strings.Join(n.Tags, listSeparator), n.NoteType,
+		n.Likes, n.Collects, n.Comments, n.PublishDate, n.Source, n.CaptureTimestamp,
+		n.CoverImageURL, strings.Join(n.ImageURLs, listSeparator), videoURL, n.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// NoteWriter 笔记逐条导出
+type NoteWriter interface {
+	Write(n *model.Note) error
+	Close() error
+}
+
+// NewNoteWriter 创建笔记导出写入器
+func NewNoteWriter(format Format, w io.Writer) (NoteWriter, error) {
+	switch format {
+	case FormatCSV, FormatXLSX:
+		t, err := newTableWriter(format, w, noteColumns)
+		if err != nil {
+			return nil, err
+		}
+		return &noteTableWriter{t: t}, nil
+	case FormatJSONL:
+		return &noteJSONLWriter{j: newJSONLWriter(w)}, nil
+	case FormatMarkdown:
+		return &noteMarkdownWriter{m: newMarkdownZip(w)}, nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+type noteTableWriter struct{ t tableWriter }
+
+func (w *noteTableWriter) Write(n *model.Note) error { return w.t.WriteRow(noteCells(n)) }
+func (w *noteTableWriter) Close() error              { return w.t.Close() }
+
+type noteJSONLWriter struct{ j *jsonlWriter }
+
+func (w *noteJSONLWriter) Write(n *model.Note) error { return w.j.write(n) }
+func (w *noteJSONLWriter) Close() error              { return nil }
+
+type noteMarkdownWriter struct{ m *markdownZip }
+
+func (w *noteMarkdownWriter) Write(n *model.Note) error {
+	return w.m.writeFile(n.Title, n.ID, noteMarkdown(n))
+}
+func (w *noteMarkdownWriter) Close() error { return w.m.close() }
+
+// noteMarkdown 渲染单篇笔记：front-matter + 标题 + 正文 + 图片/视频
+func noteMarkdown(n *model.Note) string {
+	fm := newFrontMatter()
+	fm.field("id", n.ID)
+	fm.field("url", n.URL)
+	fm.field("title", n.Title)
+	fm.field("author", n.Author)
+	if n.AuthorXhsID != "" {
+		fm.field("authorXhsId", n.AuthorXhsID)
+	}
+	fm.list("tags", n.Tags)
+	fm.field("noteType", n.NoteType)
+	fm.field("likes", n.Likes)
+	fm.field("collects", n.Collects)
+	fm.field("comments", n.Comments)
+	fm.field("publishDate", formatMillis(n.PublishDate))
+	fm.field("capturedAt", formatMillis(n.CaptureTimestamp))
+	fm.field("source", n.Source)
+	fm.field("coverImageUrl", n.CoverImageURL)
+	fm.list("imageUrls", n.ImageURLs)
+	if n.VideoURL != nil && *n.VideoURL != "" {
+		fm.field("videoUrl", *n.VideoURL)
+	}
+
+	var b strings.Builder
+	b.WriteString(fm.String())
+	b.WriteString("\n")
+	if n.Title != "" {
+		fmt.Fprintf(&b, "# %s\n\n", n.Title)
+	}
+	if n.Content != "" {
+		b.WriteString(n.Content)
+		b.WriteString("\n\n")
+	}
+	for i, url := range n.ImageURLs {
+		fmt.Fprintf(&b, "![图片%d](%s)\n", i+1, url)
+	}
+	if n.VideoURL != nil && *n.VideoURL != "" {
+		fmt.Fprintf(&b, "\n[视频](%s)\n", *n.VideoURL)
+	}
+	if n.URL != "" {
+		fmt.Fprintf(&b, "\n原文：%s\n", n.URL)
+	}
+	return b.String()
+}
@@ -0,0 +1,69 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// tableWriter 表格类格式（CSV / XLSX）的逐行写入器
+// 单元格值支持 string、int32、int64，其余类型按 fmt.Sprint 输出
+type tableWriter interface {
+	WriteRow(cells []interface{}) error
+	Close() error
+}
+
+// newTableWriter 创建表格写入器并写入表头
+func newTableWriter(format Format, w io.Writer, header []string) (tableWriter, error) {
+	var t tableWriter
+	switch format {
+	case FormatCSV:
+		cw, err := newCSVTable(w)
+		if err != nil {
+			return nil, err
+		}
+		t = cw
+	case FormatXLSX:
+		xw, err := newXLSXTable(w)
+		if err != nil {
+			return nil, err
+		}
+		t = xw
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	cells := make([]interface{}, len(header))
+	for i, h := range header {
+		cells[i] = h
+	}
+	if err := t.WriteRow(cells); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// csvTable CSV 写入器，带 UTF-8 BOM 以便 Excel 正确识别中文
+type csvTable struct {
+	w *csv.Writer
+}
+
+func newCSVTable(w io.Writer) (*csvTable, error) {
+	if _, err := w.Write([]byte("\xEF\xBB\xBF")); err != nil {
+		return nil, err
+	}
+	return &csvTable{w: csv.NewWriter(w)}, nil
+}
+
+func (t *csvTable) WriteRow(cells []interface{}) error {
+	record := make([]string, len(cells))
+	for i, v := range cells {
+		record[i] = fmt.Sprint(v)
+	}
+	return t.w.Write(record)
+}
+
+func (t *csvTable) Close() error {
+	t.w.Flush()
+	return t.w.Error()
+}
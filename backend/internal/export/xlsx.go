@@ -0,0 +1,129 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// xlsxMaxCellRunes Excel 单元格最大字符数
+const xlsxMaxCellRunes = 32767
+
+// xlsx 固定部件：单工作表、内联字符串，不依赖 sharedStrings，便于逐行写出
+var xlsxStaticParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// xlsxTable 流式 XLSX 写入器
+// zip 条目按顺序写出，工作表在最后打开并逐行追加
+type xlsxTable struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+	row   int
+}
+
+func newXLSXTable(w io.Writer) (*xlsxTable, error) {
+	zw := zip.NewWriter(w)
+	for _, part := range xlsxStaticParts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(f)
+	if _, err := sheet.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return nil, err
+	}
+	return &xlsxTable{zw: zw, sheet: sheet}, nil
+}
+
+func (t *xlsxTable) WriteRow(cells []interface{}) error {
+	t.row++
+	fmt.Fprintf(t.sheet, `<row r="%d">`, t.row)
+	for i, v := range cells {
+		ref := xlsxColumn(i) + strconv.Itoa(t.row)
+		switch n := v.(type) {
+		case int32:
+			fmt.Fprintf(t.sheet, `<c r="%s"><v>%d</v></c>`, ref, n)
+		case int64:
+			fmt.Fprintf(t.sheet, `<c r="%s"><v>%d</v></c>`, ref, n)
+		default:
+			fmt.Fprintf(t.sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+			if err := xml.EscapeText(t.sheet, []byte(xlsxText(fmt.Sprint(v)))); err != nil {
+				return err
+			}
+			t.sheet.WriteString(`</t></is></c>`)
+		}
+	}
+	_, err := t.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (t *xlsxTable) Close() error {
+	if _, err := t.sheet.WriteString(`</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	if err := t.sheet.Flush(); err != nil {
+		return err
+	}
+	return t.zw.Close()
+}
+
+// xlsxColumn 列序号（从 0 开始）转列名：0 -> A, 26 -> AA
+func xlsxColumn(i int) string {
+	name := ""
+	for i >= 0 {
+		name = string(rune('A'+i%26)) + name
+		i = i/26 - 1
+	}
+	return name
+}
+
+// xlsxText 去除 XML 1.0 不允许的控制字符，并按单元格上限截断
+func xlsxText(s string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range s {
+		if r < 0x20 && r != '\t' && r != '\n' && r != '\r' {
+			continue
+		}
+		if r == 0xFFFE || r == 0xFFFF {
+			continue
+		}
+		if n >= xlsxMaxCellRunes {
+			break
+		}
+		b.WriteRune(r)
+		n++
+	}
+	return b.String()
+}
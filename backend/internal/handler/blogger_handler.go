@@ -1,8 +1,12 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
 	"strconv"
 
+	"github.com/keenchase/edit-business/internal/export"
+	"github.com/keenchase/edit-business/internal/repository"
 	"github.com/keenchase/edit-business/internal/service"
 	"github.com/gin-gonic/gin"
 )
@@ -19,7 +23,7 @@ func NewBloggerHandler(bloggerService *service.BloggerService) *BloggerHandler {
 
 // Create 创建博主信息
 // @Summary 创建博主信息
-// @Description 创建新的博主记录
+// @Description 采集博主记录；当前用户已采集过该 xhsId 时更新原记录并追加一条粉丝快照（不同用户的博主记录相互独立）
 // @Tags bloggers
 // @Accept json
 // @Produce json
@@ -44,12 +48,9 @@ func (h *BloggerHandler) Create(c *gin.Context) {
 	}
 
 	blogger, err := h.bloggerService.Create(authCenterUserID.(string), &req)
+	setQuotaHeaders(c, h.bloggerService.Quota, authCenterUserID.(string), service.QuotaBloggers)
 	if err != nil {
-		if err == service.ErrCollectionDisabled {
-			c.JSON(403, Response{
-				Code:    403,
-				Message: "采集功能已关闭，请在网站设置中开启",
-			})
+		if collectionLimitError(c, err) {
 			return
 		}
 		InternalError(c, err.Error())
@@ -87,6 +88,7 @@ func (h *BloggerHandler) GetByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, blogger.Version)
 	SuccessResponse(c, blogger)
 }
 
@@ -123,12 +125,24 @@ func (h *BloggerHandler) GetByXhsID(c *gin.Context) {
 
 // List 获取博主列表（按用户隔离）
 // @Summary 获取博主列表
-// @Description 分页获取博主列表，按粉丝数排序
+// @Description 分页获取博主列表，支持关键词搜索、组合筛选和排序，默认按粉丝数倒序
 // @Tags bloggers
 // @Accept json
 // @Produce json
 // @Param page query int false "页码" default(1)
 // @Param size query int false "每页数量" default(20)
+// @Param q query string false "关键词，匹配名称、简介或小红书号"
+// @Param followersMin query int false "最少粉丝数"
+// @Param followersMax query int false "最多粉丝数"
+// @Param captureFrom query int false "最近采集时间起（毫秒时间戳）"
+// @Param captureTo query int false "最近采集时间止（毫秒时间戳）"
+// @Param verified query bool false "是否认证"
+// @Param category query string false "博主分类"
+// @Param hasNotes query bool false "是否有已采集的笔记"
+// @Param sort query string false "排序字段 followersCount / followingCount / likesCollectsCount / captureTimestamp（最近采集）/ createdAt / updatedAt" default(followersCount)
+// @Param order query string false "排序方向 asc / desc" default(desc)
+// @Param cursor query string false "游标分页：传入上一页的 nextCursor，首页传空值"
+// @Param withTotal query bool false "游标模式下是否返回总数" default(false)
 // @Success 200 {object} Response
 // @Router /api/v1/bloggers [get]
 func (h *BloggerHandler) List(c *gin.Context) {
@@ -149,9 +163,25 @@ func (h *BloggerHandler) List(c *gin.Context) {
 			req.Size = size
 		}
 	}
+	if err := bindBloggerFilter(c, &req.BloggerFilter); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	req.Sort = c.Query("sort")
+	req.Order = c.Query("order")
+	req.Cursor, req.UseCursor = c.GetQuery("cursor")
+	req.WithTotal = c.Query("withTotal") == "true"
 
 	result, err := h.bloggerService.List(authCenterUserID.(string), &req)
 	if err != nil {
+		if err == repository.ErrInvalidSort {
+			BadRequest(c, "invalid sort or order")
+			return
+		}
+		if err == repository.ErrInvalidCursor {
+			BadRequest(c, "invalid cursor")
+			return
+		}
 		InternalError(c, err.Error())
 		return
 	}
@@ -159,18 +189,90 @@ func (h *BloggerHandler) List(c *gin.Context) {
 	SuccessResponse(c, result)
 }
 
-// BatchCreate 批量创建博主信息
-// @Summary 批量创建博主信息
-// @Description 批量创建博主记录（用于 Chrome 插件同步）
+// bindBloggerFilter 从查询参数解析博主组合筛选条件
+func bindBloggerFilter(c *gin.Context, f *repository.BloggerFilter) error {
+	f.Keyword = c.Query("q")
+	f.Category = c.Query("category")
+
+	var err error
+	if f.FollowersMin, err = queryInt32Ptr(c, "followersMin"); err != nil {
+		return err
+	}
+	if f.FollowersMax, err = queryInt32Ptr(c, "followersMax"); err != nil {
+		return err
+	}
+	if f.CaptureFrom, err = queryInt64Ptr(c, "captureFrom"); err != nil {
+		return err
+	}
+	if f.CaptureTo, err = queryInt64Ptr(c, "captureTo"); err != nil {
+		return err
+	}
+	if f.Verified, err = queryBoolPtr(c, "verified"); err != nil {
+		return err
+	}
+	if f.HasNotes, err = queryBoolPtr(c, "hasNotes"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Export 导出博主（按当前用户隔离）
+// @Summary 导出博主
+// @Description 按列表接口的筛选/排序条件流式导出博主，md 格式为每个博主一个 Markdown 文件的 zip 包
+// @Tags bloggers
+// @Produce octet-stream
+// @Param format query string true "导出格式 csv / xlsx / jsonl / md"
+// @Success 200 {file} file
+// @Router /api/v1/bloggers/export [get]
+func (h *BloggerHandler) Export(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	format, err := export.ParseFormat(c.Query("format"))
+	if err != nil {
+		BadRequest(c, "format must be one of csv, xlsx, jsonl, md")
+		return
+	}
+
+	var req service.ListBloggersRequest
+	if err := bindBloggerFilter(c, &req.BloggerFilter); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	req.Sort = c.Query("sort")
+	req.Order = c.Query("order")
+
+	run, err := h.bloggerService.ExportBloggers(authCenterUserID.(string), &req, format)
+	if err != nil {
+		if err == repository.ErrInvalidSort {
+			BadRequest(c, "invalid sort or order")
+			return
+		}
+		InternalError(c, err.Error())
+		return
+	}
+
+	streamExport(c, format, "bloggers", run)
+}
+
+// BatchCreate 批量创建博主
+// @Summary 批量创建博主
+// @Description 按 xhsId 批量创建或更新博主记录（用于 Chrome 插件同步），返回每条的写入结果：created / updated / rejected（附原因）；mode=atomic 时任一条被拒绝整批回滚并返回 422
 // @Tags bloggers
 // @Accept json
 // @Produce json
+// @Param mode query string false "atomic 或 bestEffort（默认）"
 // @Param request body []service.CreateBloggerRequest true "批量创建博主请求"
 // @Success 200 {object} Response
+// @Failure 422 {object} Response
 // @Router /api/v1/bloggers/batch [post]
 func (h *BloggerHandler) BatchCreate(c *gin.Context) {
+	// 不使用 ShouldBindJSON：单条字段缺失时应在结果中标记为 rejected，而不是拒绝整个请求
 	var reqs []*service.CreateBloggerRequest
-	if err := c.ShouldBindJSON(&reqs); err != nil {
+	if err := json.NewDecoder(c.Request.Body).Decode(&reqs); err != nil {
 		BadRequest(c, err.Error())
 		return
 	}
@@ -185,23 +287,30 @@ func (h *BloggerHandler) BatchCreate(c *gin.Context) {
 		return
 	}
 
-	err := h.bloggerService.BatchCreate(authCenterUserID.(string), reqs)
+	result, err := h.bloggerService.BatchCreate(authCenterUserID.(string), reqs, c.Query("mode"))
+	setQuotaHeaders(c, h.bloggerService.Quota, authCenterUserID.(string), service.QuotaBloggers)
 	if err != nil {
-		if err == service.ErrCollectionDisabled {
-			c.JSON(403, Response{
-				Code:    403,
-				Message: "采集功能已关闭，请在网站设置中开启",
-			})
+		if err == service.ErrInvalidBatchMode {
+			BadRequest(c, err.Error())
+			return
+		}
+		if collectionLimitError(c, err) {
 			return
 		}
 		InternalError(c, err.Error())
 		return
 	}
 
-	SuccessResponse(c, gin.H{
-		"count":  len(reqs),
-		"status": "success",
-	})
+	if !result.Committed {
+		c.JSON(422, Response{
+			Code:    422,
+			Message: "batch rolled back",
+			Data:    result,
+		})
+		return
+	}
+
+	SuccessResponse(c, result)
 }
 
 // UpsertByXhsID 根据 xhs_id 插入或更新博主信息
@@ -231,12 +340,9 @@ func (h *BloggerHandler) UpsertByXhsID(c *gin.Context) {
 	}
 
 	blogger, err := h.bloggerService.UpsertByXhsID(authCenterUserID.(string), &req)
+	setQuotaHeaders(c, h.bloggerService.Quota, authCenterUserID.(string), service.QuotaBloggers)
 	if err != nil {
-		if err == service.ErrCollectionDisabled {
-			c.JSON(403, Response{
-				Code:    403,
-				Message: "采集功能已关闭，请在网站设置中开启",
-			})
+		if collectionLimitError(c, err) {
 			return
 		}
 		InternalError(c, err.Error())
@@ -246,15 +352,18 @@ func (h *BloggerHandler) UpsertByXhsID(c *gin.Context) {
 	SuccessResponse(c, blogger)
 }
 
-// Update 更新博主信息（校验归属）
+// Update 部分更新博主信息（校验归属）
 // @Summary 更新博主信息
-// @Description 更新博主信息
+// @Description 按 JSON Merge Patch 语义更新可编辑字段（bloggerName / avatarUrl / description / bloggerUrl），值为 null 表示清空；必须携带 If-Match（GET 返回的 ETag），缺失返回 428，版本不一致返回 412，If-Match: * 表示不做版本校验直接覆盖
 // @Tags bloggers
 // @Accept json
 // @Produce json
 // @Param id path string true "博主 ID"
-// @Param request body model.Blogger true "博主信息"
+// @Param If-Match header string true "博主的 ETag（版本号），* 表示不校验"
+// @Param request body object true "需要修改的字段"
 // @Success 200 {object} Response
+// @Failure 412 {object} Response
+// @Failure 428 {object} Response
 // @Router /api/v1/bloggers/{id} [put]
 func (h *BloggerHandler) Update(c *gin.Context) {
 	authCenterUserID, exists := c.Get("authCenterUserID")
@@ -269,35 +378,40 @@ func (h *BloggerHandler) Update(c *gin.Context) {
 		return
 	}
 
-	blogger, err := h.bloggerService.GetByID(authCenterUserID.(string), id)
+	ifMatch, err := ifMatchVersion(c)
 	if err != nil {
-		NotFound(c, "blogger not found")
+		ifMatchError(c, err)
 		return
 	}
 
-	var req map[string]interface{}
-	if err := c.ShouldBindJSON(&req); err != nil {
+	var patch map[string]json.RawMessage
+	if err := c.ShouldBindJSON(&patch); err != nil {
 		BadRequest(c, err.Error())
 		return
 	}
-	_ = req
 
-	err = h.bloggerService.Update(authCenterUserID.(string), blogger)
+	blogger, err := h.bloggerService.Patch(authCenterUserID.(string), id, patch, ifMatch)
 	if err != nil {
-		if err == service.ErrBloggerNotFound {
+		switch {
+		case errors.Is(err, service.ErrInvalidPatch):
+			BadRequest(c, err.Error())
+		case errors.Is(err, repository.ErrVersionConflict):
+			ErrorResponse(c, 412, "blogger has been modified, reload and retry")
+		case errors.Is(err, service.ErrBloggerNotFound):
 			NotFound(c, "blogger not found")
-			return
+		default:
+			InternalError(c, err.Error())
 		}
-		InternalError(c, err.Error())
 		return
 	}
 
+	setETag(c, blogger.Version)
 	SuccessResponse(c, blogger)
 }
 
 // Delete 删除博主信息（校验归属）
 // @Summary 删除博主信息
-// @Description 根据 ID 删除博主
+// @Description 根据 ID 删除博主（移入回收站，可恢复）
 // @Tags bloggers
 // @Accept json
 // @Produce json
@@ -328,3 +442,85 @@ func (h *BloggerHandler) Delete(c *gin.Context) {
 		"status": "deleted",
 	})
 }
+
+// ListNotes 获取博主已采集的笔记（校验归属）
+// @Summary 博主的笔记
+// @Description 按笔记的作者小红书 ID 关联，返回该博主已采集的笔记及汇总数据（笔记数、平均互动、点赞最多的笔记）
+// @Tags bloggers
+// @Produce json
+// @Param id path string true "博主 ID"
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(20)
+// @Param sort query string false "排序字段" default(captureTimestamp)
+// @Param order query string false "排序方向 asc / desc" default(desc)
+// @Success 200 {object} Response
+// @Router /api/v1/bloggers/{id}/notes [get]
+func (h *BloggerHandler) ListNotes(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	var req service.ListBloggerNotesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.bloggerService.ListNotes(authCenterUserID.(string), c.Param("id"), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrBloggerNotFound):
+			NotFound(c, "blogger not found")
+		case errors.Is(err, repository.ErrInvalidSort):
+			BadRequest(c, "invalid sort or order")
+		default:
+			InternalError(c, err.Error())
+		}
+		return
+	}
+
+	SuccessResponse(c, result)
+}
+
+// GetHistory 获取博主粉丝数据历史（校验归属）
+// @Summary 获取博主粉丝历史
+// @Description 返回博主每次采集时的粉丝数、关注数、获赞与收藏快照，以及 7d/30d 增长和最近 30 天的粉丝趋势（growing / stagnant / declining / unknown）
+// @Tags bloggers
+// @Produce json
+// @Param id path string true "博主 ID"
+// @Param from query int false "起始时间（毫秒时间戳）"
+// @Param to query int false "结束时间（毫秒时间戳）"
+// @Success 200 {object} Response
+// @Router /api/v1/bloggers/{id}/history [get]
+func (h *BloggerHandler) GetHistory(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	from, err := parseMillisQuery(c, "from")
+	if err != nil {
+		BadRequest(c, "invalid from")
+		return
+	}
+	to, err := parseMillisQuery(c, "to")
+	if err != nil {
+		BadRequest(c, "invalid to")
+		return
+	}
+
+	result, err := h.bloggerService.GetHistory(authCenterUserID.(string), c.Param("id"), from, to)
+	if err != nil {
+		if errors.Is(err, service.ErrBloggerNotFound) {
+			NotFound(c, "blogger not found")
+			return
+		}
+		InternalError(c, err.Error())
+		return
+	}
+
+	SuccessResponse(c, result)
+}
@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/keenchase/edit-business/internal/repository"
+	"github.com/keenchase/edit-business/internal/service"
+)
+
+// CollectionHandler 收藏夹处理器
+type CollectionHandler struct {
+	collectionService *service.CollectionService
+}
+
+// NewCollectionHandler 创建收藏夹处理器实例
+func NewCollectionHandler(collectionService *service.CollectionService) *CollectionHandler {
+	return &CollectionHandler{collectionService: collectionService}
+}
+
+// collectionError 把收藏夹服务的错误转换为响应
+func collectionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidCollection), errors.Is(err, service.ErrInvalidPatch):
+		BadRequest(c, err.Error())
+	case errors.Is(err, service.ErrCollectionNameTaken):
+		ErrorResponse(c, 409, err.Error())
+	case errors.Is(err, service.ErrCollectionNotFound):
+		NotFound(c, "collection not found")
+	case errors.Is(err, repository.ErrNoteNotInCollection):
+		NotFound(c, err.Error())
+	default:
+		InternalError(c, err.Error())
+	}
+}
+
+// List 获取收藏夹列表
+// @Summary 收藏夹列表
+// @Description 获取当前用户的全部收藏夹及其笔记数
+// @Tags collections
+// @Produce json
+// @Success 200 {object} Response
+// @Router /api/v1/collections [get]
+func (h *CollectionHandler) List(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	collections, err := h.collectionService.List(authCenterUserID.(string))
+	if err != nil {
+		collectionError(c, err)
+		return
+	}
+
+	SuccessResponse(c, collections)
+}
+
+// Create 创建收藏夹
+// @Summary 创建收藏夹
+// @Description 创建收藏夹，同一用户下名称不能重复（重复返回 409）
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param request body service.CreateCollectionRequest true "创建收藏夹请求"
+// @Success 200 {object} Response
+// @Failure 409 {object} Response
+// @Router /api/v1/collections [post]
+func (h *CollectionHandler) Create(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	var req service.CreateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	collection, err := h.collectionService.Create(authCenterUserID.(string), &req)
+	if err != nil {
+		collectionError(c, err)
+		return
+	}
+
+	SuccessResponse(c, collection)
+}
+
+// GetByID 获取收藏夹详情（校验归属）
+// @Summary 获取收藏夹详情
+// @Tags collections
+// @Produce json
+// @Param id path string true "收藏夹 ID"
+// @Success 200 {object} Response
+// @Router /api/v1/collections/{id} [get]
+func (h *CollectionHandler) GetByID(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	collection, err := h.collectionService.Get(authCenterUserID.(string), c.Param("id"))
+	if err != nil {
+		collectionError(c, err)
+		return
+	}
+
+	SuccessResponse(c, collection)
+}
+
+// Update 部分更新收藏夹（校验归属）
+// @Summary 更新收藏夹
+// @Description 按 JSON Merge Patch 语义更新 name / description
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param id path string true "收藏夹 ID"
+// @Param request body object true "需要修改的字段"
+// @Success 200 {object} Response
+// @Router /api/v1/collections/{id} [put]
+func (h *CollectionHandler) Update(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	var patch map[string]json.RawMessage
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	collection, err := h.collectionService.Patch(authCenterUserID.(string), c.Param("id"), patch)
+	if err != nil {
+		collectionError(c, err)
+		return
+	}
+
+	SuccessResponse(c, collection)
+}
+
+// Delete 删除收藏夹（校验归属）
+// @Summary 删除收藏夹
+// @Description 删除收藏夹，收藏夹中的笔记不受影响
+// @Tags collections
+// @Produce json
+// @Param id path string true "收藏夹 ID"
+// @Success 200 {object} Response
+// @Router /api/v1/collections/{id} [delete]
+func (h *CollectionHandler) Delete(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	id := c.Param("id")
+	if err := h.collectionService.Delete(authCenterUserID.(string), id); err != nil {
+		collectionError(c, err)
+		return
+	}
+
+	SuccessResponse(c, gin.H{
+		"id":     id,
+		"status": "deleted",
+	})
+}
+
+// ListNotes 获取收藏夹中的笔记
+// @Summary 收藏夹笔记列表
+// @Description 按收藏夹内顺序分页获取笔记（不含回收站中的笔记）
+// @Tags collections
+// @Produce json
+// @Param id path string true "收藏夹 ID"
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(20)
+// @Success 200 {object} Response
+// @Router /api/v1/collections/{id}/notes [get]
+func (h *CollectionHandler) ListNotes(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.Query("page"))
+	size, _ := strconv.Atoi(c.Query("size"))
+
+	result, err := h.collectionService.ListNotes(authCenterUserID.(string), c.Param("id"), page, size)
+	if err != nil {
+		collectionError(c, err)
+		return
+	}
+
+	SuccessResponse(c, result)
+}
+
+// AddNotes 把笔记加入收藏夹
+// @Summary 加入收藏夹
+// @Description 把笔记追加到收藏夹末尾，已在收藏夹中的笔记保持原位置；不存在或不属于当前用户的笔记在 notFound 中返回
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param id path string true "收藏夹 ID"
+// @Param request body service.CollectionNoteIDsRequest true "笔记 ID 列表"
+// @Success 200 {object} Response
+// @Router /api/v1/collections/{id}/notes [post]
+func (h *CollectionHandler) AddNotes(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	var req service.CollectionNoteIDsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.collectionService.AddNotes(authCenterUserID.(string), c.Param("id"), req.NoteIDs)
+	if err != nil {
+		collectionError(c, err)
+		return
+	}
+
+	SuccessResponse(c, result)
+}
+
+// RemoveNote 把笔记移出收藏夹
+// @Summary 移出收藏夹
+// @Description 把笔记移出收藏夹，笔记本身不受影响
+// @Tags collections
+// @Produce json
+// @Param id path string true "收藏夹 ID"
+// @Param noteId path string true "笔记 ID"
+// @Success 200 {object} Response
+// @Router /api/v1/collections/{id}/notes/{noteId} [delete]
+func (h *CollectionHandler) RemoveNote(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	noteID := c.Param("noteId")
+	if err := h.collectionService.RemoveNote(authCenterUserID.(string), c.Param("id"), noteID); err != nil {
+		collectionError(c, err)
+		return
+	}
+
+	SuccessResponse(c, gin.H{
+		"noteId": noteID,
+		"status": "removed",
+	})
+}
+
+// Reorder 调整收藏夹内笔记顺序
+// @Summary 调整收藏夹顺序
+// @Description noteIds 依次排在最前，其余笔记保持原有相对顺序排在其后；noteIds 中有不在收藏夹中的笔记时返回 404
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param id path string true "收藏夹 ID"
+// @Param request body service.CollectionNoteIDsRequest true "笔记 ID 顺序"
+// @Success 200 {object} Response
+// @Router /api/v1/collections/{id}/notes/order [put]
+func (h *CollectionHandler) Reorder(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	var req service.CollectionNoteIDsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.collectionService.Reorder(authCenterUserID.(string), c.Param("id"), req.NoteIDs); err != nil {
+		collectionError(c, err)
+		return
+	}
+
+	SuccessResponse(c, nil)
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/keenchase/edit-business/internal/service"
+)
+
+// collectionLimitError 处理采集开关关闭和超出采集上限的错误，已写入响应时返回 true
+func collectionLimitError(c *gin.Context, err error) bool {
+	if errors.Is(err, service.ErrCollectionDisabled) {
+		c.JSON(403, Response{
+			Code:    403,
+			Message: "采集功能已关闭，请在网站设置中开启",
+		})
+		return true
+	}
+
+	var quotaErr *service.QuotaError
+	if errors.As(err, &quotaErr) {
+		code := 429
+		if errors.Is(err, service.ErrBatchLimitExceeded) {
+			code = 400
+		}
+		c.JSON(code, Response{
+			Code:    code,
+			Message: quotaErr.Message(),
+		})
+		return true
+	}
+	return false
+}
+
+// setQuotaHeaders 在采集接口的响应头中返回 kind 的今日额度，插件据此在达到上限前提示用户
+// 查询失败只记录日志，不影响采集结果
+func setQuotaHeaders(c *gin.Context, getQuota func(string) (*service.QuotaResponse, error), authCenterUserID, kind string) {
+	quota, err := getQuota(authCenterUserID)
+	if err != nil {
+		log.Printf("[Quota] failed to load quota for %s: %v", authCenterUserID, err)
+		return
+	}
+
+	usage := quota.Notes
+	if kind == service.QuotaBloggers {
+		usage = quota.Bloggers
+	}
+	c.Header("X-Quota-Limit", strconv.Itoa(usage.Limit))
+	c.Header("X-Quota-Remaining", strconv.Itoa(usage.Remaining))
+	c.Header("X-Quota-Reset", strconv.FormatInt(quota.ResetAt.Unix(), 10))
+	c.Header("X-Quota-Batch-Limit", strconv.Itoa(quota.BatchLimit))
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errInvalidIfMatch = errors.New("invalid If-Match header")
+	errMissingIfMatch = errors.New("If-Match header is required, fetch the record and send its ETag")
+)
+
+// setETag 以记录版本号作为强 ETag
+func setETag(c *gin.Context, version int64) {
+	c.Header("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// ifMatchVersion 解析 If-Match 请求头中的版本号
+// 未提供时返回 errMissingIfMatch，避免旧客户端静默覆盖；为 * 时返回 nil（显式放弃版本校验）；只接受单个强 ETag
+func ifMatchVersion(c *gin.Context) (*int64, error) {
+	raw := strings.TrimSpace(c.GetHeader("If-Match"))
+	if raw == "" {
+		return nil, errMissingIfMatch
+	}
+	if raw == "*" {
+		return nil, nil
+	}
+	if len(raw) < 2 || raw[0] != '"' || raw[len(raw)-1] != '"' {
+		return nil, errInvalidIfMatch
+	}
+	version, err := strconv.ParseInt(raw[1:len(raw)-1], 10, 64)
+	if err != nil {
+		return nil, errInvalidIfMatch
+	}
+	return &version, nil
+}
+
+// ifMatchError 写入 If-Match 解析错误的响应：缺失返回 428，格式错误返回 400
+func ifMatchError(c *gin.Context, err error) {
+	if errors.Is(err, errMissingIfMatch) {
+		ErrorResponse(c, 428, err.Error())
+		return
+	}
+	BadRequest(c, err.Error())
+}
@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/keenchase/edit-business/internal/export"
+)
+
+// streamExport 设置下载响应头后执行导出写出
+// 写出开始后无法再修改状态码，出错时只能记录日志并中断连接
+func streamExport(c *gin.Context, format export.Format, name string, run func(w io.Writer) error) {
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102-150405"), format.FileExtension())
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Cache-Control", "no-store")
+	c.Status(200)
+
+	if err := run(c.Writer); err != nil {
+		log.Printf("[Export] %s export failed: %v", name, err)
+		c.Abort()
+	}
+}
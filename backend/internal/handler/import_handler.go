@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/keenchase/edit-business/internal/importer"
+	"github.com/keenchase/edit-business/internal/service"
+)
+
+// maxImportFileBytes 导入文件大小上限
+const maxImportFileBytes = 20 << 20
+
+// ImportHandler 笔记/博主文件导入处理器
+type ImportHandler struct {
+	importService *service.ImportService
+}
+
+// NewImportHandler 创建导入处理器实例
+func NewImportHandler(importService *service.ImportService) *ImportHandler {
+	return &ImportHandler{importService: importService}
+}
+
+// ImportNotes 从文件导入笔记
+// @Summary 导入笔记
+// @Description 上传 CSV / XLSX / JSONL 文件导入笔记，先用 dryRun=true 预览列映射与校验结果，再正式导入；不带时区的日期按用户设置的时区解释；采集关闭返回 403，数据行数超过单批上限返回 400；每日额度只计新增的记录，超出额度的新增行标记为 skipped，更新已有记录的行不受限制
+// @Tags notes
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "导入文件"
+// @Param format formData string false "文件格式 csv / xlsx / jsonl，默认按扩展名判断"
+// @Param mapping formData string false "列映射 JSON：{\"文件列名\": \"目标字段\"}，目标字段为空表示忽略该列"
+// @Param dryRun formData bool false "仅校验预览，不写入" default(false)
+// @Success 200 {object} Response
+// @Router /api/v1/notes/import [post]
+func (h *ImportHandler) ImportNotes(c *gin.Context) {
+	h.handle(c, h.importService.ImportNotes)
+}
+
+// ImportBloggers 从文件导入博主
+// @Summary 导入博主
+// @Description 上传 CSV / XLSX / JSONL 文件导入博主，按 xhsId 插入或更新；采集开关与上限同笔记导入
+// @Tags bloggers
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "导入文件"
+// @Param format formData string false "文件格式 csv / xlsx / jsonl，默认按扩展名判断"
+// @Param mapping formData string false "列映射 JSON：{\"文件列名\": \"目标字段\"}，目标字段为空表示忽略该列"
+// @Param dryRun formData bool false "仅校验预览，不写入" default(false)
+// @Success 200 {object} Response
+// @Router /api/v1/bloggers/import [post]
+func (h *ImportHandler) ImportBloggers(c *gin.Context) {
+	h.handle(c, h.importService.ImportBloggers)
+}
+
+func (h *ImportHandler) handle(c *gin.Context, run func(string, *service.ImportRequest) (*service.ImportResponse, error)) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	req, err := bindImportRequest(c)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	resp, err := run(authCenterUserID.(string), req)
+	if err != nil {
+		if errors.Is(err, importer.ErrUnsupportedFormat) ||
+			errors.Is(err, importer.ErrInvalidFile) ||
+			errors.Is(err, importer.ErrInvalidMapping) ||
+			errors.Is(err, importer.ErrEmptyFile) ||
+			errors.Is(err, importer.ErrTooManyRows) {
+			BadRequest(c, err.Error())
+			return
+		}
+		if collectionLimitError(c, err) {
+			return
+		}
+		InternalError(c, err.Error())
+		return
+	}
+
+	SuccessResponse(c, resp)
+}
+
+// bindImportRequest 解析 multipart 表单：file、format、mapping、dryRun
+func bindImportRequest(c *gin.Context) (*service.ImportRequest, error) {
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return nil, errors.New("file is required")
+	}
+	format, err := importer.DetectFormat(c.PostForm("format"), fh.Filename)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &service.ImportRequest{Format: format}
+	if raw := c.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &req.Mapping); err != nil {
+			return nil, errors.New("invalid mapping")
+		}
+	}
+	if raw := c.PostForm("dryRun"); raw != "" {
+		if req.DryRun, err = strconv.ParseBool(raw); err != nil {
+			return nil, errors.New("invalid dryRun")
+		}
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if req.Data, err = importer.ReadAll(f, maxImportFileBytes); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
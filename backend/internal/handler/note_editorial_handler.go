@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/keenchase/edit-business/internal/service"
+)
+
+// NoteEditorialHandler 笔记编辑信息处理器
+type NoteEditorialHandler struct {
+	editorialService *service.NoteEditorialService
+}
+
+// NewNoteEditorialHandler 创建笔记编辑信息处理器实例
+func NewNoteEditorialHandler(editorialService *service.NoteEditorialService) *NoteEditorialHandler {
+	return &NoteEditorialHandler{editorialService: editorialService}
+}
+
+// Get 获取笔记的编辑信息（校验归属）
+// @Summary 获取笔记编辑信息
+// @Description 返回流程状态、星级、私有备注和状态变更记录；未编辑过的笔记为 inbox、0 星
+// @Tags notes
+// @Produce json
+// @Param id path string true "笔记 ID"
+// @Success 200 {object} Response
+// @Router /api/v1/notes/{id}/editorial [get]
+func (h *NoteEditorialHandler) Get(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	result, err := h.editorialService.Get(authCenterUserID.(string), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrNoteNotFound) {
+			NotFound(c, "note not found")
+			return
+		}
+		InternalError(c, err.Error())
+		return
+	}
+
+	SuccessResponse(c, result)
+}
+
+// Update 部分更新笔记的编辑信息（校验归属）
+// @Summary 更新笔记编辑信息
+// @Description 按 JSON Merge Patch 语义更新 status（inbox / shortlisted / rewriting / used / discarded）、rating（0-5）、remarks，值为 null 恢复默认；重新采集不会覆盖这些字段
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param id path string true "笔记 ID"
+// @Param request body object true "需要修改的字段"
+// @Success 200 {object} Response
+// @Router /api/v1/notes/{id}/editorial [put]
+func (h *NoteEditorialHandler) Update(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	var patch map[string]json.RawMessage
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	editorial, err := h.editorialService.Patch(authCenterUserID.(string), c.Param("id"), patch)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidPatch):
+			BadRequest(c, err.Error())
+		case errors.Is(err, service.ErrNoteNotFound):
+			NotFound(c, "note not found")
+		default:
+			InternalError(c, err.Error())
+		}
+		return
+	}
+
+	SuccessResponse(c, editorial)
+}
+
+// Throughput 编辑流程处理量
+// @Summary 编辑流程处理量
+// @Description 按天统计进入各编辑状态的次数
+// @Tags notes
+// @Produce json
+// @Param from query int false "起始时间（毫秒时间戳）"
+// @Param to query int false "结束时间（毫秒时间戳）"
+// @Success 200 {object} Response
+// @Router /api/v1/notes/editorial/throughput [get]
+func (h *NoteEditorialHandler) Throughput(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	from, err := parseMillisQuery(c, "from")
+	if err != nil {
+		BadRequest(c, "invalid from")
+		return
+	}
+	to, err := parseMillisQuery(c, "to")
+	if err != nil {
+		BadRequest(c, "invalid to")
+		return
+	}
+
+	result, err := h.editorialService.Throughput(authCenterUserID.(string), from, to)
+	if err != nil {
+		InternalError(c, err.Error())
+		return
+	}
+
+	SuccessResponse(c, result)
+}
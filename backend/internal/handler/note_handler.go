@@ -1,8 +1,12 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
 	"strconv"
 
+	"github.com/keenchase/edit-business/internal/export"
+	"github.com/keenchase/edit-business/internal/repository"
 	"github.com/keenchase/edit-business/internal/service"
 	"github.com/gin-gonic/gin"
 )
@@ -44,27 +48,14 @@ func (h *NoteHandler) Create(c *gin.Context) {
 	}
 
 	note, err := h.noteService.Create(authCenterUserID.(string), &req)
+	setQuotaHeaders(c, h.noteService.Quota, authCenterUserID.(string), service.QuotaNotes)
 	if err != nil {
 		// Check if it's a limit error and provide appropriate message
-		if err == service.ErrCollectionDisabled {
-			c.JSON(403, Response{
-				Code:    403,
-				Message: "采集功能已关闭，请在网站设置中开启",
-			})
+		if collectionLimitError(c, err) {
 			return
 		}
-		if err == service.ErrBatchLimitExceeded {
-			c.JSON(400, Response{
-				Code:    400,
-				Message: "单次采集超过上限（50条）",
-			})
-			return
-		}
-		if err == service.ErrDailyLimitExceeded {
-			c.JSON(429, Response{
-				Code:    429,
-				Message: "今日采集数量已达上限（500条）",
-			})
+		if err == service.ErrCollectionNotFound {
+			NotFound(c, "collection not found")
 			return
 		}
 		InternalError(c, err.Error())
@@ -102,19 +93,43 @@ func (h *NoteHandler) GetByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, note.Version)
 	SuccessResponse(c, note)
 }
 
 // List 获取笔记列表（按当前用户隔离）
 // @Summary 获取笔记列表
-// @Description 分页获取笔记列表，支持按作者、标签筛选
+// @Description 分页获取笔记列表，筛选条件可任意组合，支持按数值/时间字段排序
 // @Tags notes
 // @Accept json
 // @Produce json
 // @Param page query int false "页码" default(1)
 // @Param size query int false "每页数量" default(20)
+// @Param source query string false "采集来源 single / batch"
 // @Param author query string false "作者筛选"
+// @Param authorXhsId query string false "作者小红书 ID 筛选"
 // @Param tags query []string false "标签筛选"
+// @Param tagMode query string false "标签匹配方式 any / all" default(any)
+// @Param noteType query string false "笔记类型"
+// @Param likesMin query int false "最少点赞数"
+// @Param likesMax query int false "最多点赞数"
+// @Param collectsMin query int false "最少收藏数"
+// @Param collectsMax query int false "最多收藏数"
+// @Param publishFrom query int false "发布时间起（毫秒时间戳）"
+// @Param publishTo query int false "发布时间止（毫秒时间戳）"
+// @Param captureFrom query int false "采集时间起（毫秒时间戳）"
+// @Param captureTo query int false "采集时间止（毫秒时间戳）"
+// @Param hasVideo query bool false "是否有视频"
+// @Param hasContent query bool false "是否有正文"
+// @Param collectionId query string false "收藏夹 ID"
+// @Param status query []string false "编辑状态 inbox / shortlisted / rewriting / used / discarded，可传多个"
+// @Param ratingMin query int false "最低星级"
+// @Param ratingMax query int false "最高星级"
+// @Param hasRemarks query bool false "是否有备注"
+// @Param sort query string false "排序字段" default(captureTimestamp)
+// @Param order query string false "排序方向 asc / desc" default(desc)
+// @Param cursor query string false "游标分页：传入上一页的 nextCursor，首页传空值"
+// @Param withTotal query bool false "游标模式下是否返回总数" default(false)
 // @Success 200 {object} Response
 // @Router /api/v1/notes [get]
 func (h *NoteHandler) List(c *gin.Context) {
@@ -135,31 +150,278 @@ func (h *NoteHandler) List(c *gin.Context) {
 			req.Size = size
 		}
 	}
-	req.Author = c.Query("author")
-	req.Tags = c.QueryArray("tags")
-	req.Source = c.Query("source")
+	if err := bindNoteFilter(c, &req.NoteFilter); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	req.Sort = c.Query("sort")
+	req.Order = c.Query("order")
+	req.Cursor, req.UseCursor = c.GetQuery("cursor")
+	req.WithTotal = c.Query("withTotal") == "true"
 
 	result, err := h.noteService.List(authCenterUserID.(string), &req)
 	if err != nil {
+		if err == repository.ErrInvalidSort {
+			BadRequest(c, "invalid sort or order")
+			return
+		}
+		if errors.Is(err, service.ErrInvalidNoteFilter) {
+			BadRequest(c, err.Error())
+			return
+		}
+		if err == repository.ErrInvalidCursor {
+			BadRequest(c, "invalid cursor")
+			return
+		}
+		InternalError(c, err.Error())
+		return
+	}
+
+	SuccessResponse(c, result)
+}
+
+// bindNoteFilter 从查询参数解析笔记组合筛选条件
+func bindNoteFilter(c *gin.Context, f *repository.NoteFilter) error {
+	f.Source = c.Query("source")
+	f.Author = c.Query("author")
+	f.AuthorXhsID = c.Query("authorXhsId")
+	f.Tags = c.QueryArray("tags")
+	f.NoteType = c.Query("noteType")
+	f.CollectionID = c.Query("collectionId")
+	f.Statuses = c.QueryArray("status")
+	f.TagMode = c.Query("tagMode") // status、tagMode 的取值由 service 校验（与批量操作的 JSON 筛选条件共用）
+
+	var err error
+	if f.LikesMin, err = queryInt32Ptr(c, "likesMin"); err != nil {
+		return err
+	}
+	if f.LikesMax, err = queryInt32Ptr(c, "likesMax"); err != nil {
+		return err
+	}
+	if f.CollectsMin, err = queryInt32Ptr(c, "collectsMin"); err != nil {
+		return err
+	}
+	if f.CollectsMax, err = queryInt32Ptr(c, "collectsMax"); err != nil {
+		return err
+	}
+	if f.PublishFrom, err = queryInt64Ptr(c, "publishFrom"); err != nil {
+		return err
+	}
+	if f.PublishTo, err = queryInt64Ptr(c, "publishTo"); err != nil {
+		return err
+	}
+	if f.CaptureFrom, err = queryInt64Ptr(c, "captureFrom"); err != nil {
+		return err
+	}
+	if f.CaptureTo, err = queryInt64Ptr(c, "captureTo"); err != nil {
+		return err
+	}
+	if f.HasVideo, err = queryBoolPtr(c, "hasVideo"); err != nil {
+		return err
+	}
+	if f.HasContent, err = queryBoolPtr(c, "hasContent"); err != nil {
+		return err
+	}
+	if f.RatingMin, err = queryInt32Ptr(c, "ratingMin"); err != nil {
+		return err
+	}
+	if f.RatingMax, err = queryInt32Ptr(c, "ratingMax"); err != nil {
+		return err
+	}
+	if f.HasRemarks, err = queryBoolPtr(c, "hasRemarks"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Export 导出笔记（按当前用户隔离）
+// @Summary 导出笔记
+// @Description 按列表接口的筛选/排序条件流式导出笔记，md 格式为每篇笔记一个 Markdown 文件的 zip 包
+// @Tags notes
+// @Produce octet-stream
+// @Param format query string true "导出格式 csv / xlsx / jsonl / md"
+// @Success 200 {file} file
+// @Router /api/v1/notes/export [get]
+func (h *NoteHandler) Export(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	format, err := export.ParseFormat(c.Query("format"))
+	if err != nil {
+		BadRequest(c, "format must be one of csv, xlsx, jsonl, md")
+		return
+	}
+
+	var req service.ListNotesRequest
+	if err := bindNoteFilter(c, &req.NoteFilter); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	req.Sort = c.Query("sort")
+	req.Order = c.Query("order")
+
+	run, err := h.noteService.ExportNotes(authCenterUserID.(string), &req, format)
+	if err != nil {
+		if err == repository.ErrInvalidSort {
+			BadRequest(c, "invalid sort or order")
+			return
+		}
+		if errors.Is(err, service.ErrInvalidNoteFilter) {
+			BadRequest(c, err.Error())
+			return
+		}
 		InternalError(c, err.Error())
 		return
 	}
 
+	streamExport(c, format, "notes", run)
+}
+
+// Search 全文检索笔记（按当前用户隔离）
+// @Summary 全文检索笔记
+// @Description 检索标题和正文，支持中文，按相关度排序并返回高亮片段
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param q query string true "检索关键词"
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(20)
+// @Success 200 {object} Response
+// @Router /api/v1/notes/search [get]
+func (h *NoteHandler) Search(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	var req service.SearchNotesRequest
+	req.Q = c.Query("q")
+	if req.Q == "" {
+		BadRequest(c, "q is required")
+		return
+	}
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil {
+			req.Page = page
+		}
+	}
+	if sizeStr := c.Query("size"); sizeStr != "" {
+		if size, err := strconv.Atoi(sizeStr); err == nil {
+			req.Size = size
+		}
+	}
+
+	result, err := h.noteService.Search(authCenterUserID.(string), &req)
+	if err != nil {
+		InternalError(c, err.Error())
+		return
+	}
+
+	SuccessResponse(c, result)
+}
+
+// GetMetrics 获取笔记互动数据时间序列（校验归属）
+// @Summary 获取笔记互动时间序列
+// @Description 返回笔记每次采集时的点赞/收藏/评论快照，以及 24h/7d 增长
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param id path string true "笔记 ID"
+// @Param from query int false "起始时间（毫秒时间戳）"
+// @Param to query int false "结束时间（毫秒时间戳）"
+// @Success 200 {object} Response
+// @Router /api/v1/notes/{id}/metrics [get]
+func (h *NoteHandler) GetMetrics(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		BadRequest(c, "id is required")
+		return
+	}
+
+	from, err := parseMillisQuery(c, "from")
+	if err != nil {
+		BadRequest(c, "invalid from")
+		return
+	}
+	to, err := parseMillisQuery(c, "to")
+	if err != nil {
+		BadRequest(c, "invalid to")
+		return
+	}
+
+	result, err := h.noteService.GetMetrics(authCenterUserID.(string), id, from, to)
+	if err != nil {
+		NotFound(c, "note not found")
+		return
+	}
+
+	SuccessResponse(c, result)
+}
+
+// SimilarCovers 查找封面相似的笔记（校验归属）
+// @Summary 查找封面相似的笔记
+// @Description 按封面感知哈希（dHash + aHash 汉明距离）查找当前用户的其他笔记，用于识别不同账号转发的同图笔记；封面尚未归档时 coverHashed 为 false
+// @Tags notes
+// @Produce json
+// @Param id path string true "笔记 ID"
+// @Param maxDistance query int false "最大汉明距离（0-20，默认 10）"
+// @Success 200 {object} Response
+// @Router /api/v1/notes/{id}/similar-covers [get]
+func (h *NoteHandler) SimilarCovers(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	maxDistance := service.DefaultCoverDistance
+	if v := c.Query("maxDistance"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			BadRequest(c, "invalid maxDistance")
+			return
+		}
+		maxDistance = n
+	}
+
+	result, err := h.noteService.SimilarCovers(authCenterUserID.(string), c.Param("id"), maxDistance)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCoverDistance) {
+			BadRequest(c, err.Error())
+			return
+		}
+		NotFound(c, "note not found")
+		return
+	}
+
 	SuccessResponse(c, result)
 }
 
 // BatchCreate 批量创建笔记
 // @Summary 批量创建笔记
-// @Description 批量创建笔记记录（用于 Chrome 插件同步）
+// @Description 批量创建或更新笔记记录（用于 Chrome 插件同步），返回每条的写入结果：created / updated / skipped（已有完整记录）/ rejected（附原因）；mode=atomic 时任一条被拒绝整批回滚并返回 422
 // @Tags notes
 // @Accept json
 // @Produce json
+// @Param mode query string false "atomic 或 bestEffort（默认）"
 // @Param request body []service.CreateNoteRequest true "批量创建笔记请求"
 // @Success 200 {object} Response
+// @Failure 422 {object} Response
 // @Router /api/v1/notes/batch [post]
 func (h *NoteHandler) BatchCreate(c *gin.Context) {
+	// 不使用 ShouldBindJSON：单条字段缺失时应在结果中标记为 rejected，而不是拒绝整个请求
 	var reqs []*service.CreateNoteRequest
-	if err := c.ShouldBindJSON(&reqs); err != nil {
+	if err := json.NewDecoder(c.Request.Body).Decode(&reqs); err != nil {
 		BadRequest(c, err.Error())
 		return
 	}
@@ -174,49 +436,44 @@ func (h *NoteHandler) BatchCreate(c *gin.Context) {
 		return
 	}
 
-	err := h.noteService.BatchCreate(authCenterUserID.(string), reqs)
+	result, err := h.noteService.BatchCreate(authCenterUserID.(string), reqs, c.Query("mode"))
+	setQuotaHeaders(c, h.noteService.Quota, authCenterUserID.(string), service.QuotaNotes)
 	if err != nil {
-		// Check if it's a limit error and provide appropriate message
-		if err == service.ErrCollectionDisabled {
-			c.JSON(403, Response{
-				Code:    403,
-				Message: "采集功能已关闭，请在网站设置中开启",
-			})
+		if err == service.ErrInvalidBatchMode {
+			BadRequest(c, err.Error())
 			return
 		}
-		if err == service.ErrBatchLimitExceeded {
-			c.JSON(400, Response{
-				Code:    400,
-				Message: "单次采集超过上限（50条）",
-			})
-			return
-		}
-		if err == service.ErrDailyLimitExceeded {
-			c.JSON(429, Response{
-				Code:    429,
-				Message: "今日采集数量已达上限（500条）",
-			})
+		if collectionLimitError(c, err) {
 			return
 		}
 		InternalError(c, err.Error())
 		return
 	}
 
-	SuccessResponse(c, gin.H{
-		"count":  len(reqs),
-		"status": "success",
-	})
+	if !result.Committed {
+		c.JSON(422, Response{
+			Code:    422,
+			Message: "batch rolled back",
+			Data:    result,
+		})
+		return
+	}
+
+	SuccessResponse(c, result)
 }
 
-// Update 更新笔记（校验归属）
+// Update 部分更新笔记（校验归属）
 // @Summary 更新笔记
-// @Description 更新笔记信息
+// @Description 按 JSON Merge Patch 语义更新可编辑字段（title / author / authorXhsId / content / tags / noteType，媒体链接只能由采集写入），值为 null 表示清空；必须携带 If-Match（GET 返回的 ETag），缺失返回 428，版本不一致返回 412，If-Match: * 表示不做版本校验直接覆盖
 // @Tags notes
 // @Accept json
 // @Produce json
 // @Param id path string true "笔记 ID"
-// @Param request body model.Note true "笔记信息"
+// @Param If-Match header string true "笔记的 ETag（版本号），* 表示不校验"
+// @Param request body object true "需要修改的字段"
 // @Success 200 {object} Response
+// @Failure 412 {object} Response
+// @Failure 428 {object} Response
 // @Router /api/v1/notes/{id} [put]
 func (h *NoteHandler) Update(c *gin.Context) {
 	authCenterUserID, exists := c.Get("authCenterUserID")
@@ -231,34 +488,40 @@ func (h *NoteHandler) Update(c *gin.Context) {
 		return
 	}
 
-	var req map[string]interface{}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		BadRequest(c, err.Error())
+	ifMatch, err := ifMatchVersion(c)
+	if err != nil {
+		ifMatchError(c, err)
 		return
 	}
 
-	note, err := h.noteService.GetByID(authCenterUserID.(string), id)
-	if err != nil || note == nil {
-		NotFound(c, "note not found")
+	var patch map[string]json.RawMessage
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		BadRequest(c, err.Error())
 		return
 	}
 
-	err = h.noteService.Update(authCenterUserID.(string), note)
+	note, err := h.noteService.Patch(authCenterUserID.(string), id, patch, ifMatch)
 	if err != nil {
-		if err == service.ErrNoteNotFound {
+		switch {
+		case errors.Is(err, service.ErrInvalidPatch):
+			BadRequest(c, err.Error())
+		case errors.Is(err, repository.ErrVersionConflict):
+			ErrorResponse(c, 412, "note has been modified, reload and retry")
+		case errors.Is(err, service.ErrNoteNotFound):
 			NotFound(c, "note not found")
-			return
+		default:
+			InternalError(c, err.Error())
 		}
-		InternalError(c, err.Error())
 		return
 	}
 
+	setETag(c, note.Version)
 	SuccessResponse(c, note)
 }
 
 // Delete 删除笔记（校验归属）
 // @Summary 删除笔记
-// @Description 根据 ID 删除笔记
+// @Description 根据 ID 删除笔记（移入回收站，可恢复）
 // @Tags notes
 // @Accept json
 // @Produce json
@@ -289,3 +552,42 @@ func (h *NoteHandler) Delete(c *gin.Context) {
 		"status": "deleted",
 	})
 }
+
+// Bulk 批量操作笔记（同一事务，校验归属）
+// @Summary 批量操作笔记
+// @Description 按 ID 列表或筛选条件（二选一）批量删除、增删标签、设置字段或移动到收藏夹，单次最多 1000 篇，返回每篇笔记的处理结果
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param request body service.BulkNotesRequest true "批量操作"
+// @Success 200 {object} Response
+// @Router /api/v1/notes/bulk [post]
+func (h *NoteHandler) Bulk(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	var req service.BulkNotesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	resp, err := h.noteService.Bulk(authCenterUserID.(string), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidBulk) || errors.Is(err, service.ErrBulkTooLarge) {
+			BadRequest(c, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrCollectionNotFound) {
+			NotFound(c, "collection not found")
+			return
+		}
+		InternalError(c, err.Error())
+		return
+	}
+
+	SuccessResponse(c, resp)
+}
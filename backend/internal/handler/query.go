@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// queryInt32Ptr 解析可选的 int32 查询参数，未传时返回 nil
+func queryInt32Ptr(c *gin.Context, key string) (*int32, error) {
+	v := c.Query(key)
+	if v == "" {
+		return nil, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s", key)
+	}
+	n32 := int32(n)
+	return &n32, nil
+}
+
+// queryInt64Ptr 解析可选的 int64 查询参数，未传时返回 nil
+func queryInt64Ptr(c *gin.Context, key string) (*int64, error) {
+	v := c.Query(key)
+	if v == "" {
+		return nil, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s", key)
+	}
+	return &n, nil
+}
+
+// queryBoolPtr 解析可选的布尔查询参数，未传时返回 nil
+func queryBoolPtr(c *gin.Context, key string) (*bool, error) {
+	v := c.Query(key)
+	if v == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s", key)
+	}
+	return &b, nil
+}
+
+// parseMillisQuery 解析毫秒时间戳查询参数，未传时返回零值
+func parseMillisQuery(c *gin.Context, key string) (time.Time, error) {
+	ms, err := queryInt64Ptr(c, key)
+	if err != nil || ms == nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(*ms), nil
+}
@@ -1,6 +1,10 @@
 package handler
 
 import (
+	"errors"
+
+	"github.com/keenchase/edit-business/internal/analyzer"
+	"github.com/keenchase/edit-business/internal/repository"
 	"github.com/keenchase/edit-business/internal/service"
 	"github.com/gin-gonic/gin"
 )
@@ -38,3 +42,230 @@ func (h *StatsHandler) GetStats(c *gin.Context) {
 
 	SuccessResponse(c, stats)
 }
+
+// GetTimeseries 采集时间序列（按当前用户隔离）
+// @Summary 采集时间序列
+// @Description 按用户时区的天或自然周（周一开始）统计采集的笔记数、博主数或笔记互动量，按来源（single / batch）和笔记类型细分；不含回收站中的记录
+// @Tags stats
+// @Produce json
+// @Param metric query string false "notes（默认）/ bloggers / likes / collects / comments"
+// @Param interval query string false "day（默认）/ week"
+// @Param from query int false "起始时间（毫秒时间戳），默认最近 30 天 / 12 周"
+// @Param to query int false "结束时间（毫秒时间戳），默认当前时间"
+// @Success 200 {object} Response
+// @Router /api/v1/stats/timeseries [get]
+func (h *StatsHandler) GetTimeseries(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	var req service.TimeseriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.statsService.Timeseries(authCenterUserID.(string), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidStatsQuery) {
+			BadRequest(c, err.Error())
+			return
+		}
+		InternalError(c, err.Error())
+		return
+	}
+
+	SuccessResponse(c, result)
+}
+
+// GetKeywords 关键词统计（按当前用户隔离）
+// @Summary 关键词统计
+// @Description 统计笔记标题和正文中的关键词（kind=mention / emoji 时统计 @提及 / 表情），返回出现的笔记数、次数和互动量；rank=engagement 时按互动量排序
+// @Tags stats
+// @Produce json
+// @Param kind query string false "keyword（默认）/ mention / emoji"
+// @Param dateField query string false "时间范围字段：captureTimestamp（默认）/ publishDate"
+// @Param from query int false "起始时间（毫秒时间戳）"
+// @Param to query int false "结束时间（毫秒时间戳）"
+// @Param rank query string false "frequency（默认）/ engagement"
+// @Param minNotes query int false "至少出现在多少篇笔记中"
+// @Param limit query int false "返回条数（默认 50，最大 200）"
+// @Success 200 {object} Response
+// @Router /api/v1/stats/keywords [get]
+func (h *StatsHandler) GetKeywords(c *gin.Context) {
+	h.contentTermStats(c, "")
+}
+
+// GetHashtags 话题统计（按当前用户隔离）
+// @Summary 话题统计
+// @Description 统计笔记中的话题（正文中的 #话题[话题]# 和插件采集的标签），返回出现的笔记数、次数和互动量；rank=engagement 时按互动量排序
+// @Tags stats
+// @Produce json
+// @Param dateField query string false "时间范围字段：captureTimestamp（默认）/ publishDate"
+// @Param from query int false "起始时间（毫秒时间戳）"
+// @Param to query int false "结束时间（毫秒时间戳）"
+// @Param rank query string false "frequency（默认）/ engagement"
+// @Param minNotes query int false "至少出现在多少篇笔记中"
+// @Param limit query int false "返回条数（默认 50，最大 200）"
+// @Success 200 {object} Response
+// @Router /api/v1/stats/hashtags [get]
+func (h *StatsHandler) GetHashtags(c *gin.Context) {
+	h.contentTermStats(c, analyzer.KindHashtag)
+}
+
+// contentTermStats 内容词项统计；kind 非空时忽略查询参数中的 kind
+func (h *StatsHandler) contentTermStats(c *gin.Context, kind string) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	var req service.ContentStatsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	if kind != "" {
+		req.Kind = kind
+	} else if req.Kind == analyzer.KindHashtag {
+		BadRequest(c, "use /api/v1/stats/hashtags for hashtags")
+		return
+	}
+
+	result, err := h.statsService.ContentTermStats(authCenterUserID.(string), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidStatsQuery) {
+			BadRequest(c, err.Error())
+			return
+		}
+		InternalError(c, err.Error())
+		return
+	}
+
+	SuccessResponse(c, result)
+}
+
+// GetAuthorLeaderboard 作者排行（按当前用户隔离）
+// @Summary 作者排行
+// @Description 按作者（author_xhs_id，缺失时按昵称）统计采集的笔记数、点赞、收藏、评论和平均互动，支持时间范围和分页
+// @Tags stats
+// @Produce json
+// @Param dateField query string false "时间范围字段：captureTimestamp（默认）/ publishDate"
+// @Param from query int false "起始时间（毫秒时间戳）"
+// @Param to query int false "结束时间（毫秒时间戳）"
+// @Param sort query string false "notes（默认）/ likes / engagement / avgEngagement"
+// @Param minNotes query int false "至少包含多少篇笔记（按平均互动排序时可过滤偶然的单篇爆款）"
+// @Param page query int false "页码"
+// @Param size query int false "每页数量（默认 20，最大 100）"
+// @Success 200 {object} Response
+// @Router /api/v1/stats/authors [get]
+func (h *StatsHandler) GetAuthorLeaderboard(c *gin.Context) {
+	h.leaderboard(c, repository.DimensionAuthor)
+}
+
+// GetTagLeaderboard 标签排行（按当前用户隔离）
+// @Summary 标签排行
+// @Description 按插件采集的笔记标签（去掉 # 并转小写）统计笔记数和互动量，支持时间范围和分页
+// @Tags stats
+// @Produce json
+// @Param dateField query string false "时间范围字段：captureTimestamp（默认）/ publishDate"
+// @Param from query int false "起始时间（毫秒时间戳）"
+// @Param to query int false "结束时间（毫秒时间戳）"
+// @Param sort query string false "notes（默认）/ likes / engagement / avgEngagement"
+// @Param minNotes query int false "至少包含多少篇笔记"
+// @Param page query int false "页码"
+// @Param size query int false "每页数量（默认 20，最大 100）"
+// @Success 200 {object} Response
+// @Router /api/v1/stats/tags [get]
+func (h *StatsHandler) GetTagLeaderboard(c *gin.Context) {
+	h.leaderboard(c, repository.DimensionTag)
+}
+
+// GetNoteTypeLeaderboard 笔记类型排行（按当前用户隔离）
+// @Summary 笔记类型排行
+// @Description 按笔记类型（图文 / 视频等）统计笔记数和互动量，sort=avgEngagement 时可比较各类型的表现
+// @Tags stats
+// @Produce json
+// @Param dateField query string false "时间范围字段：captureTimestamp（默认）/ publishDate"
+// @Param from query int false "起始时间（毫秒时间戳）"
+// @Param to query int false "结束时间（毫秒时间戳）"
+// @Param sort query string false "notes（默认）/ likes / engagement / avgEngagement"
+// @Param minNotes query int false "至少包含多少篇笔记"
+// @Param page query int false "页码"
+// @Param size query int false "每页数量（默认 20，最大 100）"
+// @Success 200 {object} Response
+// @Router /api/v1/stats/note-types [get]
+func (h *StatsHandler) GetNoteTypeLeaderboard(c *gin.Context) {
+	h.leaderboard(c, repository.DimensionNoteType)
+}
+
+// leaderboard 按维度统计排行
+func (h *StatsHandler) leaderboard(c *gin.Context, dimension string) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	var req service.LeaderboardRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.statsService.Leaderboard(authCenterUserID.(string), dimension, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidStatsQuery) {
+			BadRequest(c, err.Error())
+			return
+		}
+		InternalError(c, err.Error())
+		return
+	}
+
+	SuccessResponse(c, result)
+}
+
+// GetTagPairs 标签共现组合（按当前用户隔离）
+// @Summary 标签共现组合
+// @Description 统计同一篇笔记上同时出现的两个标签，返回共现笔记数、互动量和 Jaccard 相似度；指定 tag 时只返回与该标签搭配的组合
+// @Tags stats
+// @Produce json
+// @Param tag query string false "只看与该标签搭配的组合"
+// @Param dateField query string false "时间范围字段：captureTimestamp（默认）/ publishDate"
+// @Param from query int false "起始时间（毫秒时间戳）"
+// @Param to query int false "结束时间（毫秒时间戳）"
+// @Param sort query string false "notes（默认）/ likes / engagement / avgEngagement"
+// @Param minNotes query int false "至少共现在多少篇笔记中"
+// @Param page query int false "页码"
+// @Param size query int false "每页数量（默认 20，最大 100）"
+// @Success 200 {object} Response
+// @Router /api/v1/stats/tags/pairs [get]
+func (h *StatsHandler) GetTagPairs(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	var req service.LeaderboardRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.statsService.TagPairs(authCenterUserID.(string), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidStatsQuery) {
+			BadRequest(c, err.Error())
+			return
+		}
+		InternalError(c, err.Error())
+		return
+	}
+
+	SuccessResponse(c, result)
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/keenchase/edit-business/internal/service"
+	"github.com/keenchase/edit-business/internal/storage"
+)
+
+// localUploadFormOverhead 本地直传请求体中除文件外的表单开销上限
+const localUploadFormOverhead = 1 << 20
+
+// StorageHandler 对象存储处理器
+type StorageHandler struct {
+	storageService *service.StorageService
+}
+
+// NewStorageHandler 创建对象存储处理器实例
+func NewStorageHandler(storageService *service.StorageService) *StorageHandler {
+	return &StorageHandler{storageService: storageService}
+}
+
+// GetUploadToken 获取直传凭证
+// @Summary 获取直传凭证
+// @Description 返回当前存储驱动（qiniu / s3 / local）的直传凭证，只能上传到 keyPrefix 下，并限制文件大小和类型；上传完成后调用 POST /api/v1/storage/uploads 登记
+// @Tags storage
+// @Produce json
+// @Success 200 {object} Response
+// @Router /api/v1/storage/upload-token [get]
+func (h *StorageHandler) GetUploadToken(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	ticket, err := h.storageService.UploadToken(authCenterUserID.(string))
+	if err != nil {
+		if collectionLimitError(c, err) {
+			return
+		}
+		InternalError(c, err.Error())
+		return
+	}
+
+	SuccessResponse(c, ticket)
+}
+
+// GetQiniuUploadToken 获取七牛云上传 token（兼容旧版插件，返回格式不变）
+// @Summary 获取七牛云上传token
+// @Description 兼容旧版插件：返回 uploadToken、uploadUrl、cdnDomain、keyPrefix、expiresIn，key 必须为 keyPrefix + "/" + 文件名；仅在存储驱动为 qiniu 时可用
+// @Tags qiniu
+// @Produce json
+// @Success 200 {object} Response
+// @Router /api/v1/qiniu/upload-token [get]
+func (h *StorageHandler) GetQiniuUploadToken(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	if h.storageService.Driver() != storage.DriverQiniu {
+		ErrorResponse(c, 501, "当前存储驱动不是七牛云，请升级插件")
+		return
+	}
+
+	ticket, err := h.storageService.UploadToken(authCenterUserID.(string))
+	if err != nil {
+		if collectionLimitError(c, err) {
+			return
+		}
+		InternalError(c, err.Error())
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"uploadToken": ticket.Token,
+		"uploadUrl":   ticket.UploadURL,
+		"cdnDomain":   ticket.PublicURL,
+		"keyPrefix":   strings.TrimSuffix(ticket.KeyPrefix, "/"),
+		"expiresIn":   int(time.Until(ticket.ExpiresAt).Seconds()),
+	})
+}
+
+// CompleteUpload 上传完成回调
+// @Summary 登记上传完成的文件
+// @Description 校验 key 属于当前用户、文件已存在且满足大小和类型限制后记录文件；noteId 或 noteUrl 用于关联笔记
+// @Tags storage
+// @Accept json
+// @Produce json
+// @Param request body service.CompleteUploadRequest true "上传完成信息"
+// @Success 200 {object} Response
+// @Router /api/v1/storage/uploads [post]
+func (h *StorageHandler) CompleteUpload(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	var req service.CompleteUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	obj, err := h.storageService.CompleteUpload(c.Request.Context(), authCenterUserID.(string), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUploadKeyForbidden):
+			ErrorResponse(c, 403, err.Error())
+		case errors.Is(err, service.ErrUploadNotFound):
+			NotFound(c, err.Error())
+		case errors.Is(err, service.ErrNoteNotFound):
+			NotFound(c, "note not found")
+		case errors.Is(err, service.ErrUploadRejected):
+			ErrorResponse(c, 422, err.Error())
+		default:
+			InternalError(c, err.Error())
+		}
+		return
+	}
+
+	SuccessResponse(c, obj)
+}
+
+// ListUploads 获取已上传的文件
+// @Summary 获取已上传的文件
+// @Description 按上传时间倒序返回当前用户的文件；指定 noteId 时只返回该笔记的文件（包括笔记保存前上传的文件）
+// @Tags storage
+// @Produce json
+// @Param noteId query string false "笔记 ID"
+// @Param limit query int false "返回条数（默认 50，最大 200）"
+// @Success 200 {object} Response
+// @Router /api/v1/storage/uploads [get]
+func (h *StorageHandler) ListUploads(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	objs, err := h.storageService.ListUploads(authCenterUserID.(string), c.Query("noteId"), limit)
+	if err != nil {
+		if errors.Is(err, service.ErrNoteNotFound) {
+			NotFound(c, "note not found")
+			return
+		}
+		InternalError(c, err.Error())
+		return
+	}
+
+	SuccessResponse(c, objs)
+}
+
+// LocalUpload 本地存储驱动的直传接口（凭证即授权，不需要 API Key）
+// @Summary 上传文件（本地存储）
+// @Description multipart 表单字段 token（直传凭证）、key（必须以凭证的 keyPrefix 开头）、file
+// @Tags storage
+// @Accept multipart/form-data
+// @Produce json
+// @Success 200 {object} Response
+// @Router /api/v1/storage/local/upload [post]
+func (h *StorageHandler) LocalUpload(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.storageService.UploadMaxBytes()+localUploadFormOverhead)
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		BadRequest(c, "file is required")
+		return
+	}
+	defer file.Close()
+
+	url, err := h.storageService.LocalUpload(c.Request.Context(), c.PostForm("token"), c.PostForm("key"), file, header.Size, header.Header.Get("Content-Type"))
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrInvalidToken):
+			Unauthorized(c, err.Error())
+		case errors.Is(err, storage.ErrPolicyViolate):
+			ErrorResponse(c, 403, err.Error())
+		case errors.Is(err, service.ErrStorageDriver):
+			NotFound(c, err.Error())
+		default:
+			InternalError(c, err.Error())
+		}
+		return
+	}
+
+	SuccessResponse(c, gin.H{"key": c.PostForm("key"), "url": url})
+}
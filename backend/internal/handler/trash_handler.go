@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/keenchase/edit-business/internal/service"
+)
+
+// TrashHandler 回收站处理器
+type TrashHandler struct {
+	trashService *service.TrashService
+}
+
+// NewTrashHandler 创建回收站处理器实例
+func NewTrashHandler(trashService *service.TrashService) *TrashHandler {
+	return &TrashHandler{trashService: trashService}
+}
+
+// List 获取回收站列表
+// @Summary 回收站列表
+// @Description 按删除时间倒序列出已删除的笔记和博主，保留期满后自动永久删除
+// @Tags trash
+// @Produce json
+// @Param type query string false "类型 note / blogger，默认全部"
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(20)
+// @Success 200 {object} Response
+// @Router /api/v1/trash [get]
+func (h *TrashHandler) List(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	var req service.ListTrashRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	resp, err := h.trashService.List(authCenterUserID.(string), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTrashType) {
+			BadRequest(c, err.Error())
+			return
+		}
+		InternalError(c, err.Error())
+		return
+	}
+
+	SuccessResponse(c, resp)
+}
+
+// Restore 从回收站恢复
+// @Summary 恢复笔记或博主
+// @Description 已存在相同链接 / xhs_id 的记录时返回 409；replace=true 时把该记录移入回收站后再恢复
+// @Tags trash
+// @Produce json
+// @Param id path string true "笔记或博主 ID"
+// @Param replace query bool false "替换已存在的同一记录" default(false)
+// @Success 200 {object} Response
+// @Failure 409 {object} Response
+// @Router /api/v1/trash/{id}/restore [post]
+func (h *TrashHandler) Restore(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	replace := false
+	if raw := c.Query("replace"); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			BadRequest(c, "invalid replace")
+			return
+		}
+		replace = v
+	}
+
+	result, err := h.trashService.Restore(authCenterUserID.(string), c.Param("id"), replace)
+	if err != nil {
+		var conflict *service.RestoreConflictError
+		switch {
+		case errors.As(err, &conflict):
+			c.JSON(409, Response{
+				Code:    409,
+				Message: err.Error(),
+				Data:    gin.H{"type": conflict.Type, "conflictId": conflict.ConflictID},
+			})
+		case errors.Is(err, service.ErrTrashItemNotFound):
+			NotFound(c, err.Error())
+		default:
+			InternalError(c, err.Error())
+		}
+		return
+	}
+
+	SuccessResponse(c, result)
+}
+
+// Purge 永久删除回收站中的一条记录
+// @Summary 永久删除
+// @Description 永久删除回收站中的笔记或博主，不可恢复
+// @Tags trash
+// @Produce json
+// @Param id path string true "笔记或博主 ID"
+// @Success 200 {object} Response
+// @Router /api/v1/trash/{id} [delete]
+func (h *TrashHandler) Purge(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	if err := h.trashService.Purge(authCenterUserID.(string), c.Param("id")); err != nil {
+		if errors.Is(err, service.ErrTrashItemNotFound) {
+			NotFound(c, err.Error())
+			return
+		}
+		InternalError(c, err.Error())
+		return
+	}
+
+	SuccessResponse(c, nil)
+}
+
+// Empty 清空回收站
+// @Summary 清空回收站
+// @Description 永久删除回收站中的全部记录，可按类型清空
+// @Tags trash
+// @Produce json
+// @Param type query string false "类型 note / blogger，默认全部"
+// @Success 200 {object} Response
+// @Router /api/v1/trash [delete]
+func (h *TrashHandler) Empty(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(401, Response{Code: 401, Message: "Unauthorized"})
+		return
+	}
+
+	result, err := h.trashService.Empty(authCenterUserID.(string), c.Query("type"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTrashType) {
+			BadRequest(c, err.Error())
+			return
+		}
+		InternalError(c, err.Error())
+		return
+	}
+
+	SuccessResponse(c, result)
+}
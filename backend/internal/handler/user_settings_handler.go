@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -108,3 +109,99 @@ func (h *UserSettingsHandler) ToggleCollectionEnabled(c *gin.Context) {
 		Data:    response,
 	})
 }
+
+// GetQuota gets the current collection quota
+// @Summary Get collection quota
+// @Description 获取采集额度：单次上限、笔记和博主今日已用与剩余额度；每日额度按用户时区的自然日计算，resetAt 为下次重置时间。采集接口的响应头同样返回 X-Quota-Limit / X-Quota-Remaining / X-Quota-Reset / X-Quota-Batch-Limit
+// @Tags user-settings
+// @Produce json
+// @Success 200 {object} handler.Response{data=service.QuotaResponse}
+// @Failure 401 {object} handler.Response
+// @Failure 500 {object} handler.Response
+// @Router /api/v1/user-settings/quota [get]
+func (h *UserSettingsHandler) GetQuota(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, Response{
+			Code:    401,
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	quota, err := h.settingsService.GetQuota(authCenterUserID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: "Failed to get quota",
+			Data:    err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Code:    0,
+		Message: "Success",
+		Data:    quota,
+	})
+}
+
+// SetTimezone sets the user's timezone
+// @Summary Set timezone
+// @Description 设置用户时区（IANA 名称，如 Asia/Shanghai），每日采集额度按该时区的自然日计算
+// @Tags user-settings
+// @Accept json
+// @Produce json
+// @Param request body object true "{\"timezone\": \"Asia/Shanghai\"}"
+// @Success 200 {object} handler.Response{data=service.UserSettingsResponse}
+// @Failure 400 {object} handler.Response
+// @Failure 401 {object} handler.Response
+// @Failure 500 {object} handler.Response
+// @Router /api/v1/user-settings/timezone [post]
+func (h *UserSettingsHandler) SetTimezone(c *gin.Context) {
+	authCenterUserID, exists := c.Get("authCenterUserID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, Response{
+			Code:    401,
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	var req struct {
+		Timezone string `json:"timezone" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Code:    400,
+			Message: "Invalid request",
+			Data:    err.Error(),
+		})
+		return
+	}
+
+	settings, err := h.settingsService.SetTimezone(authCenterUserID.(string), req.Timezone)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTimezone) {
+			c.JSON(http.StatusBadRequest, Response{
+				Code:    400,
+				Message: "Invalid timezone",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, Response{
+			Code:    500,
+			Message: "Failed to update settings",
+			Data:    err.Error(),
+		})
+		return
+	}
+
+	response := h.settingsService.ToResponse(settings)
+	c.JSON(http.StatusOK, Response{
+		Code:    0,
+		Message: "Settings updated",
+		Data:    response,
+	})
+}
@@ -0,0 +1,166 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Field 可导入的目标字段（名称与 API JSON 字段一致），Aliases 用于自动匹配常见表头
+type Field struct {
+	Name     string
+	Aliases  []string
+	Required bool
+}
+
+// NoteFields 笔记可导入字段
+var NoteFields = []Field{
+	{Name: "url", Aliases: []string{"链接", "笔记链接", "地址", "link"}, Required: true},
+	{Name: "title", Aliases: []string{"标题", "笔记标题"}},
+	{Name: "author", Aliases: []string{"作者", "博主", "博主名称"}},
+	{Name: "authorXhsId", Aliases: []string{"作者ID", "博主ID", "作者小红书ID"}},
+	{Name: "content", Aliases: []string{"正文", "内容", "笔记内容", "描述"}},
+	{Name: "tags", Aliases: []string{"标签", "话题"}},
+	{Name: "noteType", Aliases: []string{"笔记类型", "类型", "type"}},
+	{Name: "likes", Aliases: []string{"点赞", "点赞数", "likedCount"}},
+	{Name: "collects", Aliases: []string{"收藏", "收藏数", "collectedCount"}},
+	{Name: "comments", Aliases: []string{"评论", "评论数", "commentCount"}},
+	{Name: "publishDate", Aliases: []string{"发布时间", "发布日期"}},
+	{Name: "source", Aliases: []string{"来源"}},
+	{Name: "captureTimestamp", Aliases: []string{"采集时间", "采集日期"}},
+	{Name: "coverImageUrl", Aliases: []string{"封面", "封面图", "封面链接", "cover"}},
+	{Name: "imageUrls", Aliases: []string{"图片", "图片链接", "images"}},
+	{Name: "videoUrl", Aliases: []string{"视频", "视频链接", "video"}},
+}
+
+// BloggerFields 博主可导入字段
+var BloggerFields = []Field{
+	{Name: "xhsId", Aliases: []string{"小红书号", "小红书ID", "博主ID", "userId"}, Required: true},
+	{Name: "bloggerName", Aliases: []string{"博主", "博主名称", "昵称", "nickname"}},
+	{Name: "avatarUrl", Aliases: []string{"头像", "头像链接", "avatar"}},
+	{Name: "description", Aliases: []string{"简介", "描述", "desc"}},
+	{Name: "followersCount", Aliases: []string{"粉丝", "粉丝数", "fans"}},
+	{Name: "followingCount", Aliases: []string{"关注", "关注数", "following"}},
+	{Name: "likesCollectsCount", Aliases: []string{"获赞与收藏", "获赞与收藏数", "获赞"}},
+	{Name: "ipLocation", Aliases: []string{"IP属地", "IP", "属地"}},
+	{Name: "verified", Aliases: []string{"认证", "是否认证"}},
+	{Name: "verifiedLabel", Aliases: []string{"认证信息", "认证说明"}},
+	{Name: "category", Aliases: []string{"分类", "领域", "类目"}},
+	{Name: "bloggerUrl", Aliases: []string{"主页", "主页链接", "博主链接"}},
+	{Name: "captureTimestamp", Aliases: []string{"采集时间", "采集日期"}},
+}
+
+// Mapping 列映射：文件列名 -> 目标字段
+type Mapping map[string]string
+
+// BuildMapping 生成列映射
+// explicit 为调用方指定的映射（目标字段为空表示忽略该列），未指定的列按字段名/别名自动匹配；
+// 同一目标字段只能由一列提供
+func BuildMapping(columns []string, fields []Field, explicit map[string]string) (Mapping, error) {
+	known := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		known[f.Name] = true
+	}
+	hasColumn := make(map[string]bool, len(columns))
+	for _, c := range columns {
+		hasColumn[c] = true
+	}
+
+	m := make(Mapping)
+	used := make(map[string]string)
+	for column, field := range explicit {
+		if !hasColumn[column] {
+			return nil, fmt.Errorf("%w: mapping refers to unknown column %q", ErrInvalidMapping, column)
+		}
+		if field == "" {
+			continue
+		}
+		if !known[field] {
+			return nil, fmt.Errorf("%w: mapping refers to unknown field %q", ErrInvalidMapping, field)
+		}
+		if prev, ok := used[field]; ok {
+			return nil, fmt.Errorf("%w: field %q is mapped from both %q and %q", ErrInvalidMapping, field, prev, column)
+		}
+		m[column] = field
+		used[field] = column
+	}
+
+	for _, column := range columns {
+		if _, ok := explicit[column]; ok {
+			continue
+		}
+		field := matchField(column, fields)
+		if field == "" {
+			continue
+		}
+		if _, ok := used[field]; ok {
+			continue
+		}
+		m[column] = field
+		used[field] = column
+	}
+
+	for _, f := range fields {
+		if f.Required {
+			if _, ok := used[f.Name]; !ok {
+				return nil, fmt.Errorf("%w: required field %q is not mapped", ErrInvalidMapping, f.Name)
+			}
+		}
+	}
+	return m, nil
+}
+
+// matchField 按字段名或别名匹配列名（忽略大小写、空格、下划线和连字符）
+func matchField(column string, fields []Field) string {
+	key := normalizeHeader(column)
+	if key == "" {
+		return ""
+	}
+	for _, f := range fields {
+		if normalizeHeader(f.Name) == key {
+			return f.Name
+		}
+		for _, alias := range f.Aliases {
+			if normalizeHeader(alias) == key {
+				return f.Name
+			}
+		}
+	}
+	return ""
+}
+
+func normalizeHeader(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '_', '-', '\t':
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(s)))
+}
+
+// Unmapped 返回未映射的列
+func (m Mapping) Unmapped(columns []string) []string {
+	unmapped := []string{}
+	for _, c := range columns {
+		if _, ok := m[c]; !ok {
+			unmapped = append(unmapped, c)
+		}
+	}
+	return unmapped
+}
+
+// Record 单行数据：目标字段 -> 原始值（已去除首尾空白）
+type Record map[string]string
+
+// Apply 按映射把一行数据转为 Record
+func (m Mapping) Apply(columns []string, row []string) Record {
+	rec := make(Record, len(m))
+	for i, column := range columns {
+		field, ok := m[column]
+		if !ok || i >= len(row) {
+			continue
+		}
+		rec[field] = strings.TrimSpace(row[i])
+	}
+	return rec
+}
@@ -0,0 +1,71 @@
+package importer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBuildMapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		columns  []string
+		explicit map[string]string
+		want     Mapping
+		wantErr  bool
+	}{
+		{
+			name:    "aliases and normalized names",
+			columns: []string{"笔记链接", "标题", "Liked Count", "cover_image_url", "备注"},
+			want:    Mapping{"笔记链接": "url", "标题": "title", "Liked Count": "likes", "cover_image_url": "coverImageUrl"},
+		},
+		{
+			name:    "first matching column wins",
+			columns: []string{"链接", "地址", "url"},
+			want:    Mapping{"链接": "url"},
+		},
+		{
+			name:     "explicit overrides and ignores",
+			columns:  []string{"A", "链接", "标题"},
+			explicit: map[string]string{"A": "url", "标题": ""},
+			want:     Mapping{"A": "url"},
+		},
+		{name: "missing required", columns: []string{"标题"}, wantErr: true},
+		{name: "unknown column", columns: []string{"url"}, explicit: map[string]string{"B": "title"}, wantErr: true},
+		{name: "unknown field", columns: []string{"url", "B"}, explicit: map[string]string{"B": "password"}, wantErr: true},
+		{name: "duplicate field", columns: []string{"A", "B"}, explicit: map[string]string{"A": "url", "B": "url"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildMapping(tt.columns, NoteFields, tt.explicit)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidMapping) {
+					t.Fatalf("err = %v, want ErrInvalidMapping", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mapping = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMappingApply(t *testing.T) {
+	columns := []string{"链接", "备注", "标题", "点赞"}
+	m, err := BuildMapping(columns, NoteFields, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Unmapped(columns); !reflect.DeepEqual(got, []string{"备注"}) {
+		t.Errorf("Unmapped = %q", got)
+	}
+	got := m.Apply(columns, []string{" https://a ", "x", "标题一"})
+	want := Record{"url": "https://a", "title": "标题一"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,212 @@
+// Package importer 解析导入文件（CSV / XLSX / JSONL）并完成列映射
+// 只负责把文件转为「目标字段 -> 原始字符串」的行，校验和入库由 service 层完成
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported import format")
+	ErrEmptyFile         = errors.New("import file has no data rows")
+	ErrTooManyRows       = errors.New("import file has too many rows")
+	ErrInvalidFile       = errors.New("invalid import file")
+	ErrInvalidMapping    = errors.New("invalid column mapping")
+)
+
+// MaxRows 单次导入的最大行数
+const MaxRows = 10000
+
+// Format 导入文件格式
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatXLSX  Format = "xlsx"
+	FormatJSONL Format = "jsonl"
+)
+
+// DetectFormat 根据显式参数或文件扩展名判断格式
+func DetectFormat(explicit, filename string) (Format, error) {
+	f := strings.ToLower(strings.TrimSpace(explicit))
+	if f == "" {
+		f = strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
+	}
+	switch f {
+	case "csv":
+		return FormatCSV, nil
+	case "xlsx":
+		return FormatXLSX, nil
+	case "jsonl", "ndjson":
+		return FormatJSONL, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// Table 解析后的表格：表头 + 数据行（与表头对齐）
+type Table struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// ReadTable 读取导入文件
+func ReadTable(format Format, data []byte) (*Table, error) {
+	var (
+		t   *Table
+		err error
+	)
+	switch format {
+	case FormatCSV:
+		t, err = readCSV(data)
+	case FormatXLSX:
+		t, err = readXLSX(data)
+	case FormatJSONL:
+		t, err = readJSONL(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(t.Rows) == 0 {
+		return nil, ErrEmptyFile
+	}
+	if len(t.Rows) > MaxRows {
+		return nil, ErrTooManyRows
+	}
+	return t, nil
+}
+
+// fromRecords 首行为表头，去除空行，短行补齐
+func fromRecords(records [][]string) *Table {
+	t := &Table{}
+	if len(records) == 0 {
+		return t
+	}
+	for _, h := range records[0] {
+		t.Columns = append(t.Columns, strings.TrimSpace(h))
+	}
+	for _, rec := range records[1:] {
+		if isBlank(rec) {
+			continue
+		}
+		row := make([]string, len(t.Columns))
+		copy(row, rec)
+		t.Rows = append(t.Rows, row)
+	}
+	return t
+}
+
+func isBlank(rec []string) bool {
+	for _, v := range rec {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func readCSV(data []byte) (*Table, error) {
+	// 去掉 Excel 导出的 UTF-8 BOM
+	data = bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF"))
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	return fromRecords(records), nil
+}
+
+// readJSONL 每行一个 JSON 对象，表头为所有对象键的并集（按首次出现的行排列，同一行内按键名排序）
+// 数组值按换行拼接，其余非字符串值按 JSON 文本保存
+func readJSONL(data []byte) (*Table, error) {
+	var objects []map[string]json.RawMessage
+	index := make(map[string]int)
+	t := &Table{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var obj map[string]json.RawMessage
+		dec := json.NewDecoder(strings.NewReader(text))
+		dec.UseNumber()
+		if err := dec.Decode(&obj); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidFile, line, err)
+		}
+		var added []string
+		for k := range obj {
+			if _, ok := index[k]; !ok {
+				added = append(added, k)
+			}
+		}
+		sort.Strings(added)
+		for _, k := range added {
+			index[k] = len(t.Columns)
+			t.Columns = append(t.Columns, k)
+		}
+		objects = append(objects, obj)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	for _, obj := range objects {
+		row := make([]string, len(t.Columns))
+		for k, raw := range obj {
+			row[index[k]] = jsonValueString(raw)
+		}
+		if !isBlank(row) {
+			t.Rows = append(t.Rows, row)
+		}
+	}
+	return t, nil
+}
+
+func jsonValueString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var list []interface{}
+	if err := json.Unmarshal(raw, &list); err == nil {
+		parts := make([]string, 0, len(list))
+		for _, v := range list {
+			parts = append(parts, fmt.Sprint(v))
+		}
+		return strings.Join(parts, "\n")
+	}
+	if string(raw) == "null" {
+		return ""
+	}
+	return string(raw)
+}
+
+// ReadAll 读取上传内容，超过 limit 字节返回错误
+func ReadAll(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: exceeds %d bytes", ErrInvalidFile, limit)
+	}
+	return data, nil
+}
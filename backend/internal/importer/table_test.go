@@ -0,0 +1,109 @@
+package importer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		explicit, filename string
+		want               Format
+		wantErr            bool
+	}{
+		{"", "notes.csv", FormatCSV, false},
+		{"", "Notes.XLSX", FormatXLSX, false},
+		{"", "export.ndjson", FormatJSONL, false},
+		{" JSONL ", "export.txt", FormatJSONL, false},
+		{"csv", "notes.xlsx", FormatCSV, false},
+		{"", "notes.xls", "", true},
+		{"", "notes", "", true},
+		{"pdf", "notes.csv", "", true},
+	}
+	for _, tt := range tests {
+		got, err := DetectFormat(tt.explicit, tt.filename)
+		if tt.wantErr {
+			if !errors.Is(err, ErrUnsupportedFormat) {
+				t.Errorf("DetectFormat(%q, %q) err = %v, want ErrUnsupportedFormat", tt.explicit, tt.filename, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("DetectFormat(%q, %q) = %q, %v, want %q", tt.explicit, tt.filename, got, err, tt.want)
+		}
+	}
+}
+
+func TestReadTableCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    *Table
+		wantErr error
+	}{
+		{
+			name: "bom, blank and short rows",
+			data: "\xEF\xBB\xBF 标题 ,链接\n笔记一,https://a\n,\n笔记二\n",
+			want: &Table{Columns: []string{"标题", "链接"}, Rows: [][]string{{"笔记一", "https://a"}, {"笔记二", ""}}},
+		},
+		{
+			name: "quoted newlines",
+			data: "title,tags\n\"a\",\"x\ny\"\n",
+			want: &Table{Columns: []string{"title", "tags"}, Rows: [][]string{{"a", "x\ny"}}},
+		},
+		{name: "header only", data: "title,url\n", wantErr: ErrEmptyFile},
+		{name: "empty", data: "", wantErr: ErrEmptyFile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadTable(FormatCSV, []byte(tt.data))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("table = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadTableTooManyRows(t *testing.T) {
+	data := "url\n" + strings.Repeat("https://a\n", MaxRows+1)
+	if _, err := ReadTable(FormatCSV, []byte(data)); !errors.Is(err, ErrTooManyRows) {
+		t.Errorf("err = %v, want ErrTooManyRows", err)
+	}
+}
+
+func TestReadTableJSONL(t *testing.T) {
+	data := `{"url":"https://a","likes":12,"tags":["x","y"]}
+
+{"title":"二","url":"https://b","extra":null}
+{}
+`
+	got, err := ReadTable(FormatJSONL, []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Table{
+		Columns: []string{"likes", "tags", "url", "extra", "title"},
+		Rows: [][]string{
+			{"12", "x\ny", "https://a", "", ""},
+			{"", "", "https://b", "", "二"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("table = %+v, want %+v", got, want)
+	}
+
+	if _, err := ReadTable(FormatJSONL, []byte("{\"url\":1}\nnot json\n")); !errors.Is(err, ErrInvalidFile) || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("err = %v, want ErrInvalidFile at line 2", err)
+	}
+}
@@ -0,0 +1,136 @@
+package importer
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseCount 解析互动数，兼容「1.2万」「3w」「1,234」「10k+」等写法，空值为 0
+func ParseCount(s string) (int32, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	s = strings.TrimSuffix(s, "+")
+	s = strings.ReplaceAll(s, ",", "")
+	s = strings.ReplaceAll(s, " ", "")
+
+	multiplier := 1.0
+	lower := strings.ToLower(s)
+	for _, unit := range []struct {
+		suffix string
+		factor float64
+	}{{"万", 1e4}, {"w", 1e4}, {"千", 1e3}, {"k", 1e3}} {
+		if strings.HasSuffix(lower, unit.suffix) {
+			lower = strings.TrimSuffix(lower, unit.suffix)
+			multiplier = unit.factor
+			break
+		}
+	}
+
+	v, err := strconv.ParseFloat(lower, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid count %q", s)
+	}
+	v = math.Round(v * multiplier)
+	if v > math.MaxInt32 {
+		return 0, fmt.Errorf("count %q out of range", s)
+	}
+	return int32(v), nil
+}
+
+// ParseBool 解析是/否，兼容「true」「1」「是」「已认证」等写法，空值为 false
+func ParseBool(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "false", "0", "no", "n", "否", "未认证":
+		return false, nil
+	case "true", "1", "yes", "y", "是", "已认证":
+		return true, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", s)
+}
+
+// excelEpoch Excel 日期序列号的起点（1900 日期系统，已包含 1900-02-29 的历史偏差）
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+var timestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/01/02",
+	"2006年01月02日 15:04",
+	"2006年01月02日",
+	"2006年1月2日",
+}
+
+// ParseTimestamp 解析时间为毫秒时间戳，空值为 0
+// 支持毫秒/秒时间戳、Excel 日期序列号和常见日期格式（不带时区的按 loc 解释）
+func ParseTimestamp(s string, loc *time.Location) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil && n >= 0 {
+		switch {
+		case n == 0:
+			return 0, nil
+		case n >= 1e11:
+			return n, nil
+		case n >= 1e9:
+			return n * 1000, nil
+		}
+	}
+	if v, err := strconv.ParseFloat(s, 64); err == nil && v > 0 && v < 1e6 {
+		days := math.Floor(v)
+		nanos := int64(math.Round((v-days)*86400)) * int64(time.Second)
+		t := excelEpoch.AddDate(0, 0, int(days)).Add(time.Duration(nanos))
+		local := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+		return local.UnixMilli(), nil
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t.UnixMilli(), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid time %q", s)
+}
+
+// SplitList 拆分列表字段（换行、逗号、顿号、分号、竖线），去除空项和首尾空白
+func SplitList(s string) []string {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		switch r {
+		case '\n', '\r', ',', '，', '、', ';', '；', '|':
+			return true
+		}
+		return false
+	})
+	list := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
+// SplitTags 拆分标签，兼容「#标签1 #标签2」写法
+func SplitTags(s string) []string {
+	if strings.Contains(s, "#") {
+		s = strings.NewReplacer("#", "\n", "[话题]", "").Replace(s)
+	}
+	tags := []string{}
+	seen := make(map[string]bool)
+	for _, t := range SplitList(s) {
+		if !seen[t] {
+			seen[t] = true
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,123 @@
+package importer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseCount(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int32
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"  ", 0, false},
+		{"1234", 1234, false},
+		{"1,234", 1234, false},
+		{"1.2万", 12000, false},
+		{"3w", 30000, false},
+		{"3W", 30000, false},
+		{"10k+", 10000, false},
+		{"2.5千", 2500, false},
+		{"1 000", 1000, false},
+		{"abc", 0, true},
+		{"-5", 0, true},
+		{"300000万", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCount(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseCount(%q) = %d, %v, want %d (err %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseBool(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    bool
+		wantErr bool
+	}{
+		{"", false, false},
+		{"TRUE", true, false},
+		{"1", true, false},
+		{"是", true, false},
+		{" 已认证 ", true, false},
+		{"否", false, false},
+		{"no", false, false},
+		{"maybe", false, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseBool(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseBool(%q) = %v, %v, want %v (err %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	ms := func(y int, m time.Month, d, h, min int) int64 {
+		return time.Date(y, m, d, h, min, 0, 0, loc).UnixMilli()
+	}
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"0", 0, false},
+		{"1704067200000", 1704067200000, false},
+		{"1704067200", 1704067200000, false},
+		{"45292", ms(2024, 1, 1, 0, 0), false},
+		{"45292.5", ms(2024, 1, 1, 12, 0), false},
+		{"2024-01-01T08:00:00+08:00", 1704067200000, false},
+		{"2024-01-01 09:30", ms(2024, 1, 1, 9, 30), false},
+		{"2024/01/01", ms(2024, 1, 1, 0, 0), false},
+		{"2024年1月2日", ms(2024, 1, 2, 0, 0), false},
+		{"2024年01月02日 15:04", ms(2024, 1, 2, 15, 4), false},
+		{"yesterday", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimestamp(tt.in, loc)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseTimestamp(%q) = %d, %v, want %d (err %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", []string{}},
+		{"a, b，c、d;e；f|g", []string{"a", "b", "c", "d", "e", "f", "g"}},
+		{"https://a\r\nhttps://b\n\n", []string{"https://a", "https://b"}},
+		{" , ,", []string{}},
+	}
+	for _, tt := range tests {
+		if got := SplitList(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitList(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSplitTags(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", []string{}},
+		{"穿搭,通勤,穿搭", []string{"穿搭", "通勤"}},
+		{"#穿搭[话题]# #通勤[话题]#", []string{"穿搭", "通勤"}},
+		{"#旅行 #日记 #旅行", []string{"旅行", "日记"}},
+	}
+	for _, tt := range tests {
+		if got := SplitTags(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitTags(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// xlsx 读取：只读第一个工作表，支持共享字符串、内联字符串与数值单元格
+// 日期单元格以 Excel 序列号形式返回，由字段解析时再转换
+
+const (
+	// maxXLSXColumn Excel 的最大列（XFD），超出的单元格引用视为无效文件
+	maxXLSXColumn = 16383
+	// maxXLSXPartBytes 单个 XML 部件解压后的大小上限；上传大小限制只作用于压缩后的文件
+	maxXLSXPartBytes = 100 << 20
+)
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Items []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxRichText struct {
+	T string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (s xlsxRichText) text() string {
+	if len(s.R) == 0 {
+		return s.T
+	}
+	var b strings.Builder
+	b.WriteString(s.T)
+	for _, r := range s.R {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxRichText `xml:"si"`
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string       `xml:"r,attr"`
+			Type   string       `xml:"t,attr"`
+			Value  string       `xml:"v"`
+			Inline xlsxRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readXLSX(data []byte) (*Table, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared xlsxSharedStrings
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeZipXML(f, &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("%w: missing %s", ErrInvalidFile, sheetPath)
+	}
+	var sheet xlsxSheet
+	if err := decodeZipXML(f, &sheet); err != nil {
+		return nil, err
+	}
+
+	records := make([][]string, 0, len(sheet.Rows))
+	for _, row := range sheet.Rows {
+		var rec []string
+		for i, c := range row.Cells {
+			col := i
+			if c.Ref != "" {
+				idx, err := columnIndex(c.Ref)
+				if err != nil {
+					return nil, err
+				}
+				if idx >= 0 {
+					col = idx
+				}
+			}
+			for len(rec) <= col {
+				rec = append(rec, "")
+			}
+			switch c.Type {
+			case "s":
+				var idx int
+				if _, err := fmt.Sscan(c.Value, &idx); err == nil && idx >= 0 && idx < len(shared.Items) {
+					rec[col] = shared.Items[idx].text()
+				}
+			case "inlineStr":
+				rec[col] = c.Inline.text()
+			default:
+				rec[col] = c.Value
+			}
+		}
+		records = append(records, rec)
+	}
+	return fromRecords(records), nil
+}
+
+// firstSheetPath 通过 workbook.xml 与其关系文件定位第一个工作表
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	const fallback = "xl/worksheets/sheet1.xml"
+	wbFile, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", fmt.Errorf("%w: missing workbook", ErrInvalidFile)
+	}
+	var wb xlsxWorkbook
+	if err := decodeZipXML(wbFile, &wb); err != nil {
+		return "", err
+	}
+	relsFile, ok := files["xl/_rels/workbook.xml.rels"]
+	if !ok || len(wb.Sheets) == 0 {
+		return fallback, nil
+	}
+	var rels xlsxRelationships
+	if err := decodeZipXML(relsFile, &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Items {
+		if rel.ID != wb.Sheets[0].RID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return fallback, nil
+}
+
+func decodeZipXML(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	// 多读 1 字节用于判断是否超限，超限时解码会因截断报错
+	lr := &io.LimitedReader{R: rc, N: maxXLSXPartBytes + 1}
+	if err := xml.NewDecoder(lr).Decode(v); err != nil {
+		if lr.N <= 0 {
+			return fmt.Errorf("%w: %s exceeds %d bytes uncompressed", ErrInvalidFile, f.Name, maxXLSXPartBytes)
+		}
+		return fmt.Errorf("%w: %s: %v", ErrInvalidFile, f.Name, err)
+	}
+	if lr.N <= 0 {
+		return fmt.Errorf("%w: %s exceeds %d bytes uncompressed", ErrInvalidFile, f.Name, maxXLSXPartBytes)
+	}
+	return nil
+}
+
+// columnIndex 单元格引用转列序号：A1 -> 0, AA3 -> 26；没有列字母时返回 -1
+// 超出 XFD 的引用返回 ErrInvalidFile
+func columnIndex(ref string) (int, error) {
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		n = n*26 + int(r-'A'+1)
+		if n-1 > maxXLSXColumn {
+			return 0, fmt.Errorf("%w: cell reference %q is beyond column XFD", ErrInvalidFile, ref)
+		}
+	}
+	return n - 1, nil
+}
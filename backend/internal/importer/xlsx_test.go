@@ -0,0 +1,168 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// buildXLSX 按「文件名 -> 内容」生成最小 xlsx 压缩包
+func buildXLSX(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+const (
+	testWorkbook = `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="导出" sheetId="1" r:id="rId2"/></sheets></workbook>`
+	testRels = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Target="styles.xml"/>
+<Relationship Id="rId2" Target="worksheets/data.xml"/></Relationships>`
+	testSharedStrings = `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<si><t>标题</t></si><si><t>链接</t></si><si><r><t>富</t></r><r><t>文本</t></r></si></sst>`
+)
+
+func sheetXML(rows string) string {
+	return `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + rows + `</sheetData></worksheet>`
+}
+
+func TestReadTableXLSX(t *testing.T) {
+	data := buildXLSX(t, map[string]string{
+		"xl/workbook.xml":            testWorkbook,
+		"xl/_rels/workbook.xml.rels": testRels,
+		"xl/sharedStrings.xml":       testSharedStrings,
+		"xl/worksheets/data.xml": sheetXML(`
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>
+<row r="2"><c r="A2" t="s"><v>2</v></c><c r="C2" t="inlineStr"><is><t>https://a</t></is></c></row>
+<row r="3"><c r="B3"><v></v></c></row>
+<row r="4"><c><v>45292</v></c><c><v>1.5</v></c><c t="str"><v>https://b</v></c></row>`),
+	})
+	got, err := ReadTable(FormatXLSX, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Table{
+		Columns: []string{"标题", "", "链接"},
+		Rows: [][]string{
+			{"富文本", "", "https://a"},
+			{"45292", "1.5", "https://b"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("table = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadTableXLSXFallbackSheet(t *testing.T) {
+	data := buildXLSX(t, map[string]string{
+		"xl/workbook.xml":          `<workbook><sheets/></workbook>`,
+		"xl/worksheets/sheet1.xml": sheetXML(`<row><c t="inlineStr"><is><t>url</t></is></c></row><row><c t="inlineStr"><is><t>https://a</t></is></c></row>`),
+	})
+	got, err := ReadTable(FormatXLSX, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Rows, [][]string{{"https://a"}}) {
+		t.Errorf("rows = %q", got.Rows)
+	}
+}
+
+func TestReadTableXLSXInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  []byte
+		match string
+	}{
+		{"not a zip", []byte("hello"), ""},
+		{"missing workbook", buildXLSX(t, map[string]string{"xl/worksheets/sheet1.xml": sheetXML("")}), "missing workbook"},
+		{"missing sheet", buildXLSX(t, map[string]string{"xl/workbook.xml": testWorkbook, "xl/_rels/workbook.xml.rels": testRels}), "missing xl/worksheets/data.xml"},
+		{"column beyond XFD", buildXLSX(t, map[string]string{
+			"xl/workbook.xml":          `<workbook/>`,
+			"xl/worksheets/sheet1.xml": sheetXML(`<row><c r="XFE1"><v>1</v></c></row>`),
+		}), "beyond column XFD"},
+		{"malformed xml", buildXLSX(t, map[string]string{"xl/workbook.xml": `<workbook>`}), "xl/workbook.xml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadTable(FormatXLSX, tt.data)
+			if !errors.Is(err, ErrInvalidFile) || !strings.Contains(err.Error(), tt.match) {
+				t.Errorf("err = %v, want ErrInvalidFile containing %q", err, tt.match)
+			}
+		})
+	}
+}
+
+func TestReadTableXLSXPartSizeLimit(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a large compressed part")
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{"xl/workbook.xml": `<workbook/>`} {
+		w, _ := zw.Create(name)
+		w.Write([]byte(content))
+	}
+	// 高度可压缩的超大工作表：压缩后很小，解压后超过上限
+	w, _ := zw.Create("xl/worksheets/sheet1.xml")
+	w.Write([]byte(`<worksheet><sheetData><row><c><v>`))
+	chunk := bytes.Repeat([]byte("0"), 1<<20)
+	for i := 0; i <= maxXLSXPartBytes>>20; i++ {
+		w.Write(chunk)
+	}
+	w.Write([]byte(`</v></c></row></sheetData></worksheet>`))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := ReadTable(FormatXLSX, buf.Bytes())
+	if !errors.Is(err, ErrInvalidFile) || !strings.Contains(err.Error(), "uncompressed") {
+		t.Errorf("err = %v, want uncompressed size error", err)
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    int
+		wantErr bool
+	}{
+		{"A1", 0, false},
+		{"Z9", 25, false},
+		{"AA3", 26, false},
+		{"AZ1", 51, false},
+		{"XFD1048576", maxXLSXColumn, false},
+		{"12", -1, false},
+		{"", -1, false},
+		{"XFE1", 0, true},
+		{"ZZZZZZZZZZZZZZ1", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := columnIndex(tt.ref)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidFile) {
+				t.Errorf("columnIndex(%q) err = %v, want ErrInvalidFile", tt.ref, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("columnIndex(%q) = %d, %v, want %d", tt.ref, got, err, tt.want)
+		}
+	}
+}
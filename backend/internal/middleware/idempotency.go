@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/keenchase/edit-business/internal/service"
+)
+
+// maxIdempotencyKeyLength Idempotency-Key 请求头的最大长度
+const maxIdempotencyKeyLength = 255
+
+// replayedHeaders 随响应记录并在重放时返回的响应头
+var replayedHeaders = []string{
+	"Content-Type",
+	"ETag",
+	"Location",
+	"X-Quota-Limit",
+	"X-Quota-Remaining",
+	"X-Quota-Reset",
+	"X-Quota-Batch-Limit",
+}
+
+// IdempotencyStore 幂等键的占用、记录和释放（由 service.IdempotencyService 实现）
+type IdempotencyStore interface {
+	Begin(req *service.IdempotentRequest) (*service.StoredResponse, error)
+	Complete(req *service.IdempotentRequest, resp *service.StoredResponse) error
+	Release(req *service.IdempotentRequest) error
+}
+
+// IdempotencyMiddleware 处理 Idempotency-Key 请求头（用于插件同步接口，需在认证中间件之后）
+// 未携带该请求头时直接放行；重复请求重放首次的状态码、响应体和 replayedHeaders 中的响应头（另加 Idempotent-Replayed: true），
+// 同一个键用于不同的请求或首次请求仍在处理中时返回 409；首次请求返回 5xx 或 panic 时释放幂等键，允许客户端重试
+func IdempotencyMiddleware(idempotencyService IdempotencyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"code":    400,
+				"message": "Idempotency-Key is too long",
+			})
+			c.Abort()
+			return
+		}
+
+		authCenterUserID := c.GetString("authCenterUserID")
+		if authCenterUserID == "" {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"code":    400,
+				"message": "failed to read request body",
+			})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		req := &service.IdempotentRequest{
+			AuthCenterUserID: authCenterUserID,
+			Key:              key,
+			RequestHash:      requestHash(c.Request.Method, c.FullPath(), body),
+		}
+		stored, err := idempotencyService.Begin(req)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, service.ErrIdempotencyKeyReused) || errors.Is(err, service.ErrIdempotencyKeyInProgress) {
+				status = http.StatusConflict
+			}
+			c.JSON(status, gin.H{
+				"success": false,
+				"code":    status,
+				"message": err.Error(),
+			})
+			c.Abort()
+			return
+		}
+		if stored != nil {
+			contentType := "application/json; charset=utf-8"
+			for name, value := range stored.Header {
+				if name == "Content-Type" {
+					contentType = value
+					continue
+				}
+				c.Header(name, value)
+			}
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(stored.StatusCode, contentType, stored.Body)
+			c.Abort()
+			return
+		}
+
+		release := func() {
+			if err := idempotencyService.Release(req); err != nil {
+				log.Printf("[Idempotency] failed to release key %s: %v", key, err)
+			}
+		}
+		// handler panic 时释放幂等键后继续向上抛出，由 Recovery 中间件返回 500
+		defer func() {
+			if r := recover(); r != nil {
+				release()
+				panic(r)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// 服务端错误不记录，释放幂等键以便客户端重试
+		if recorder.Status() >= http.StatusInternalServerError {
+			release()
+			return
+		}
+		resp := &service.StoredResponse{
+			StatusCode: recorder.Status(),
+			Header:     make(map[string]string),
+			Body:       recorder.body.Bytes(),
+		}
+		for _, name := range replayedHeaders {
+			if v := recorder.Header().Get(name); v != "" {
+				resp.Header[name] = v
+			}
+		}
+		if err := idempotencyService.Complete(req, resp); err != nil {
+			log.Printf("[Idempotency] failed to store response for key %s: %v", key, err)
+		}
+	}
+}
+
+// requestHash 请求方法、路由和请求体的 SHA-256
+func requestHash(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder 在写出响应的同时保留一份响应体
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,210 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/keenchase/edit-business/internal/service"
+)
+
+// memoryIdempotencyStore 按 IdempotencyService 的约定在内存中保存幂等键
+type memoryIdempotencyStore struct {
+	mu       sync.Mutex
+	records  map[string]*memoryRecord
+	released int
+}
+
+type memoryRecord struct {
+	hash string
+	resp *service.StoredResponse // nil 表示仍在处理中
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: make(map[string]*memoryRecord)}
+}
+
+func (s *memoryIdempotencyStore) id(req *service.IdempotentRequest) string {
+	return req.AuthCenterUserID + "\x00" + req.Key
+}
+
+func (s *memoryIdempotencyStore) Begin(req *service.IdempotentRequest) (*service.StoredResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec, ok := s.records[s.id(req)]
+	if !ok {
+		s.records[s.id(req)] = &memoryRecord{hash: req.RequestHash}
+		return nil, nil
+	}
+	if rec.hash != req.RequestHash {
+		return nil, service.ErrIdempotencyKeyReused
+	}
+	if rec.resp == nil {
+		return nil, service.ErrIdempotencyKeyInProgress
+	}
+	return rec.resp, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(req *service.IdempotentRequest, resp *service.StoredResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[s.id(req)].resp = resp
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(req *service.IdempotentRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rec, ok := s.records[s.id(req)]; ok && rec.resp == nil {
+		delete(s.records, s.id(req))
+		s.released++
+	}
+	return nil
+}
+
+// newIdempotencyRouter POST /notes 经过幂等中间件，handler 由测试提供
+func newIdempotencyRouter(store IdempotencyStore, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(gin.CustomRecovery(func(c *gin.Context, _ interface{}) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	r.POST("/notes", func(c *gin.Context) {
+		c.Set("authCenterUserID", "user-1")
+	}, IdempotencyMiddleware(store), handler)
+	return r
+}
+
+func post(r http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/notes", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyReplaysResponseAndHeaders(t *testing.T) {
+	calls := 0
+	r := newIdempotencyRouter(newMemoryIdempotencyStore(), func(c *gin.Context) {
+		calls++
+		c.Header("ETag", `"3"`)
+		c.Header("X-Quota-Remaining", "49")
+		c.Header("X-Request-Id", "not-replayed")
+		c.JSON(http.StatusCreated, gin.H{"id": "note-1", "call": calls})
+	})
+
+	first := post(r, "k1", `{"url":"https://a"}`)
+	second := post(r, "k1", `{"url":"https://a"}`)
+
+	if calls != 1 {
+		t.Fatalf("handler calls = %d, want 1", calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Errorf("replay = %d %s, want %d %s", second.Code, second.Body, first.Code, first.Body)
+	}
+	for name, want := range map[string]string{
+		"ETag":                `"3"`,
+		"X-Quota-Remaining":   "49",
+		"Content-Type":        "application/json; charset=utf-8",
+		"Idempotent-Replayed": "true",
+		"X-Request-Id":        "",
+	} {
+		if got := second.Header().Get(name); got != want {
+			t.Errorf("replayed %s = %q, want %q", name, got, want)
+		}
+	}
+	if first.Header().Get("Idempotent-Replayed") != "" {
+		t.Error("first response should not be marked as replayed")
+	}
+}
+
+func TestIdempotencyRejectsKeyReuseWithDifferentBody(t *testing.T) {
+	calls := 0
+	r := newIdempotencyRouter(newMemoryIdempotencyStore(), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	post(r, "k1", `{"url":"https://a"}`)
+	w := post(r, "k1", `{"url":"https://b"}`)
+	if w.Code != http.StatusConflict {
+		t.Errorf("status = %d, want 409", w.Code)
+	}
+	if calls != 1 {
+		t.Errorf("handler calls = %d, want 1", calls)
+	}
+
+	// 其他键和不带键的请求不受影响
+	if w := post(r, "k2", `{"url":"https://b"}`); w.Code != http.StatusOK {
+		t.Errorf("other key status = %d", w.Code)
+	}
+	if w := post(r, "", `{"url":"https://b"}`); w.Code != http.StatusOK {
+		t.Errorf("no key status = %d", w.Code)
+	}
+}
+
+func TestIdempotencyReleasesKeyOnFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		fail gin.HandlerFunc
+	}{
+		{"server error", func(c *gin.Context) { c.JSON(http.StatusInternalServerError, gin.H{"error": "db down"}) }},
+		{"panic", func(c *gin.Context) { panic("boom") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryIdempotencyStore()
+			failing := true
+			r := newIdempotencyRouter(store, func(c *gin.Context) {
+				if failing {
+					tt.fail(c)
+					return
+				}
+				c.JSON(http.StatusCreated, gin.H{"id": "note-1"})
+			})
+
+			if w := post(r, "k1", `{"url":"https://a"}`); w.Code != http.StatusInternalServerError {
+				t.Fatalf("first status = %d, want 500", w.Code)
+			}
+			if store.released != 1 {
+				t.Errorf("released = %d, want 1", store.released)
+			}
+
+			// 同一个键重试会重新执行，而不是 409
+			failing = false
+			w := post(r, "k1", `{"url":"https://a"}`)
+			if w.Code != http.StatusCreated || w.Header().Get("Idempotent-Replayed") != "" {
+				t.Errorf("retry = %d replayed=%q, want fresh 201", w.Code, w.Header().Get("Idempotent-Replayed"))
+			}
+		})
+	}
+}
+
+func TestIdempotencyStoresClientErrors(t *testing.T) {
+	calls := 0
+	r := newIdempotencyRouter(newMemoryIdempotencyStore(), func(c *gin.Context) {
+		calls++
+		c.Header("X-Quota-Remaining", "0")
+		c.JSON(http.StatusTooManyRequests, gin.H{"message": "limit"})
+	})
+	post(r, "k1", `{}`)
+	w := post(r, "k1", `{}`)
+	if calls != 1 || w.Code != http.StatusTooManyRequests || w.Header().Get("X-Quota-Remaining") != "0" {
+		t.Errorf("calls = %d, replay = %d quota=%q", calls, w.Code, w.Header().Get("X-Quota-Remaining"))
+	}
+}
+
+func TestIdempotencyKeyTooLong(t *testing.T) {
+	r := newIdempotencyRouter(newMemoryIdempotencyStore(), func(c *gin.Context) {
+		t.Error("handler should not run")
+	})
+	if w := post(r, strings.Repeat("k", maxIdempotencyKeyLength+1), `{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
@@ -7,19 +7,28 @@ import (
 	"gorm.io/gorm"
 )
 
-// Blogger 博主信息模型
+// Blogger 博主信息模型（按用户隔离，每个用户各自跟踪）
+// 主页公开数据同时汇总到按小红书号共享的 BloggerProfile，分类、采集时间、回收站和粉丝快照只属于该用户
 type Blogger struct {
 	ID               string      `gorm:"primaryKey;column:id;type:varchar(255)" json:"id"`
-	UserID           string      `gorm:"column:user_id;type:varchar(255);not null;index" json:"userId"`
-	XhsID            string      `gorm:"uniqueIndex;column:xhs_id;type:varchar(50)" json:"xhsId"`
+	UserID           string      `gorm:"column:user_id;type:varchar(255);not null;index;uniqueIndex:idx_bloggers_user_xhs_id_live,priority:1,where:deleted_at IS NULL" json:"userId"`
+	XhsID            string      `gorm:"uniqueIndex:idx_bloggers_user_xhs_id_live,priority:2;column:xhs_id;type:varchar(50)" json:"xhsId"` // 同一用户未删除的博主中唯一
 	BloggerName      string      `gorm:"column:blogger_name;type:varchar(100)" json:"bloggerName"`
 	AvatarURL        string      `gorm:"column:avatar_url;type:varchar(500)" json:"avatarUrl"`
 	Description      string      `gorm:"column:description;type:text" json:"description"`
 	FollowersCount   int32       `gorm:"column:followers_count;type:integer;default:0" json:"followersCount"`
+	FollowingCount   int32       `gorm:"column:following_count;type:integer;default:0" json:"followingCount"`
+	LikesCollectsCount int32     `gorm:"column:likes_collects_count;type:integer;default:0" json:"likesCollectsCount"` // 获赞与收藏
+	IPLocation       string      `gorm:"column:ip_location;type:varchar(50)" json:"ipLocation"`                        // IP 属地
+	Verified         bool        `gorm:"column:verified;type:boolean;default:false" json:"verified"`                   // 是否有认证标识
+	VerifiedLabel    string      `gorm:"column:verified_label;type:varchar(100)" json:"verifiedLabel"`                 // 认证说明，如"小红书认证博主"
+	Category         string      `gorm:"column:category;type:varchar(50)" json:"category"`                             // 博主分类 / 领域
 	BloggerURL       string      `gorm:"column:blogger_url;type:varchar(500)" json:"bloggerUrl"`
 	CaptureTimestamp int64       `gorm:"column:capture_timestamp;type:bigint;not null" json:"captureTimestamp"`
+	Version          int64       `gorm:"column:version;type:bigint;not null;default:1" json:"version"` // 每次写入递增，作为 ETag
 	CreatedAt        time.Time   `gorm:"column:created_at;type:timestamp with time zone;default:now();not null" json:"createdAt"`
 	UpdatedAt        time.Time   `gorm:"column:updated_at;type:timestamp with time zone;default:now();not null" json:"updatedAt"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"` // 软删除：非空表示在回收站中
 }
 
 // TableName 指定表名（复数 + snake_case）
@@ -32,5 +41,8 @@ func (b *Blogger) BeforeCreate(tx *gorm.DB) error {
 	if b.ID == "" {
 		b.ID = fmt.Sprintf("blogger-%d", time.Now().UnixNano())
 	}
+	if b.Version == 0 {
+		b.Version = 1
+	}
 	return nil
 }
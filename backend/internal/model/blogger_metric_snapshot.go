@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BloggerMetricSnapshot 博主粉丝数据快照
+// 每次插件采集（Create/Upsert）都会写入一条，用于还原博主的粉丝增长曲线
+type BloggerMetricSnapshot struct {
+	ID                 string    `gorm:"primaryKey;column:id;type:varchar(255)" json:"id"`
+	BloggerID          string    `gorm:"column:blogger_id;type:varchar(255);not null;index:idx_blogger_metric_snapshots_blogger_created,priority:1" json:"bloggerId"`
+	UserID             string    `gorm:"column:user_id;type:varchar(255);not null;index" json:"userId"`
+	FollowersCount     int32     `gorm:"column:followers_count;type:integer;default:0" json:"followersCount"`
+	FollowingCount     int32     `gorm:"column:following_count;type:integer;default:0" json:"followingCount"`
+	LikesCollectsCount int32     `gorm:"column:likes_collects_count;type:integer;default:0" json:"likesCollectsCount"`
+	CaptureTimestamp   int64     `gorm:"column:capture_timestamp;type:bigint;not null" json:"captureTimestamp"`
+	CreatedAt          time.Time `gorm:"column:created_at;type:timestamp with time zone;default:now();not null;index:idx_blogger_metric_snapshots_blogger_created,priority:2" json:"createdAt"`
+}
+
+// TableName 指定表名（复数 + snake_case）
+func (BloggerMetricSnapshot) TableName() string {
+	return "blogger_metric_snapshots"
+}
+
+// BeforeCreate GORM hook - 快照写入频繁，使用 UUID 避免同一纳秒内 ID 冲突
+func (s *BloggerMetricSnapshot) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// NewBloggerMetricSnapshot 根据博主当前粉丝数据生成快照
+func NewBloggerMetricSnapshot(blogger *Blogger) *BloggerMetricSnapshot {
+	return &BloggerMetricSnapshot{
+		BloggerID:          blogger.ID,
+		UserID:             blogger.UserID,
+		FollowersCount:     blogger.FollowersCount,
+		FollowingCount:     blogger.FollowingCount,
+		LikesCollectsCount: blogger.LikesCollectsCount,
+		CaptureTimestamp:   blogger.CaptureTimestamp,
+	}
+}
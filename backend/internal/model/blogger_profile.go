@@ -0,0 +1,77 @@
+package model
+
+import "time"
+
+// BloggerProfile 博主主页公开数据（按小红书号在用户之间共享）
+// 任一用户通过插件采集博主时以更新的采集数据覆盖（文件导入和手动编辑不会写入）；
+// 用户采集或导入的数据缺少某些字段时用这里的值补全
+type BloggerProfile struct {
+	XhsID              string    `gorm:"primaryKey;column:xhs_id;type:varchar(50)" json:"xhsId"`
+	BloggerName        string    `gorm:"column:blogger_name;type:varchar(100)" json:"bloggerName"`
+	AvatarURL          string    `gorm:"column:avatar_url;type:varchar(500)" json:"avatarUrl"`
+	Description        string    `gorm:"column:description;type:text" json:"description"`
+	FollowersCount     int32     `gorm:"column:followers_count;type:integer;default:0" json:"followersCount"`
+	FollowingCount     int32     `gorm:"column:following_count;type:integer;default:0" json:"followingCount"`
+	LikesCollectsCount int32     `gorm:"column:likes_collects_count;type:integer;default:0" json:"likesCollectsCount"`
+	IPLocation         string    `gorm:"column:ip_location;type:varchar(50)" json:"ipLocation"`
+	Verified           bool      `gorm:"column:verified;type:boolean;default:false" json:"verified"`
+	VerifiedLabel      string    `gorm:"column:verified_label;type:varchar(100)" json:"verifiedLabel"`
+	BloggerURL         string    `gorm:"column:blogger_url;type:varchar(500)" json:"bloggerUrl"`
+	CaptureTimestamp   int64     `gorm:"column:capture_timestamp;type:bigint;not null" json:"captureTimestamp"` // 最近一次采集时间
+	UpdatedAt          time.Time `gorm:"column:updated_at;type:timestamp with time zone;default:now();not null" json:"updatedAt"`
+}
+
+// TableName 指定表名（复数 + snake_case）
+func (BloggerProfile) TableName() string {
+	return "blogger_profiles"
+}
+
+// NewBloggerProfile 从用户采集的博主数据提取公开主页数据
+func NewBloggerProfile(blogger *Blogger) *BloggerProfile {
+	return &BloggerProfile{
+		XhsID:              blogger.XhsID,
+		BloggerName:        blogger.BloggerName,
+		AvatarURL:          blogger.AvatarURL,
+		Description:        blogger.Description,
+		FollowersCount:     blogger.FollowersCount,
+		FollowingCount:     blogger.FollowingCount,
+		LikesCollectsCount: blogger.LikesCollectsCount,
+		IPLocation:         blogger.IPLocation,
+		Verified:           blogger.Verified,
+		VerifiedLabel:      blogger.VerifiedLabel,
+		BloggerURL:         blogger.BloggerURL,
+		CaptureTimestamp:   blogger.CaptureTimestamp,
+	}
+}
+
+// FillBlogger 用公开主页数据补全博主记录中为空的字段
+func (p *BloggerProfile) FillBlogger(blogger *Blogger) {
+	if blogger.BloggerName == "" {
+		blogger.BloggerName = p.BloggerName
+	}
+	if blogger.AvatarURL == "" {
+		blogger.AvatarURL = p.AvatarURL
+	}
+	if blogger.Description == "" {
+		blogger.Description = p.Description
+	}
+	if blogger.FollowersCount == 0 {
+		blogger.FollowersCount = p.FollowersCount
+	}
+	if blogger.FollowingCount == 0 {
+		blogger.FollowingCount = p.FollowingCount
+	}
+	if blogger.LikesCollectsCount == 0 {
+		blogger.LikesCollectsCount = p.LikesCollectsCount
+	}
+	if blogger.IPLocation == "" {
+		blogger.IPLocation = p.IPLocation
+	}
+	if !blogger.Verified && blogger.VerifiedLabel == "" {
+		blogger.Verified = p.Verified
+		blogger.VerifiedLabel = p.VerifiedLabel
+	}
+	if blogger.BloggerURL == "" {
+		blogger.BloggerURL = p.BloggerURL
+	}
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Collection 用户自建的笔记收藏夹（看板），与笔记多对多
+type Collection struct {
+	ID          string    `gorm:"primaryKey;column:id;type:varchar(255)" json:"id"`
+	UserID      string    `gorm:"column:user_id;type:varchar(255);not null;uniqueIndex:idx_collections_user_name,priority:1" json:"userId"`
+	Name        string    `gorm:"column:name;type:varchar(100);not null;uniqueIndex:idx_collections_user_name,priority:2" json:"name"`
+	Description string    `gorm:"column:description;type:text" json:"description"`
+	CreatedAt   time.Time `gorm:"column:created_at;type:timestamp with time zone;default:now();not null" json:"createdAt"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;type:timestamp with time zone;default:now();not null" json:"updatedAt"`
+}
+
+// TableName 指定表名（复数 + snake_case）
+func (Collection) TableName() string {
+	return "collections"
+}
+
+// BeforeCreate GORM hook
+func (c *Collection) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = fmt.Sprintf("collection-%d", time.Now().UnixNano())
+	}
+	return nil
+}
+
+// CollectionNote 收藏夹成员关系，Position 为笔记在收藏夹内的顺序（从小到大）
+type CollectionNote struct {
+	CollectionID string    `gorm:"primaryKey;column:collection_id;type:varchar(255);index:idx_collection_notes_position,priority:1" json:"collectionId"`
+	NoteID       string    `gorm:"primaryKey;column:note_id;type:varchar(255);index" json:"noteId"`
+	Position     int       `gorm:"column:position;type:integer;not null;default:0;index:idx_collection_notes_position,priority:2" json:"position"`
+	AddedAt      time.Time `gorm:"column:added_at;type:timestamp with time zone;default:now();not null" json:"addedAt"`
+}
+
+// TableName 指定表名（复数 + snake_case）
+func (CollectionNote) TableName() string {
+	return "collection_notes"
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// IdempotencyKey 插件同步请求的幂等键：记录请求指纹和首次处理的响应，
+// 在有效期内重复提交同一个键时直接返回记录的响应
+// StatusCode 为 0 表示首次请求仍在处理中
+type IdempotencyKey struct {
+	UserID          string    `gorm:"primaryKey;column:user_id;type:varchar(255)" json:"userId"`
+	Key             string    `gorm:"primaryKey;column:key;type:varchar(255)" json:"key"`
+	RequestHash     string    `gorm:"column:request_hash;type:varchar(64);not null" json:"requestHash"`
+	StatusCode      int       `gorm:"column:status_code;type:integer;not null;default:0" json:"statusCode"`
+	ResponseBody    []byte    `gorm:"column:response_body;type:bytea" json:"-"`
+	ResponseHeaders JSONB     `gorm:"column:response_headers;type:jsonb" json:"-"` // 随响应重放的响应头（ETag、X-Quota-* 等）
+	CreatedAt       time.Time `gorm:"column:created_at;type:timestamp with time zone;default:now();not null" json:"createdAt"`
+	ExpiresAt       time.Time `gorm:"column:expires_at;type:timestamp with time zone;not null;index" json:"expiresAt"`
+}
+
+// TableName 指定表名（复数 + snake_case）
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
@@ -16,24 +16,36 @@ import (
 // - 主键 string
 type Note struct {
     ID              string            `gorm:"primaryKey;column:id;type:varchar(255)" json:"id"`
-    UserID          string            `gorm:"column:user_id;type:varchar(255);not null;index" json:"userId"`
+    UserID          string            `gorm:"column:user_id;type:varchar(255);not null;index;index:idx_notes_user_author_xhs_id,priority:1;index:idx_notes_user_xhs_note_id,priority:1" json:"userId"`
     URL             string            `gorm:"column:url;type:varchar(500);not null;index:idx_notes_url" json:"url"`
+    XhsNoteID       string            `gorm:"column:xhs_note_id;type:varchar(32);index:idx_notes_user_xhs_note_id,priority:2" json:"xhsNoteId"` // 从链接中提取的小红书笔记 ID，去重依据
     Title           string            `gorm:"column:title;type:varchar(500)" json:"title"`
     Author          string            `gorm:"column:author;type:varchar(100)" json:"author"`
+    AuthorXhsID     string            `gorm:"column:author_xhs_id;type:varchar(50);index:idx_notes_user_author_xhs_id,priority:2" json:"authorXhsId"` // 作者的小红书用户 ID，对应 Blogger.XhsID
     Content         string            `gorm:"column:content;type:text" json:"content"`
     Tags            pq.StringArray    `gorm:"column:tags;type:text[]" json:"tags"`
     ImageURLs       pq.StringArray    `gorm:"column:image_urls;type:text[]" json:"imageUrls"`
     VideoURL        *string           `gorm:"column:video_url;type:varchar(500)" json:"videoUrl,omitempty"`
     NoteType        string            `gorm:"column:note_type;type:varchar(20)" json:"noteType"`
     CoverImageURL   string            `gorm:"column:cover_image_url;type:varchar(500)" json:"coverImageUrl"`
+    // 归档到自有存储的副本（由后台归档任务写入）：ArchivedImageURLs 与 ImageURLs 一一对应，未归档的位置为空串
+    ArchivedImageURLs     pq.StringArray `gorm:"column:archived_image_urls;type:text[]" json:"archivedImageUrls"`
+    ArchivedCoverImageURL string         `gorm:"column:archived_cover_image_url;type:varchar(500)" json:"archivedCoverImageUrl"`
+    ArchivedVideoURL      string         `gorm:"column:archived_video_url;type:varchar(500)" json:"archivedVideoUrl,omitempty"`
+    // 封面缩略图和感知哈希（取自封面的归档结果），用于识别不同账号转发的同图笔记
+    CoverThumbnailURL string `gorm:"column:cover_thumbnail_url;type:varchar(500)" json:"coverThumbnailUrl,omitempty"`
+    CoverDHash        *int64 `gorm:"column:cover_dhash;type:bigint" json:"-"`
+    CoverAHash        *int64 `gorm:"column:cover_ahash;type:bigint" json:"-"`
     Likes           int32             `gorm:"column:likes;type:integer;default:0" json:"likes"`
     Collects        int32             `gorm:"column:collects;type:integer;default:0" json:"collects"`
     Comments        int32             `gorm:"column:comments;type:integer;default:0" json:"comments"`
     PublishDate     int64             `gorm:"column:publish_date;type:bigint" json:"publishDate"`
     Source          string            `gorm:"column:source;type:varchar(20);default:'single'" json:"source"` // single: 单篇, batch: 批量
     CaptureTimestamp int64            `gorm:"column:capture_timestamp;type:bigint;not null" json:"captureTimestamp"`
+    Version         int64             `gorm:"column:version;type:bigint;not null;default:1" json:"version"` // 每次写入递增，作为 ETag
     CreatedAt       time.Time         `gorm:"column:created_at;type:timestamp with time zone;default:now();not null" json:"createdAt"`
     UpdatedAt       time.Time         `gorm:"column:updated_at;type:timestamp with time zone;default:now();not null" json:"updatedAt"`
+    DeletedAt       gorm.DeletedAt    `gorm:"index" json:"-"` // 软删除：非空表示在回收站中
 }
 
 // TableName 指定表名（复数 + snake_case）
@@ -46,5 +58,8 @@ func (n *Note) BeforeCreate(tx *gorm.DB) error {
 	if n.ID == "" {
 		n.ID = fmt.Sprintf("note-%d", time.Now().UnixNano())
 	}
+	if n.Version == 0 {
+		n.Version = 1
+	}
 	return nil
 }
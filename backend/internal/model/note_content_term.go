@@ -0,0 +1,16 @@
+package model
+
+// NoteContentTerm 笔记内容分析结果：话题、@提及、表情、关键词及出现次数
+// 由 NoteRepository 在笔记写入时维护，词项由 analyzer.Analyze 生成
+type NoteContentTerm struct {
+	NoteID string `gorm:"primaryKey;column:note_id;type:varchar(255)" json:"noteId"`
+	Kind   string `gorm:"primaryKey;column:kind;type:varchar(20);index:idx_note_content_terms_user_kind_term,priority:2" json:"kind"`
+	Term   string `gorm:"primaryKey;column:term;type:varchar(64);index:idx_note_content_terms_user_kind_term,priority:3" json:"term"`
+	UserID string `gorm:"column:user_id;type:varchar(255);not null;index:idx_note_content_terms_user_kind_term,priority:1" json:"userId"`
+	Count  int    `gorm:"column:count;type:integer;not null;default:0" json:"count"`
+}
+
+// TableName 指定表名（复数 + snake_case）
+func (NoteContentTerm) TableName() string {
+	return "note_content_terms"
+}
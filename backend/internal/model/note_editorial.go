@@ -0,0 +1,76 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 笔记编辑流程状态
+const (
+	NoteStatusInbox       = "inbox"       // 待处理（默认）
+	NoteStatusShortlisted = "shortlisted" // 入选
+	NoteStatusRewriting   = "rewriting"   // 改写中
+	NoteStatusUsed        = "used"        // 已使用
+	NoteStatusDiscarded   = "discarded"   // 已弃用
+)
+
+// NoteStatuses 全部编辑流程状态（按流程顺序）
+var NoteStatuses = []string{NoteStatusInbox, NoteStatusShortlisted, NoteStatusRewriting, NoteStatusUsed, NoteStatusDiscarded}
+
+// IsValidNoteStatus 是否为合法的编辑流程状态
+func IsValidNoteStatus(status string) bool {
+	for _, s := range NoteStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// NoteEditorial 笔记的编辑信息（流程状态、星级、私有备注）
+// 与采集数据分表存储，重新采集（Upsert）不会覆盖；没有记录的笔记视为 inbox、未评级
+type NoteEditorial struct {
+	NoteID          string     `gorm:"primaryKey;column:note_id;type:varchar(255)" json:"noteId"`
+	UserID          string     `gorm:"column:user_id;type:varchar(255);not null;index:idx_note_editorials_user_status,priority:1" json:"userId"`
+	Status          string     `gorm:"column:status;type:varchar(20);not null;default:'inbox';index:idx_note_editorials_user_status,priority:2" json:"status"`
+	Rating          int16      `gorm:"column:rating;type:smallint;not null;default:0" json:"rating"` // 0 表示未评级，1-5 星
+	Remarks         string     `gorm:"column:remarks;type:text" json:"remarks"`
+	StatusChangedAt *time.Time `gorm:"column:status_changed_at;type:timestamp with time zone" json:"statusChangedAt"`
+	CreatedAt       time.Time  `gorm:"column:created_at;type:timestamp with time zone;default:now();not null" json:"createdAt"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at;type:timestamp with time zone;default:now();not null" json:"updatedAt"`
+}
+
+// TableName 指定表名（复数 + snake_case）
+func (NoteEditorial) TableName() string {
+	return "note_editorials"
+}
+
+// NewNoteEditorial 没有编辑记录时的默认值
+func NewNoteEditorial(userID, noteID string) *NoteEditorial {
+	return &NoteEditorial{NoteID: noteID, UserID: userID, Status: NoteStatusInbox}
+}
+
+// NoteStatusChange 笔记编辑流程状态变更记录，用于统计各环节的处理量
+type NoteStatusChange struct {
+	ID         string    `gorm:"primaryKey;column:id;type:varchar(255)" json:"id"`
+	NoteID     string    `gorm:"column:note_id;type:varchar(255);not null;index" json:"noteId"`
+	UserID     string    `gorm:"column:user_id;type:varchar(255);not null;index:idx_note_status_changes_user_changed,priority:1" json:"userId"`
+	FromStatus string    `gorm:"column:from_status;type:varchar(20);not null" json:"fromStatus"`
+	ToStatus   string    `gorm:"column:to_status;type:varchar(20);not null" json:"toStatus"`
+	ChangedAt  time.Time `gorm:"column:changed_at;type:timestamp with time zone;default:now();not null;index:idx_note_status_changes_user_changed,priority:2" json:"changedAt"`
+}
+
+// TableName 指定表名（复数 + snake_case）
+func (NoteStatusChange) TableName() string {
+	return "note_status_changes"
+}
+
+// BeforeCreate GORM hook
+func (c *NoteStatusChange) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return nil
+}
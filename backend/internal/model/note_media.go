@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// 笔记媒体类型
+const (
+	NoteMediaImage = "image"
+	NoteMediaCover = "cover"
+	NoteMediaVideo = "video"
+)
+
+// 笔记媒体归档状态
+const (
+	NoteMediaPending  = "pending"  // 等待归档（含等待重试）
+	NoteMediaArchived = "archived" // 已归档
+	NoteMediaFailed   = "failed"   // 放弃归档，见 LastError
+)
+
+// NoteMedia 笔记引用的一个媒体文件及其归档状态（同一笔记内按原始链接去重）
+type NoteMedia struct {
+	NoteID        string     `gorm:"primaryKey;column:note_id;type:varchar(255)" json:"noteId"`
+	SourceURL     string     `gorm:"primaryKey;column:source_url;type:text" json:"sourceUrl"`
+	UserID        string     `gorm:"column:user_id;type:varchar(255);not null" json:"userId"`
+	Kind          string     `gorm:"column:kind;type:varchar(20);not null" json:"kind"`
+	Status        string     `gorm:"column:status;type:varchar(20);not null;default:'pending';index:idx_note_media_due,priority:1" json:"status"`
+	ArchivedURL   string     `gorm:"column:archived_url;type:varchar(500)" json:"archivedUrl"`
+	ContentType   string     `gorm:"column:content_type;type:varchar(100)" json:"contentType"`
+	Size          int64      `gorm:"column:size;type:bigint;not null;default:0" json:"size"`
+	ThumbnailURL  string     `gorm:"column:thumbnail_url;type:varchar(500)" json:"thumbnailUrl,omitempty"`
+	DHash         *int64     `gorm:"column:dhash;type:bigint" json:"-"`
+	AHash         *int64     `gorm:"column:ahash;type:bigint" json:"-"`
+	Attempts      int        `gorm:"column:attempts;type:integer;not null;default:0" json:"attempts"`
+	LastError     string     `gorm:"column:last_error;type:text" json:"lastError,omitempty"`
+	NextAttemptAt time.Time  `gorm:"column:next_attempt_at;type:timestamp with time zone;default:now();not null;index:idx_note_media_due,priority:2" json:"nextAttemptAt"`
+	ArchivedAt    *time.Time `gorm:"column:archived_at;type:timestamp with time zone" json:"archivedAt,omitempty"`
+	CreatedAt     time.Time  `gorm:"column:created_at;type:timestamp with time zone;default:now();not null" json:"createdAt"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at;type:timestamp with time zone;default:now();not null" json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (NoteMedia) TableName() string {
+	return "note_media"
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NoteMetricSnapshot 笔记互动数据快照
+// 每次插件采集（Create/Upsert）都会写入一条，用于还原笔记的互动增长曲线
+type NoteMetricSnapshot struct {
+	ID               string    `gorm:"primaryKey;column:id;type:varchar(255)" json:"id"`
+	NoteID           string    `gorm:"column:note_id;type:varchar(255);not null;index:idx_note_metric_snapshots_note_created,priority:1" json:"noteId"`
+	UserID           string    `gorm:"column:user_id;type:varchar(255);not null;index" json:"userId"`
+	Likes            int32     `gorm:"column:likes;type:integer;default:0" json:"likes"`
+	Collects         int32     `gorm:"column:collects;type:integer;default:0" json:"collects"`
+	Comments         int32     `gorm:"column:comments;type:integer;default:0" json:"comments"`
+	CaptureTimestamp int64     `gorm:"column:capture_timestamp;type:bigint;not null" json:"captureTimestamp"`
+	CreatedAt        time.Time `gorm:"column:created_at;type:timestamp with time zone;default:now();not null;index:idx_note_metric_snapshots_note_created,priority:2" json:"createdAt"`
+}
+
+// TableName 指定表名（复数 + snake_case）
+func (NoteMetricSnapshot) TableName() string {
+	return "note_metric_snapshots"
+}
+
+// BeforeCreate GORM hook - 快照写入频繁，使用 UUID 避免同一纳秒内 ID 冲突
+func (s *NoteMetricSnapshot) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// NewNoteMetricSnapshot 根据笔记当前互动数据生成快照
+func NewNoteMetricSnapshot(note *Note) *NoteMetricSnapshot {
+	return &NoteMetricSnapshot{
+		NoteID:           note.ID,
+		UserID:           note.UserID,
+		Likes:            note.Likes,
+		Collects:         note.Collects,
+		Comments:         note.Comments,
+		CaptureTimestamp: note.CaptureTimestamp,
+	}
+}
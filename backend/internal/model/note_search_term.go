@@ -0,0 +1,16 @@
+package model
+
+// NoteSearchTerm 笔记全文检索倒排索引
+// 由 NoteRepository 在笔记写入时维护，词项由 search.IndexTerms 生成
+type NoteSearchTerm struct {
+	NoteID    string `gorm:"primaryKey;column:note_id;type:varchar(255)" json:"noteId"`
+	Term      string `gorm:"primaryKey;column:term;type:varchar(64);index:idx_note_search_terms_user_term,priority:2" json:"term"`
+	UserID    string `gorm:"column:user_id;type:varchar(255);not null;index:idx_note_search_terms_user_term,priority:1" json:"userId"`
+	TitleTF   int    `gorm:"column:title_tf;type:integer;not null;default:0" json:"titleTf"`
+	ContentTF int    `gorm:"column:content_tf;type:integer;not null;default:0" json:"contentTf"`
+}
+
+// TableName 指定表名（复数 + snake_case）
+func (NoteSearchTerm) TableName() string {
+	return "note_search_terms"
+}
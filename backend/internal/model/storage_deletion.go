@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// StorageDeletion 待删除的存储对象（永久删除笔记后不再被引用的归档媒体和直传文件），
+// 由后台任务按 URL 反推对象 key 删除，失败时按 NextAttemptAt 重试
+type StorageDeletion struct {
+	URL           string    `gorm:"primaryKey;column:url;type:varchar(1000)" json:"url"`
+	Attempts      int       `gorm:"column:attempts;type:integer;not null;default:0" json:"attempts"`
+	LastError     string    `gorm:"column:last_error;type:text" json:"lastError,omitempty"`
+	NextAttemptAt time.Time `gorm:"column:next_attempt_at;type:timestamp with time zone;default:now();not null;index" json:"nextAttemptAt"`
+	CreatedAt     time.Time `gorm:"column:created_at;type:timestamp with time zone;default:now();not null" json:"createdAt"`
+}
+
+// TableName 指定表名
+func (StorageDeletion) TableName() string {
+	return "storage_deletions"
+}
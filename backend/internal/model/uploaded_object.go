@@ -0,0 +1,35 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UploadedObject 用户通过直传凭证上传到对象存储的文件（上传完成回调时记录）
+type UploadedObject struct {
+	ID          string    `gorm:"primaryKey;column:id;type:varchar(255)" json:"id"`
+	UserID      string    `gorm:"column:user_id;type:varchar(255);not null;uniqueIndex:idx_uploaded_objects_user_key,priority:1" json:"userId"`
+	NoteID      *string   `gorm:"column:note_id;type:varchar(255);index" json:"noteId,omitempty"`
+	XhsNoteID   string    `gorm:"column:xhs_note_id;type:varchar(64);index" json:"xhsNoteId,omitempty"`
+	Driver      string    `gorm:"column:driver;type:varchar(20);not null" json:"driver"`
+	Key         string    `gorm:"column:key;type:varchar(500);not null;uniqueIndex:idx_uploaded_objects_user_key,priority:2" json:"key"`
+	URL         string    `gorm:"column:url;type:varchar(1000);not null" json:"url"`
+	Size        int64     `gorm:"column:size;type:bigint;not null;default:0" json:"size"`
+	ContentType string    `gorm:"column:content_type;type:varchar(100)" json:"contentType"`
+	CreatedAt   time.Time `gorm:"column:created_at;type:timestamp with time zone;default:now();not null" json:"createdAt"`
+}
+
+// TableName 指定表名
+func (UploadedObject) TableName() string {
+	return "uploaded_objects"
+}
+
+// BeforeCreate GORM hook
+func (o *UploadedObject) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == "" {
+		o.ID = fmt.Sprintf("upload-%d", time.Now().UnixNano())
+	}
+	return nil
+}
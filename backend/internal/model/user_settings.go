@@ -12,10 +12,30 @@ type UserSettings struct {
 	CollectionEnabled    bool   `gorm:"column:collection_enabled;not null;default:false" json:"collectionEnabled"`
 	CollectionDailyLimit int    `gorm:"column:collection_daily_limit;not null;default:500" json:"collectionDailyLimit"`
 	CollectionBatchLimit int    `gorm:"column:collection_batch_limit;not null;default:50" json:"collectionBatchLimit"`
+	Timezone             string `gorm:"column:timezone;type:varchar(64);not null;default:'Asia/Shanghai'" json:"timezone"` // 每日采集额度按该时区的自然日计算
 	CreatedAt            time.Time `gorm:"column:created_at;not null;default:now()" json:"createdAt"`
 	UpdatedAt            time.Time `gorm:"column:updated_at;not null;default:now()" json:"updatedAt"`
 }
 
+// DefaultTimezone 用户未设置时区时使用的时区
+const DefaultTimezone = "Asia/Shanghai"
+
+// Location 用户时区，未设置或无法识别时使用 DefaultTimezone
+func (us *UserSettings) Location() *time.Location {
+	name := us.Timezone
+	if name == "" {
+		name = DefaultTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		loc, err = time.LoadLocation(DefaultTimezone)
+		if err != nil {
+			return time.FixedZone(DefaultTimezone, 8*3600)
+		}
+	}
+	return loc
+}
+
 // TableName specifies the table name for UserSettings
 func (UserSettings) TableName() string {
 	return "user_settings"
@@ -0,0 +1,121 @@
+// Package phash 图片感知哈希和缩略图
+// 不同账号转发的同一张封面通常经过重新压缩、缩放或轻微裁切，字节不同但哈希的汉明距离很小
+package phash
+
+import (
+	"image"
+	"image/color"
+	"math/bits"
+
+	// 注册标准库支持的解码器（小红书 CDN 的 webp 需请求 jpeg/png 格式）
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// AHash 均值哈希：缩放为 8×8 灰度图，亮度高于均值的像素记为 1
+func AHash(img image.Image) uint64 {
+	g := gray(img, 8, 8)
+	var sum float64
+	for _, v := range g {
+		sum += v
+	}
+	mean := sum / float64(len(g))
+
+	var h uint64
+	for i, v := range g {
+		if v > mean {
+			h |= 1 << uint(i)
+		}
+	}
+	return h
+}
+
+// DHash 差值哈希：缩放为 9×8 灰度图，每行左侧像素比右侧亮记为 1
+func DHash(img image.Image) uint64 {
+	g := gray(img, 9, 8)
+	var h uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			if g[y*9+x] > g[y*9+x+1] {
+				h |= 1 << uint(y*8+x)
+			}
+		}
+	}
+	return h
+}
+
+// Distance 两个哈希的汉明距离（0 表示相同，64 表示完全相反）
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Thumbnail 等比缩放到最长边不超过 maxSide（不放大）
+func Thumbnail(img image.Image, maxSide int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxSide && h <= maxSide {
+		return img
+	}
+	if w >= h {
+		h = max(1, h*maxSide/w)
+		w = maxSide
+	} else {
+		w = max(1, w*maxSide/h)
+		h = maxSide
+	}
+
+	// 区域平均：每个目标像素取其覆盖的源像素均值
+	sums := make([][4]uint64, w*h)
+	counts := make([]uint64, w*h)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		ty := (y - b.Min.Y) * h / b.Dy()
+		for x := b.Min.X; x < b.Max.X; x++ {
+			tx := (x - b.Min.X) * w / b.Dx()
+			r, g, bl, a := img.At(x, y).RGBA()
+			i := ty*w + tx
+			sums[i][0] += uint64(r)
+			sums[i][1] += uint64(g)
+			sums[i][2] += uint64(bl)
+			sums[i][3] += uint64(a)
+			counts[i]++
+		}
+	}
+
+	out := image.NewRGBA64(image.Rect(0, 0, w, h))
+	for i, s := range sums {
+		n := max(counts[i], 1)
+		out.SetRGBA64(i%w, i/w, color.RGBA64{
+			R: uint16(s[0] / n),
+			G: uint16(s[1] / n),
+			B: uint16(s[2] / n),
+			A: uint16(s[3] / n),
+		})
+	}
+	return out
+}
+
+// gray 按区域平均缩放为 w×h 的灰度矩阵（行优先）
+func gray(img image.Image, w, h int) []float64 {
+	b := img.Bounds()
+	out := make([]float64, w*h)
+	if b.Empty() {
+		return out
+	}
+	counts := make([]float64, w*h)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		ty := (y - b.Min.Y) * h / b.Dy()
+		for x := b.Min.X; x < b.Max.X; x++ {
+			tx := (x - b.Min.X) * w / b.Dx()
+			r, g, bl, _ := img.At(x, y).RGBA()
+			out[ty*w+tx] += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(bl)
+			counts[ty*w+tx]++
+		}
+	}
+	for i := range out {
+		if counts[i] > 0 {
+			out[i] /= counts[i]
+		}
+	}
+	return out
+}
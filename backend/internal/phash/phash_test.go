@@ -0,0 +1,117 @@
+package phash
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// gradient 生成水平渐变加一块亮斑的测试图
+func gradient(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := uint8(x * 255 / w)
+			if x > w/2 && y < h/3 {
+				v = 255
+			}
+			img.Set(x, y, color.RGBA{R: v, G: v, B: v, A: 255})
+		}
+	}
+	return img
+}
+
+func invert(img image.Image) image.Image {
+	b := img.Bounds()
+	out := image.NewRGBA(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, bl, a := img.At(x, y).RGBA()
+			out.Set(x, y, color.RGBA64{R: uint16(0xffff - r), G: uint16(0xffff - g), B: uint16(0xffff - bl), A: uint16(a)})
+		}
+	}
+	return out
+}
+
+func TestHashes(t *testing.T) {
+	base := gradient(256, 192)
+	tests := []struct {
+		name    string
+		other   image.Image
+		maxDist int
+		minDist int
+	}{
+		{"identical", gradient(256, 192), 0, 0},
+		{"rescaled", gradient(96, 72), 4, 0},
+		{"inverted", invert(base), 64, 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, h := range []struct {
+				name string
+				fn   func(image.Image) uint64
+			}{{"AHash", AHash}, {"DHash", DHash}} {
+				d := Distance(h.fn(base), h.fn(tt.other))
+				if d > tt.maxDist || d < tt.minDist {
+					t.Errorf("%s distance = %d, want %d..%d", h.name, d, tt.minDist, tt.maxDist)
+				}
+			}
+		})
+	}
+}
+
+func TestHashEmptyImage(t *testing.T) {
+	empty := image.NewRGBA(image.Rect(0, 0, 0, 0))
+	if AHash(empty) != 0 || DHash(empty) != 0 {
+		t.Error("empty image should hash to 0")
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b uint64
+		want int
+	}{
+		{0, 0, 0},
+		{0, 1, 1},
+		{0xff, 0x0f, 4},
+		{0, ^uint64(0), 64},
+	}
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%#x, %#x) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestThumbnail(t *testing.T) {
+	tests := []struct {
+		name         string
+		w, h, side   int
+		wantW, wantH int
+	}{
+		{"landscape", 400, 200, 100, 100, 50},
+		{"portrait", 300, 900, 90, 30, 90},
+		{"square", 50, 50, 10, 10, 10},
+		{"no upscale", 40, 20, 100, 40, 20},
+		{"thin strip keeps one pixel", 1000, 2, 100, 100, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Thumbnail(gradient(tt.w, tt.h), tt.side).Bounds()
+			if b.Dx() != tt.wantW || b.Dy() != tt.wantH {
+				t.Errorf("size = %dx%d, want %dx%d", b.Dx(), b.Dy(), tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+func TestThumbnailAveragesColors(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+	img.Set(1, 0, color.RGBA{B: 255, A: 255})
+	r, g, b, _ := Thumbnail(img, 1).At(0, 0).RGBA()
+	if r>>8 != 127 || g != 0 || b>>8 != 127 {
+		t.Errorf("averaged pixel = (%d, %d, %d)", r>>8, g>>8, b>>8)
+	}
+}
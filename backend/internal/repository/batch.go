@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// 单条记录的写入结果
+const (
+	UpsertCreated = "created"
+	UpsertUpdated = "updated"
+	UpsertSkipped = "skipped" // 已有完整记录，新数据为简化版，保留现有记录
+)
+
+// BatchResult 批量写入中一条记录的结果，Err 非空表示该条写入失败（仅逐条提交时出现）
+type BatchResult struct {
+	ID      string
+	Outcome string
+	Err     error
+}
+
+// BatchItemError 整批事务中某一条写入失败，整批已回滚
+type BatchItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("batch item %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// runBatch 执行 n 条写入：atomic 为 true 时共用一个事务，失败返回 *BatchItemError；
+// 否则每条使用独立事务，失败记录到 results 对应位置
+func runBatch(db *gorm.DB, n int, atomic bool, save func(tx *gorm.DB, i int) error, results []*BatchResult) error {
+	if atomic {
+		return db.Transaction(func(tx *gorm.DB) error {
+			for i := 0; i < n; i++ {
+				if err := save(tx, i); err != nil {
+					return &BatchItemError{Index: i, Err: err}
+				}
+			}
+			return nil
+		})
+	}
+
+	for i := 0; i < n; i++ {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			return save(tx, i)
+		})
+		if err != nil {
+			results[i] = &BatchResult{Err: err}
+		}
+	}
+	return nil
+}
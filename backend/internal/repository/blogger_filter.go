@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/keenchase/edit-business/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// BloggerFilter 博主列表组合筛选条件，零值字段不参与筛选
+// 采集时间为毫秒时间戳，与 captureTimestamp 一致；JSON 字段名与列表查询参数一致
+type BloggerFilter struct {
+	Keyword      string `json:"q"` // 匹配名称、简介或小红书号（不区分大小写）
+	FollowersMin *int32 `json:"followersMin"`
+	FollowersMax *int32 `json:"followersMax"`
+	CaptureFrom  *int64 `json:"captureFrom"`
+	CaptureTo    *int64 `json:"captureTo"`
+	Verified     *bool  `json:"verified"`
+	Category     string `json:"category"`
+	HasNotes     *bool  `json:"hasNotes"` // 是否有已采集的笔记（按笔记的作者小红书 ID 关联）
+}
+
+// IsEmpty 是否没有任何筛选条件
+func (f *BloggerFilter) IsEmpty() bool {
+	return f.Keyword == "" && f.FollowersMin == nil && f.FollowersMax == nil &&
+		f.CaptureFrom == nil && f.CaptureTo == nil && f.Verified == nil && f.Category == "" && f.HasNotes == nil
+}
+
+// bloggerHasNotesSQL 博主是否有已采集的笔记（回收站中的笔记除外）
+const bloggerHasNotesSQL = "EXISTS (SELECT 1 FROM notes n WHERE n.user_id = bloggers.user_id AND n.author_xhs_id = bloggers.xhs_id AND n.deleted_at IS NULL)"
+
+// bloggerSortColumns 允许排序的字段（API 字段名 -> 排序表达式）
+var bloggerSortColumns = map[string]sortColumn{
+	"followersCount":     {expr: "followers_count"},
+	"followingCount":     {expr: "following_count"},
+	"likesCollectsCount": {expr: "likes_collects_count"},
+	"captureTimestamp":   {expr: "capture_timestamp"},
+	"createdAt":          {expr: "created_at", isTime: true},
+	"updatedAt":          {expr: "updated_at", isTime: true},
+}
+
+// ParseBloggerSort 解析博主排序参数，field 为空时默认按粉丝数倒序
+func ParseBloggerSort(field, order string) (Sort, error) {
+	return parseSort(bloggerSortColumns, "followersCount", field, order)
+}
+
+// bloggerSortValue 取博主在排序字段上的值，用于生成游标
+func bloggerSortValue(b *model.Blogger, field string) interface{} {
+	switch field {
+	case "followingCount":
+		return int64(b.FollowingCount)
+	case "likesCollectsCount":
+		return int64(b.LikesCollectsCount)
+	case "captureTimestamp":
+		return b.CaptureTimestamp
+	case "createdAt":
+		return b.CreatedAt
+	case "updatedAt":
+		return b.UpdatedAt
+	default:
+		return int64(b.FollowersCount)
+	}
+}
+
+// applyBloggerFilter 在查询上叠加组合筛选条件
+func applyBloggerFilter(q *gorm.DB, f *BloggerFilter) *gorm.DB {
+	if f == nil {
+		return q
+	}
+	if keyword := strings.TrimSpace(f.Keyword); keyword != "" {
+		pattern := likePattern(keyword)
+		q = q.Where("(blogger_name ILIKE ? OR description ILIKE ? OR xhs_id ILIKE ?)", pattern, pattern, pattern)
+	}
+	if f.FollowersMin != nil {
+		q = q.Where("followers_count >= ?", *f.FollowersMin)
+	}
+	if f.FollowersMax != nil {
+		q = q.Where("followers_count <= ?", *f.FollowersMax)
+	}
+	if f.CaptureFrom != nil {
+		q = q.Where("capture_timestamp >= ?", *f.CaptureFrom)
+	}
+	if f.CaptureTo != nil {
+		q = q.Where("capture_timestamp <= ?", *f.CaptureTo)
+	}
+	if f.Verified != nil {
+		q = q.Where("verified = ?", *f.Verified)
+	}
+	if f.Category != "" {
+		q = q.Where("category = ?", f.Category)
+	}
+	if f.HasNotes != nil {
+		if *f.HasNotes {
+			q = q.Where(bloggerHasNotesSQL)
+		} else {
+			q = q.Where("NOT " + bloggerHasNotesSQL)
+		}
+	}
+	return q
+}
+
+// likePattern 生成包含匹配的 LIKE 模式，转义用户输入中的通配符
+func likePattern(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+	return "%" + s + "%"
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/keenchase/edit-business/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// BloggerMetricRepository 博主粉丝快照仓库
+// 快照由 BloggerRepository 在 Create/Upsert 时写入，这里只负责查询
+type BloggerMetricRepository struct {
+	db *gorm.DB
+}
+
+// NewBloggerMetricRepository 创建博主粉丝快照仓库实例
+func NewBloggerMetricRepository(db *gorm.DB) *BloggerMetricRepository {
+	return &BloggerMetricRepository{db: db}
+}
+
+// ListByBloggerID 获取博主的快照时间序列（按用户隔离，按时间升序）
+// from/to 为零值时不限制
+func (r *BloggerMetricRepository) ListByBloggerID(userID, bloggerID string, from, to time.Time) ([]*model.BloggerMetricSnapshot, error) {
+	var snapshots []*model.BloggerMetricSnapshot
+	q := r.db.Where("user_id = ? AND blogger_id = ?", userID, bloggerID)
+	if !from.IsZero() {
+		q = q.Where("created_at >= ?", from)
+	}
+	if !to.IsZero() {
+		q = q.Where("created_at <= ?", to)
+	}
+	err := q.Order("created_at ASC").Find(&snapshots).Error
+	return snapshots, err
+}
+
+// FollowerDelta 一段时间内的粉丝数据增量
+type FollowerDelta struct {
+	Followers     int32     `json:"followers"`
+	Following     int32     `json:"following"`
+	LikesCollects int32     `json:"likesCollects"`
+	FollowersRate float64   `json:"followersRate"` // 粉丝增长率（相对基准快照，基准为 0 时为 0）
+	Since         time.Time `json:"since"`         // 基准快照的时间，早于窗口起点的数据缺失时晚于窗口起点
+}
+
+// BloggerGrowth 博主粉丝增长（7 天 / 30 天）
+// 窗口起点之前没有快照时，以最早的快照为基准；只有一条快照时为 nil
+type BloggerGrowth struct {
+	Delta7d  *FollowerDelta `json:"delta7d"`
+	Delta30d *FollowerDelta `json:"delta30d"`
+}
+
+// GetGrowth 批量计算博主的粉丝增长（按用户隔离）
+// 以最新快照为当前值，与窗口起点处的快照做差
+func (r *BloggerMetricRepository) GetGrowth(userID string, bloggerIDs []string, now time.Time) (map[string]*BloggerGrowth, error) {
+	result := make(map[string]*BloggerGrowth, len(bloggerIDs))
+	if len(bloggerIDs) == 0 {
+		return result, nil
+	}
+
+	latest, err := r.latestSnapshotsBefore(userID, bloggerIDs, now)
+	if err != nil {
+		return nil, err
+	}
+	earliest, err := r.earliestSnapshots(userID, bloggerIDs)
+	if err != nil {
+		return nil, err
+	}
+	weekBase, err := r.latestSnapshotsBefore(userID, bloggerIDs, now.Add(-7*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	monthBase, err := r.latestSnapshotsBefore(userID, bloggerIDs, now.Add(-30*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range bloggerIDs {
+		current, ok := latest[id]
+		if !ok {
+			continue
+		}
+		result[id] = &BloggerGrowth{
+			Delta7d:  followerDelta(current, pickBloggerBaseline(weekBase[id], earliest[id])),
+			Delta30d: followerDelta(current, pickBloggerBaseline(monthBase[id], earliest[id])),
+		}
+	}
+	return result, nil
+}
+
+// latestSnapshotsBefore 每个博主在 at 之前（含）最新的一条快照
+func (r *BloggerMetricRepository) latestSnapshotsBefore(userID string, bloggerIDs []string, at time.Time) (map[string]*model.BloggerMetricSnapshot, error) {
+	var snapshots []*model.BloggerMetricSnapshot
+	err := r.db.Raw(`SELECT DISTINCT ON (blogger_id) * FROM blogger_metric_snapshots
+		WHERE user_id = ? AND blogger_id IN ? AND created_at <= ?
+		ORDER BY blogger_id, created_at DESC`, userID, bloggerIDs, at).Scan(&snapshots).Error
+	if err != nil {
+		return nil, err
+	}
+	return snapshotsByBlogger(snapshots), nil
+}
+
+// earliestSnapshots 每个博主最早的一条快照
+func (r *BloggerMetricRepository) earliestSnapshots(userID string, bloggerIDs []string) (map[string]*model.BloggerMetricSnapshot, error) {
+	var snapshots []*model.BloggerMetricSnapshot
+	err := r.db.Raw(`SELECT DISTINCT ON (blogger_id) * FROM blogger_metric_snapshots
+		WHERE user_id = ? AND blogger_id IN ?
+		ORDER BY blogger_id, created_at ASC`, userID, bloggerIDs).Scan(&snapshots).Error
+	if err != nil {
+		return nil, err
+	}
+	return snapshotsByBlogger(snapshots), nil
+}
+
+func snapshotsByBlogger(snapshots []*model.BloggerMetricSnapshot) map[string]*model.BloggerMetricSnapshot {
+	byBlogger := make(map[string]*model.BloggerMetricSnapshot, len(snapshots))
+	for _, s := range snapshots {
+		byBlogger[s.BloggerID] = s
+	}
+	return byBlogger
+}
+
+// pickBloggerBaseline 窗口起点前有快照则用之，否则退回最早的快照
+func pickBloggerBaseline(base, earliest *model.BloggerMetricSnapshot) *model.BloggerMetricSnapshot {
+	if base != nil {
+		return base
+	}
+	return earliest
+}
+
+// followerDelta 计算两条快照之间的增量，基准缺失或与当前为同一条时返回 nil
+func followerDelta(current, base *model.BloggerMetricSnapshot) *FollowerDelta {
+	if current == nil || base == nil || current.ID == base.ID {
+		return nil
+	}
+	delta := &FollowerDelta{
+		Followers:     current.FollowersCount - base.FollowersCount,
+		Following:     current.FollowingCount - base.FollowingCount,
+		LikesCollects: current.LikesCollectsCount - base.LikesCollectsCount,
+		Since:         base.CreatedAt,
+	}
+	if base.FollowersCount > 0 {
+		delta.FollowersRate = float64(delta.Followers) / float64(base.FollowersCount)
+	}
+	return delta
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/keenchase/edit-business/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// fillFromProfile 用共享的主页数据补全本次采集缺少的字段（如导入文件只有小红书号和名称）
+func fillFromProfile(tx *gorm.DB, blogger *model.Blogger) error {
+	if blogger.XhsID == "" {
+		return nil
+	}
+	var profile model.BloggerProfile
+	err := tx.Where("xhs_id = ?", blogger.XhsID).First(&profile).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	profile.FillBlogger(blogger)
+	return nil
+}
+
+// saveProfile 把插件采集的主页数据写入共享表，只有比现有数据更新的采集才会覆盖
+// 采集时间由客户端提供，超过当前时间的按当前时间记录，避免一次未来时间的写入让共享数据再也无法更新
+func saveProfile(tx *gorm.DB, blogger *model.Blogger) error {
+	if blogger.XhsID == "" {
+		return nil
+	}
+	profile := model.NewBloggerProfile(blogger)
+	if now := time.Now().UnixMilli(); profile.CaptureTimestamp > now {
+		profile.CaptureTimestamp = now
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "xhs_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"blogger_name", "avatar_url", "description", "followers_count", "following_count",
+			"likes_collects_count", "ip_location", "verified", "verified_label", "blogger_url",
+			"capture_timestamp", "updated_at",
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "blogger_profiles.capture_timestamp <= excluded.capture_timestamp"},
+		}},
+	}).Create(profile).Error
+}
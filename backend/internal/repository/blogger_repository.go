@@ -2,10 +2,12 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/keenchase/edit-business/internal/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // BloggerRepository 博主信息仓库
@@ -18,11 +20,6 @@ func NewBloggerRepository(db *gorm.DB) *BloggerRepository {
 	return &BloggerRepository{db: db}
 }
 
-// Create 创建博主信息
-func (r *BloggerRepository) Create(blogger *model.Blogger) error {
-	return r.db.Create(blogger).Error
-}
-
 // GetByID 根据 ID 获取博主信息（按用户隔离）
 func (r *BloggerRepository) GetByID(userID, id string) (*model.Blogger, error) {
 	var blogger model.Blogger
@@ -43,27 +40,43 @@ func (r *BloggerRepository) GetByUserIDAndXhsID(userID, xhsID string) (*model.Bl
 	return &blogger, nil
 }
 
-// GetByXhsID 根据小红书 ID 获取博主信息（已废弃，请用 GetByUserIDAndXhsID）
-func (r *BloggerRepository) GetByXhsID(xhsID string) (*model.Blogger, error) {
-	var blogger model.Blogger
-	err := r.db.Where("xhs_id = ?", xhsID).First(&blogger).Error
+// MatchExisting 逐条判断 xhsIDs 是否会更新已有博主（不含回收站），批内重复的小红书 ID 也算已有；
+// 用于只对新增的博主计算每日额度
+func (r *BloggerRepository) MatchExisting(userID string, xhsIDs []string) ([]bool, error) {
+	matched := make([]bool, len(xhsIDs))
+	if len(xhsIDs) == 0 {
+		return matched, nil
+	}
+	var existing []string
+	err := r.db.Model(&model.Blogger{}).
+		Where("user_id = ? AND xhs_id IN ?", userID, xhsIDs).
+		Pluck("xhs_id", &existing).Error
 	if err != nil {
 		return nil, err
 	}
-	return &blogger, nil
+
+	seen := make(map[string]bool, len(existing))
+	for _, id := range existing {
+		seen[id] = true
+	}
+	for i, id := range xhsIDs {
+		matched[i] = seen[id]
+		seen[id] = true
+	}
+	return matched, nil
 }
 
-// List 获取博主列表（按用户隔离）
-func (r *BloggerRepository) List(userID string, offset, limit int) ([]*model.Blogger, int64, error) {
+// List 获取博主列表（按用户隔离，支持组合筛选）
+func (r *BloggerRepository) List(userID string, filter *BloggerFilter, sort Sort, offset, limit int) ([]*model.Blogger, int64, error) {
 	var bloggers []*model.Blogger
 	var total int64
 
-	if err := r.db.Model(&model.Blogger{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+	if err := r.filteredQuery(userID, filter).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	err := r.db.Where("user_id = ?", userID).
-		Order("followers_count DESC").
+	err := r.filteredQuery(userID, filter).
+		Order(sort.orderClause()).
 		Offset(offset).
 		Limit(limit).
 		Find(&bloggers).Error
@@ -71,42 +84,264 @@ func (r *BloggerRepository) List(userID string, offset, limit int) ([]*model.Blo
 	return bloggers, total, err
 }
 
+// ListByCursor 游标分页获取博主列表（按用户隔离，支持组合筛选）
+// cursor 为空时从第一页开始；返回的 nextCursor 为空表示没有更多数据
+func (r *BloggerRepository) ListByCursor(userID string, filter *BloggerFilter, sort Sort, cursor string, limit int) ([]*model.Blogger, string, error) {
+	q := r.filteredQuery(userID, filter)
+	if cursor != "" {
+		value, id, err := sort.decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		q = q.Where(sort.keysetCondition(), value, id)
+	}
+
+	// 多取一条判断是否还有下一页
+	var bloggers []*model.Blogger
+	if err := q.Order(sort.orderClause()).Limit(limit + 1).Find(&bloggers).Error; err != nil {
+		return nil, "", err
+	}
+	if len(bloggers) <= limit {
+		return bloggers, "", nil
+	}
+
+	bloggers = bloggers[:limit]
+	last := bloggers[limit-1]
+	return bloggers, sort.encodeCursor(bloggerSortValue(last, sort.Field), last.ID), nil
+}
+
+// Stream 逐行读取博主（按用户隔离，支持组合筛选），不缓存整个结果集，用于导出
+func (r *BloggerRepository) Stream(userID string, filter *BloggerFilter, sort Sort, fn func(*model.Blogger) error) error {
+	rows, err := r.filteredQuery(userID, filter).Order(sort.orderClause()).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var blogger model.Blogger
+		if err := r.db.ScanRows(rows, &blogger); err != nil {
+			return err
+		}
+		if err := fn(&blogger); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 // Update 更新博主信息
 func (r *BloggerRepository) Update(blogger *model.Blogger) error {
+	blogger.Version++
 	return r.db.Save(blogger).Error
 }
 
-// Delete 删除博主信息（按用户隔离）
+// Patch 按版本号条件更新博主的部分字段
+// version 与当前记录不一致时返回 ErrVersionConflict
+func (r *BloggerRepository) Patch(userID, id string, version int64, updates map[string]interface{}) (*model.Blogger, error) {
+	if err := patchWithVersion(r.db, &model.Blogger{}, userID, id, version, updates); err != nil {
+		return nil, err
+	}
+	return r.GetByID(userID, id)
+}
+
+// Delete 删除博主信息到回收站（按用户隔离）
 func (r *BloggerRepository) Delete(userID, id string) error {
 	return r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&model.Blogger{}).Error
 }
 
-// BatchCreate 批量创建博主信息
-func (r *BloggerRepository) BatchCreate(bloggers []*model.Blogger) error {
-	if len(bloggers) == 0 {
-		return nil
+// GetTrashed 获取回收站中的博主（按用户隔离）
+func (r *BloggerRepository) GetTrashed(userID, id string) (*model.Blogger, error) {
+	var blogger model.Blogger
+	err := r.db.Unscoped().
+		Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userID).
+		First(&blogger).Error
+	if err != nil {
+		return nil, err
 	}
-	return r.db.Create(&bloggers).Error
+	return &blogger, nil
 }
 
-// UpsertByXhsID 根据 user_id + xhs_id 插入或更新博主信息
-func (r *BloggerRepository) UpsertByXhsID(blogger *model.Blogger) error {
-	existing, err := r.GetByUserIDAndXhsID(blogger.UserID, blogger.XhsID)
+// GetTrashedByXhsID 获取回收站中相同 xhs_id 的博主（最近删除的一条）
+func (r *BloggerRepository) GetTrashedByXhsID(userID, xhsID string) (*model.Blogger, error) {
+	var blogger model.Blogger
+	err := r.db.Unscoped().
+		Where("user_id = ? AND xhs_id = ? AND deleted_at IS NOT NULL", userID, xhsID).
+		Order("deleted_at DESC").
+		First(&blogger).Error
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return r.db.Create(blogger).Error
+		return nil, err
+	}
+	return &blogger, nil
+}
+
+// Restore 从回收站恢复博主
+// replaceID 非空时先把该博主（通常是重新采集产生的同 xhs_id 记录）移入回收站
+func (r *BloggerRepository) Restore(userID, id, replaceID string) (*model.Blogger, error) {
+	var blogger model.Blogger
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if replaceID != "" {
+			if err := tx.Where("id = ? AND user_id = ?", replaceID, userID).Delete(&model.Blogger{}).Error; err != nil {
+				return err
+			}
+		}
+
+		res := tx.Unscoped().Model(&model.Blogger{}).
+			Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userID).
+			Updates(map[string]interface{}{"deleted_at": nil, "version": gorm.Expr("version + 1")})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
 		}
+		return tx.Where("id = ?", id).First(&blogger).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &blogger, nil
+}
+
+// UpsertByXhsID 根据 user_id + xhs_id 插入或更新插件采集的博主信息（同时写入粉丝数据快照和共享的主页数据，关联同名作者的笔记）
+// 同一用户重复采集同一博主时更新原记录
+func (r *BloggerRepository) UpsertByXhsID(blogger *model.Blogger) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		_, err := upsertBlogger(tx, blogger, true)
 		return err
+	})
+}
+
+// UpsertImported 同 UpsertByXhsID，用于文件导入：数据来自用户上传的文件，只用共享的主页数据补全，不写回共享表
+func (r *BloggerRepository) UpsertImported(blogger *model.Blogger) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		_, err := upsertBlogger(tx, blogger, false)
+		return err
+	})
+}
+
+// UpsertBatch 批量按 xhs_id 插入或更新博主信息，结果与 bloggers 一一对应
+// atomic 语义同 NoteRepository.UpsertBatch
+func (r *BloggerRepository) UpsertBatch(bloggers []*model.Blogger, atomic bool) ([]*BatchResult, error) {
+	results := make([]*BatchResult, len(bloggers))
+	save := func(tx *gorm.DB, i int) error {
+		outcome, err := upsertBlogger(tx, bloggers[i], true)
+		if err != nil {
+			return err
+		}
+		results[i] = &BatchResult{ID: bloggers[i].ID, Outcome: outcome}
+		return nil
+	}
+
+	if err := runBatch(r.db, len(bloggers), atomic, save, results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// upsertBlogger UpsertByXhsID 的写入逻辑，在调用方的事务中执行，返回写入结果
+// 博主记录保存最新一次采集的数据，历史数据保存在快照中；shareProfile 为 true（插件采集）时更新共享的主页数据
+func upsertBlogger(tx *gorm.DB, blogger *model.Blogger, shareProfile bool) (string, error) {
+	if err := fillFromProfile(tx, blogger); err != nil {
+		return "", err
+	}
+	outcome, err := saveBlogger(tx, blogger)
+	if err != nil {
+		return "", err
+	}
+	if err := tx.Create(model.NewBloggerMetricSnapshot(blogger)).Error; err != nil {
+		return "", err
+	}
+	if shareProfile {
+		if err := saveProfile(tx, blogger); err != nil {
+			return "", err
+		}
+	}
+	return outcome, linkAuthorNotes(tx, blogger)
+}
+
+// saveBlogger 按 user_id + xhs_id 插入或覆盖博主记录
+func saveBlogger(tx *gorm.DB, blogger *model.Blogger) (string, error) {
+	existing, err := lockBlogger(tx, blogger.UserID, blogger.XhsID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// 并发采集同一博主时以唯一索引兜底：插入冲突说明另一请求已创建，改为更新该记录
+		res := tx.Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "user_id"}, {Name: "xhs_id"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+			DoNothing:   true,
+		}).Create(blogger)
+		if res.Error != nil {
+			return "", res.Error
+		}
+		if res.RowsAffected > 0 {
+			return UpsertCreated, nil
+		}
+		existing, err = lockBlogger(tx, blogger.UserID, blogger.XhsID)
+	}
+	if err != nil {
+		return "", err
 	}
 
 	blogger.ID = existing.ID
-	return r.db.Save(blogger).Error
+	blogger.CreatedAt = existing.CreatedAt
+	blogger.Version = existing.Version + 1
+	// 分类可由用户编辑，采集未带分类时保留原值
+	if blogger.Category == "" {
+		blogger.Category = existing.Category
+	}
+	if err := tx.Save(blogger).Error; err != nil {
+		return "", err
+	}
+	return UpsertUpdated, nil
+}
+
+// lockBlogger 加行锁读取用户未删除的同 xhs_id 博主，避免与编辑接口交错写入
+func lockBlogger(tx *gorm.DB, userID, xhsID string) (*model.Blogger, error) {
+	var blogger model.Blogger
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND xhs_id = ?", userID, xhsID).First(&blogger).Error
+	if err != nil {
+		return nil, err
+	}
+	return &blogger, nil
+}
+
+// linkAuthorNotes 把作者名称与博主名称相同、尚未关联的笔记关联到该博主
+// 用户有多个同名博主时无法判断归属，不做关联
+func linkAuthorNotes(tx *gorm.DB, blogger *model.Blogger) error {
+	if blogger.BloggerName == "" || blogger.XhsID == "" {
+		return nil
+	}
+	var others int64
+	err := tx.Model(&model.Blogger{}).
+		Where("user_id = ? AND blogger_name = ? AND xhs_id <> ?", blogger.UserID, blogger.BloggerName, blogger.XhsID).
+		Count(&others).Error
+	if err != nil || others > 0 {
+		return err
+	}
+	return tx.Model(&model.Note{}).
+		Where("user_id = ? AND author = ? AND (author_xhs_id IS NULL OR author_xhs_id = '')", blogger.UserID, blogger.BloggerName).
+		Updates(map[string]interface{}{"author_xhs_id": blogger.XhsID, "version": gorm.Expr("version + 1")}).Error
+}
+
+// Count 获取博主总数（按用户隔离，filter 为 nil 时不筛选）
+func (r *BloggerRepository) Count(userID string, filter *BloggerFilter) (int64, error) {
+	var count int64
+	err := r.filteredQuery(userID, filter).Count(&count).Error
+	return count, err
+}
+
+// filteredQuery 按用户隔离并叠加组合筛选条件的基础查询
+func (r *BloggerRepository) filteredQuery(userID string, filter *BloggerFilter) *gorm.DB {
+	return applyBloggerFilter(r.db.Model(&model.Blogger{}).Where("user_id = ?", userID), filter)
 }
 
-// Count 获取博主总数（按用户隔离）
-func (r *BloggerRepository) Count(userID string) (int64, error) {
+// CountCreatedBetween 统计用户在 [from, to) 内新增的博主数量（含回收站，删除不返还采集额度）
+func (r *BloggerRepository) CountCreatedBetween(userID string, from, to time.Time) (int64, error) {
 	var count int64
-	err := r.db.Model(&model.Blogger{}).Where("user_id = ?", userID).Count(&count).Error
+	err := r.db.Unscoped().Model(&model.Blogger{}).
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, from, to).
+		Count(&count).Error
 	return count, err
 }
 
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/keenchase/edit-business/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNoteNotInCollection 排序时指定的笔记不在收藏夹中
+var ErrNoteNotInCollection = errors.New("note is not in the collection")
+
+// CollectionRepository 收藏夹数据仓库
+type CollectionRepository struct {
+	db *gorm.DB
+}
+
+// NewCollectionRepository 创建收藏夹仓库实例
+func NewCollectionRepository(db *gorm.DB) *CollectionRepository {
+	return &CollectionRepository{db: db}
+}
+
+// CollectionWithCount 收藏夹 + 笔记数（不含回收站中的笔记）
+type CollectionWithCount struct {
+	model.Collection
+	NoteCount int64 `json:"noteCount"`
+}
+
+// Create 创建收藏夹
+func (r *CollectionRepository) Create(collection *model.Collection) error {
+	return r.db.Create(collection).Error
+}
+
+// GetByUserIDAndID 根据 ID 获取收藏夹（按用户隔离）
+func (r *CollectionRepository) GetByUserIDAndID(userID, id string) (*model.Collection, error) {
+	var collection model.Collection
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&collection).Error
+	if err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+// GetByUserIDAndName 根据名称获取收藏夹（按用户隔离）
+func (r *CollectionRepository) GetByUserIDAndName(userID, name string) (*model.Collection, error) {
+	var collection model.Collection
+	err := r.db.Where("user_id = ? AND name = ?", userID, name).First(&collection).Error
+	if err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+// ListByUserID 获取用户的全部收藏夹及笔记数（按创建时间排序）
+func (r *CollectionRepository) ListByUserID(userID string) ([]*CollectionWithCount, error) {
+	collections := []*CollectionWithCount{}
+	err := r.db.Raw(`SELECT c.*, COUNT(n.id) AS note_count
+		FROM collections c
+		LEFT JOIN collection_notes cn ON cn.collection_id = c.id
+		LEFT JOIN notes n ON n.id = cn.note_id AND n.deleted_at IS NULL
+		WHERE c.user_id = ?
+		GROUP BY c.id
+		ORDER BY c.created_at, c.id`, userID).Scan(&collections).Error
+	return collections, err
+}
+
+// CountNotes 统计收藏夹中的笔记数（不含回收站中的笔记）
+func (r *CollectionRepository) CountNotes(collectionID string) (int64, error) {
+	var total int64
+	err := r.notesQuery(collectionID).Count(&total).Error
+	return total, err
+}
+
+// Patch 更新收藏夹的部分字段（按用户隔离）
+func (r *CollectionRepository) Patch(userID, id string, updates map[string]interface{}) (*model.Collection, error) {
+	var collection model.Collection
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.Collection{}).Where("id = ? AND user_id = ?", id, userID).Updates(updates)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("id = ?", id).First(&collection).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+// Delete 删除收藏夹（按用户隔离），只解除成员关系，不删除笔记
+func (r *CollectionRepository) Delete(userID, id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&model.Collection{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("collection_id = ?", id).Delete(&model.CollectionNote{}).Error
+	})
+}
+
+// ListNotes 按收藏夹内顺序分页获取笔记（按用户隔离，不含回收站中的笔记）
+func (r *CollectionRepository) ListNotes(userID, collectionID string, offset, limit int) ([]*model.Note, int64, error) {
+	var total int64
+	if err := r.notesQuery(collectionID).Where("notes.user_id = ?", userID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var notes []*model.Note
+	err := r.notesQuery(collectionID).
+		Where("notes.user_id = ?", userID).
+		Order("collection_notes.position, collection_notes.added_at, notes.id").
+		Offset(offset).
+		Limit(limit).
+		Find(&notes).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return notes, total, nil
+}
+
+func (r *CollectionRepository) notesQuery(collectionID string) *gorm.DB {
+	return r.db.Model(&model.Note{}).
+		Joins("JOIN collection_notes ON collection_notes.note_id = notes.id").
+		Where("collection_notes.collection_id = ?", collectionID)
+}
+
+// AddNotes 把笔记追加到收藏夹末尾，已在收藏夹中的笔记保持原位置，返回新增数量
+// 调用方负责校验收藏夹和笔记的归属
+func (r *CollectionRepository) AddNotes(collectionID string, noteIDs []string) (int64, error) {
+	var added int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		added, err = addCollectionNotes(tx, collectionID, noteIDs)
+		return err
+	})
+	return added, err
+}
+
+// RemoveNotes 把笔记移出收藏夹，返回移出数量
+func (r *CollectionRepository) RemoveNotes(collectionID string, noteIDs []string) (int64, error) {
+	res := r.db.Where("collection_id = ? AND note_id IN ?", collectionID, noteIDs).Delete(&model.CollectionNote{})
+	return res.RowsAffected, res.Error
+}
+
+// Reorder 调整收藏夹内笔记顺序：noteIDs 按给定顺序排在最前，其余笔记保持原有相对顺序排在其后
+// noteIDs 中有不在收藏夹中的笔记时返回 ErrNoteNotInCollection
+func (r *CollectionRepository) Reorder(collectionID string, noteIDs []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockCollection(tx, collectionID); err != nil {
+			return err
+		}
+
+		var members []*model.CollectionNote
+		err := tx.Where("collection_id = ?", collectionID).
+			Order("position, added_at, note_id").
+			Find(&members).Error
+		if err != nil {
+			return err
+		}
+
+		isMember := make(map[string]bool, len(members))
+		for _, m := range members {
+			isMember[m.NoteID] = true
+		}
+		listed := make(map[string]bool, len(noteIDs))
+		order := make([]string, 0, len(members))
+		for _, id := range noteIDs {
+			if !isMember[id] {
+				return ErrNoteNotInCollection
+			}
+			if !listed[id] {
+				listed[id] = true
+				order = append(order, id)
+			}
+		}
+		for _, m := range members {
+			if !listed[m.NoteID] {
+				order = append(order, m.NoteID)
+			}
+		}
+
+		for i, id := range order {
+			err := tx.Model(&model.CollectionNote{}).
+				Where("collection_id = ? AND note_id = ?", collectionID, id).
+				Update("position", i).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// lockCollection 锁定收藏夹行，串行化同一收藏夹的追加和排序
+func lockCollection(tx *gorm.DB, collectionID string) error {
+	var collection model.Collection
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", collectionID).First(&collection).Error
+}
+
+// addCollectionNotes 在调用方的事务中把笔记追加到收藏夹末尾，返回新增数量
+func addCollectionNotes(tx *gorm.DB, collectionID string, noteIDs []string) (int64, error) {
+	if len(noteIDs) == 0 {
+		return 0, nil
+	}
+	if err := lockCollection(tx, collectionID); err != nil {
+		return 0, err
+	}
+
+	var next int
+	err := tx.Model(&model.CollectionNote{}).
+		Where("collection_id = ?", collectionID).
+		Select("COALESCE(MAX(position) + 1, 0)").
+		Scan(&next).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var added int64
+	for _, id := range noteIDs {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.CollectionNote{
+			CollectionID: collectionID,
+			NoteID:       id,
+			Position:     next,
+		})
+		if res.Error != nil {
+			return 0, res.Error
+		}
+		if res.RowsAffected > 0 {
+			added++
+			next++
+		}
+	}
+	return added, nil
+}
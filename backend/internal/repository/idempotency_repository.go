@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/keenchase/edit-business/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyRepository 幂等键记录
+type IdempotencyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyRepository 创建幂等键仓库实例
+func NewIdempotencyRepository(db *gorm.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Reserve 占用幂等键：键不存在（或已过期）时写入 record 并返回 nil；
+// 键已被占用时返回现有记录
+func (r *IdempotencyRepository) Reserve(record *model.IdempotencyKey, now time.Time) (*model.IdempotencyKey, error) {
+	var existing *model.IdempotencyKey
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND key = ? AND expires_at <= ?", record.UserID, record.Key, now).
+			Delete(&model.IdempotencyKey{}).Error; err != nil {
+			return err
+		}
+
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected > 0 {
+			return nil
+		}
+
+		var found model.IdempotencyKey
+		if err := tx.Where("user_id = ? AND key = ?", record.UserID, record.Key).First(&found).Error; err != nil {
+			return err
+		}
+		existing = &found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// Complete 记录首次请求的响应
+func (r *IdempotencyRepository) Complete(userID, key string, statusCode int, body []byte, headers model.JSONB) error {
+	return r.db.Model(&model.IdempotencyKey{}).
+		Where("user_id = ? AND key = ?", userID, key).
+		Updates(map[string]interface{}{"status_code": statusCode, "response_body": body, "response_headers": headers}).Error
+}
+
+// Release 释放仍在处理中的幂等键（首次请求失败时），允许客户端用同一个键重试
+func (r *IdempotencyRepository) Release(userID, key string) error {
+	return r.db.Where("user_id = ? AND key = ? AND status_code = 0", userID, key).
+		Delete(&model.IdempotencyKey{}).Error
+}
+
+// DeleteExpired 删除已过期的幂等键，返回删除数量
+func (r *IdempotencyRepository) DeleteExpired(now time.Time) (int64, error) {
+	res := r.db.Where("expires_at <= ?", now).Delete(&model.IdempotencyKey{})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/keenchase/edit-business/internal/model"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrBulkTooLarge 批量操作命中的笔记超过上限
+var ErrBulkTooLarge = errors.New("too many notes for one bulk operation")
+
+// 批量操作类型
+const (
+	BulkDelete     = "delete"
+	BulkAddTags    = "addTags"
+	BulkRemoveTags = "removeTags"
+	BulkSetField   = "setField"
+
+	BulkMoveToCollection = "moveToCollection"
+)
+
+// 批量操作单条结果状态
+const (
+	BulkStatusDeleted   = "deleted"
+	BulkStatusUpdated   = "updated"
+	BulkStatusUnchanged = "unchanged"
+	BulkStatusNotFound  = "notFound" // 不存在或不属于当前用户
+)
+
+// BulkAction 批量操作：Kind 为操作类型，Tags 用于增删标签，Updates 为 setField 的列 -> 新值，
+// CollectionID 为 moveToCollection 的目标收藏夹（调用方负责校验归属）
+type BulkAction struct {
+	Kind         string
+	Tags         []string
+	Updates      map[string]interface{}
+	CollectionID string
+}
+
+// BulkSelection 批量操作的目标：IDs 与 Filter 二选一，Limit 为最多处理的笔记数
+type BulkSelection struct {
+	IDs    []string
+	Filter *NoteFilter
+	Limit  int
+}
+
+// BulkItemResult 批量操作单条结果
+type BulkItemResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// Bulk 在同一事务中对一组笔记执行操作（按用户隔离，目标行加锁）
+// 按 ID 操作时，不存在或不属于当前用户的 ID 返回 notFound，不影响其他笔记；任何写入失败整体回滚
+func (r *NoteRepository) Bulk(userID string, sel BulkSelection, action BulkAction) ([]*BulkItemResult, error) {
+	var results []*BulkItemResult
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		notes, err := lockBulkTargets(tx, userID, sel)
+		if err != nil {
+			return err
+		}
+
+		byID := make(map[string]*model.Note, len(notes))
+		for _, note := range notes {
+			byID[note.ID] = note
+		}
+		order := sel.IDs
+		if sel.Filter != nil {
+			order = make([]string, len(notes))
+			for i, note := range notes {
+				order[i] = note.ID
+			}
+		}
+
+		results = make([]*BulkItemResult, 0, len(order))
+		for _, id := range order {
+			note, ok := byID[id]
+			if !ok {
+				results = append(results, &BulkItemResult{ID: id, Status: BulkStatusNotFound})
+				continue
+			}
+			status, err := applyBulkAction(tx, note, action)
+			if err != nil {
+				return err
+			}
+			results = append(results, &BulkItemResult{ID: id, Status: status})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// lockBulkTargets 查询并锁定目标笔记；按筛选条件命中超过上限时返回 ErrBulkTooLarge
+func lockBulkTargets(tx *gorm.DB, userID string, sel BulkSelection) ([]*model.Note, error) {
+	q := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID)
+	if sel.Filter != nil {
+		q = applyNoteFilter(q, sel.Filter).Order("capture_timestamp DESC, id DESC").Limit(sel.Limit + 1)
+	} else {
+		q = q.Where("id IN ?", sel.IDs)
+	}
+
+	var notes []*model.Note
+	if err := q.Find(&notes).Error; err != nil {
+		return nil, err
+	}
+	if len(notes) > sel.Limit {
+		return nil, ErrBulkTooLarge
+	}
+	return notes, nil
+}
+
+// applyBulkAction 对单篇笔记执行操作，返回结果状态
+func applyBulkAction(tx *gorm.DB, note *model.Note, action BulkAction) (string, error) {
+	switch action.Kind {
+	case BulkDelete:
+		if err := tx.Delete(note).Error; err != nil {
+			return "", err
+		}
+		if err := deleteNoteTerms(tx, "note_id = ?", note.ID); err != nil {
+			return "", err
+		}
+		return BulkStatusDeleted, nil
+
+	case BulkAddTags, BulkRemoveTags:
+		tags, changed := mergeTags(note.Tags, action.Tags, action.Kind == BulkAddTags)
+		if !changed {
+			return BulkStatusUnchanged, nil
+		}
+		return BulkStatusUpdated, bumpNote(tx, note, map[string]interface{}{"tags": tags})
+
+	case BulkSetField:
+		if err := bumpNote(tx, note, action.Updates); err != nil {
+			return "", err
+		}
+		if err := tx.Where("id = ?", note.ID).First(note).Error; err != nil {
+			return "", err
+		}
+		if err := indexNote(tx, note); err != nil {
+			return "", err
+		}
+		return BulkStatusUpdated, syncNoteMedia(tx, note)
+
+	case BulkMoveToCollection:
+		// 移出其他收藏夹，追加到目标收藏夹末尾（已在目标收藏夹中的保持原位置）
+		res := tx.Where("note_id = ? AND collection_id <> ?", note.ID, action.CollectionID).Delete(&model.CollectionNote{})
+		if res.Error != nil {
+			return "", res.Error
+		}
+		added, err := addCollectionNotes(tx, action.CollectionID, []string{note.ID})
+		if err != nil {
+			return "", err
+		}
+		if res.RowsAffected == 0 && added == 0 {
+			return BulkStatusUnchanged, nil
+		}
+		return BulkStatusUpdated, nil
+	}
+	return "", errors.New("unknown bulk action")
+}
+
+// bumpNote 更新指定列并递增版本号（调用方已持有行锁）
+func bumpNote(tx *gorm.DB, note *model.Note, updates map[string]interface{}) error {
+	values := make(map[string]interface{}, len(updates)+1)
+	for column, v := range updates {
+		values[column] = v
+	}
+	values["version"] = gorm.Expr("version + 1")
+	return tx.Model(&model.Note{}).Where("id = ?", note.ID).Updates(values).Error
+}
+
+// mergeTags 增加或移除标签，保持原有顺序，返回新标签和是否有变化
+func mergeTags(current pq.StringArray, tags []string, add bool) (pq.StringArray, bool) {
+	has := make(map[string]bool, len(current))
+	for _, t := range current {
+		has[t] = true
+	}
+	result := pq.StringArray{}
+	changed := false
+	if add {
+		result = append(result, current...)
+		for _, t := range tags {
+			if !has[t] {
+				has[t] = true
+				result = append(result, t)
+				changed = true
+			}
+		}
+		return result, changed
+	}
+
+	remove := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		remove[t] = true
+	}
+	for _, t := range current {
+		if remove[t] {
+			changed = true
+			continue
+		}
+		result = append(result, t)
+	}
+	return result, changed
+}
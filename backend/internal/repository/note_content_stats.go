@@ -0,0 +1,106 @@
+package repository
+
+import "fmt"
+
+// 内容词项统计的排序方式
+const (
+	ContentRankFrequency  = "frequency"  // 按包含该词项的笔记数
+	ContentRankEngagement = "engagement" // 按包含该词项的笔记互动总量（点赞 + 收藏 + 评论）
+)
+
+// statsDateColumns 统计时间范围可选的字段（毫秒时间戳），笔记表别名为 n
+var statsDateColumns = map[string]string{
+	"captureTimestamp": "n.capture_timestamp",
+	"publishDate":      "n.publish_date",
+}
+
+// dateRangeSQL 生成时间范围条件（以 " AND " 开头，未指定范围时为空）
+func dateRangeSQL(dateField string, from, to *int64) (string, []interface{}, error) {
+	column, ok := statsDateColumns[dateField]
+	if !ok {
+		return "", nil, fmt.Errorf("invalid date field %q", dateField)
+	}
+	sql := ""
+	args := []interface{}{}
+	if from != nil {
+		sql += " AND " + column + " >= ?"
+		args = append(args, *from)
+	}
+	if to != nil {
+		sql += " AND " + column + " <= ?"
+		args = append(args, *to)
+	}
+	return sql, args, nil
+}
+
+// ContentTermQuery 内容词项统计条件
+type ContentTermQuery struct {
+	Kind      string
+	DateField string // captureTimestamp（默认）/ publishDate
+	From      *int64 // 毫秒时间戳，含
+	To        *int64 // 毫秒时间戳，含
+	MinNotes  int
+	Rank      string
+	Limit     int
+}
+
+// ContentTermStat 单个词项的统计结果
+type ContentTermStat struct {
+	Term          string  `json:"term"`
+	Notes         int64   `json:"notes"`       // 包含该词项的笔记数
+	Occurrences   int64   `json:"occurrences"` // 出现总次数
+	Likes         int64   `json:"likes"`
+	Collects      int64   `json:"collects"`
+	Comments      int64   `json:"comments"`
+	Engagement    int64   `json:"engagement"`    // 点赞 + 收藏 + 评论
+	AvgEngagement float64 `json:"avgEngagement"` // 每篇笔记的平均互动
+	Share         float64 `json:"share"`         // 占时间范围内笔记数的比例
+}
+
+// ContentTermStats 按词项聚合用户笔记的内容分析结果（回收站中的笔记除外），返回统计结果和时间范围内的笔记总数
+func (r *NoteRepository) ContentTermStats(userID string, q *ContentTermQuery) ([]*ContentTermStat, int64, error) {
+	rangeSQL, rangeArgs, err := dateRangeSQL(q.DateField, q.From, q.To)
+	if err != nil {
+		return nil, 0, err
+	}
+	orderBy := "notes DESC, occurrences DESC, term"
+	if q.Rank == ContentRankEngagement {
+		orderBy = "engagement DESC, notes DESC, term"
+	}
+
+	var total int64
+	err = r.db.Raw("SELECT COUNT(*) FROM notes n WHERE n.user_id = ? AND n.deleted_at IS NULL"+rangeSQL,
+		append([]interface{}{userID}, rangeArgs...)...).Scan(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	args := append([]interface{}{userID, q.Kind}, rangeArgs...)
+	args = append(args, q.MinNotes, q.Limit)
+	var stats []*ContentTermStat
+	err = r.db.Raw(`SELECT t.term,
+			COUNT(*) AS notes,
+			SUM(t.count) AS occurrences,
+			SUM(n.likes) AS likes,
+			SUM(n.collects) AS collects,
+			SUM(n.comments) AS comments,
+			SUM(n.likes + n.collects + n.comments) AS engagement,
+			AVG(n.likes + n.collects + n.comments) AS avg_engagement
+		FROM note_content_terms t
+		JOIN notes n ON n.id = t.note_id AND n.deleted_at IS NULL
+		WHERE t.user_id = ? AND t.kind = ?`+rangeSQL+`
+		GROUP BY t.term
+		HAVING COUNT(*) >= ?
+		ORDER BY `+orderBy+`
+		LIMIT ?`, args...).Scan(&stats).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, s := range stats {
+		if total > 0 {
+			s.Share = float64(s.Notes) / float64(total)
+		}
+	}
+	return stats, total, nil
+}
@@ -0,0 +1,30 @@
+package repository
+
+import "github.com/keenchase/edit-business/internal/model"
+
+// CoverHash 笔记封面的感知哈希（按位存为 bigint）
+type CoverHash struct {
+	ID         string `gorm:"column:id"`
+	CoverDHash int64  `gorm:"column:cover_dhash"`
+	CoverAHash int64  `gorm:"column:cover_ahash"`
+}
+
+// ListCoverHashes 获取用户所有已计算封面哈希的笔记（回收站中的笔记除外）
+func (r *NoteRepository) ListCoverHashes(userID string) ([]*CoverHash, error) {
+	var hashes []*CoverHash
+	err := r.db.Model(&model.Note{}).
+		Select("id", "cover_dhash", "cover_ahash").
+		Where("user_id = ? AND cover_dhash IS NOT NULL AND cover_ahash IS NOT NULL", userID).
+		Find(&hashes).Error
+	return hashes, err
+}
+
+// GetByIDs 按 ID 批量获取用户的笔记（顺序不保证）
+func (r *NoteRepository) GetByIDs(userID string, ids []string) ([]*model.Note, error) {
+	var notes []*model.Note
+	if len(ids) == 0 {
+		return notes, nil
+	}
+	err := r.db.Where("user_id = ? AND id IN ?", userID, ids).Find(&notes).Error
+	return notes, err
+}
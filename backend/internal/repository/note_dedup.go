@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"github.com/keenchase/edit-business/internal/model"
+	"github.com/keenchase/edit-business/internal/xhsurl"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DuplicateGroup 同一篇笔记的重复记录：保留 KeepID，合并并删除 MergedIDs
+type DuplicateGroup struct {
+	XhsNoteID string
+	KeepID    string
+	MergedIDs []string
+}
+
+// BackfillXhsNoteIDs 为尚未提取笔记 ID 的历史笔记（含回收站）回填笔记 ID，返回回填数量
+func (r *NoteRepository) BackfillXhsNoteIDs(userID string) (int, error) {
+	var notes []*model.Note
+	err := r.db.Unscoped().Select("id", "url").
+		Where("user_id = ? AND (xhs_note_id IS NULL OR xhs_note_id = '')", userID).
+		Find(&notes).Error
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, note := range notes {
+		id := xhsurl.NoteID(note.URL)
+		if id == "" {
+			continue
+		}
+		err := r.db.Unscoped().Model(&model.Note{}).Where("id = ?", note.ID).
+			UpdateColumn("xhs_note_id", id).Error
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// MergeDuplicates 合并同一用户下笔记 ID 相同的笔记（不含回收站），每组保留最完整的一条
+// 被合并记录的互动快照、收藏夹成员关系、状态变更和媒体归档结果转移到保留的笔记，编辑信息保留最近更新的一份；
+// dryRun 为 true 时只返回分组结果，不做修改
+func (r *NoteRepository) MergeDuplicates(userID string, dryRun bool) ([]*DuplicateGroup, error) {
+	var noteIDs []string
+	err := r.db.Model(&model.Note{}).
+		Where("user_id = ? AND xhs_note_id <> ''", userID).
+		Group("xhs_note_id").
+		Having("COUNT(*) > 1").
+		Pluck("xhs_note_id", &noteIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]*DuplicateGroup, 0, len(noteIDs))
+	for _, xhsNoteID := range noteIDs {
+		var group *DuplicateGroup
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			var notes []*model.Note
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("user_id = ? AND xhs_note_id = ?", userID, xhsNoteID).
+				Order("created_at, id").
+				Find(&notes).Error
+			if err != nil || len(notes) < 2 {
+				return err
+			}
+
+			keep := mostCompleteNote(notes)
+			group = &DuplicateGroup{XhsNoteID: xhsNoteID, KeepID: keep.ID}
+			for _, n := range notes {
+				if n.ID != keep.ID {
+					group.MergedIDs = append(group.MergedIDs, n.ID)
+				}
+			}
+			if dryRun {
+				return nil
+			}
+			return mergeNotes(tx, keep, notes, group.MergedIDs)
+		})
+		if err != nil {
+			return groups, err
+		}
+		if group != nil {
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+// mostCompleteNote 选出最完整的记录：有正文优先，其次非空字段多，再次采集时间新，最后创建早
+func mostCompleteNote(notes []*model.Note) *model.Note {
+	best := notes[0]
+	for _, n := range notes[1:] {
+		if noteCompleteness(n) > noteCompleteness(best) ||
+			(noteCompleteness(n) == noteCompleteness(best) && n.CaptureTimestamp > best.CaptureTimestamp) {
+			best = n
+		}
+	}
+	return best
+}
+
+func noteCompleteness(n *model.Note) int {
+	score := 0
+	if n.Content != "" {
+		score += 100
+	}
+	for _, filled := range []bool{
+		n.Title != "", n.Author != "", n.AuthorXhsID != "", len(n.Tags) > 0, len(n.ImageURLs) > 0,
+		n.VideoURL != nil && *n.VideoURL != "", n.NoteType != "", n.CoverImageURL != "", n.PublishDate != 0,
+	} {
+		if filled {
+			score++
+		}
+	}
+	return score
+}
+
+// mergeNotes 把 mergedIDs 的关联数据转移到 keep 并删除这些记录
+func mergeNotes(tx *gorm.DB, keep *model.Note, notes []*model.Note, mergedIDs []string) error {
+	// 互动数据、链接和作者 ID 取最近一次采集的值
+	for _, n := range notes {
+		if n.CaptureTimestamp > keep.CaptureTimestamp {
+			keep.URL = n.URL
+			keep.Likes = n.Likes
+			keep.Collects = n.Collects
+			keep.Comments = n.Comments
+			keep.CaptureTimestamp = n.CaptureTimestamp
+		}
+		if keep.AuthorXhsID == "" && n.AuthorXhsID != "" {
+			keep.AuthorXhsID = n.AuthorXhsID
+		}
+	}
+
+	if err := tx.Model(&model.NoteMetricSnapshot{}).Where("note_id IN ?", mergedIDs).
+		Update("note_id", keep.ID).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&model.NoteStatusChange{}).Where("note_id IN ?", mergedIDs).
+		Update("note_id", keep.ID).Error; err != nil {
+		return err
+	}
+
+	// 收藏夹：保留的笔记不在该收藏夹时沿用被合并记录的位置
+	if err := tx.Exec(`INSERT INTO collection_notes (collection_id, note_id, position, added_at)
+		SELECT DISTINCT ON (collection_id) collection_id, ?, position, added_at
+		FROM collection_notes WHERE note_id IN ?
+		ORDER BY collection_id, added_at
+		ON CONFLICT DO NOTHING`, keep.ID, mergedIDs).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("note_id IN ?", mergedIDs).Delete(&model.CollectionNote{}).Error; err != nil {
+		return err
+	}
+
+	// 编辑信息：保留最近更新的一份
+	var latest model.NoteEditorial
+	err := tx.Where("note_id IN ?", append([]string{keep.ID}, mergedIDs...)).
+		Order("updated_at DESC").
+		First(&latest).Error
+	if err == nil && latest.NoteID != keep.ID {
+		if err := tx.Where("note_id = ?", keep.ID).Delete(&model.NoteEditorial{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.NoteEditorial{}).Where("note_id = ?", latest.NoteID).
+			Update("note_id", keep.ID).Error; err != nil {
+			return err
+		}
+	} else if err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	if err := tx.Where("note_id IN ?", mergedIDs).Delete(&model.NoteEditorial{}).Error; err != nil {
+		return err
+	}
+
+	if err := deleteNoteTerms(tx, "note_id IN ?", mergedIDs); err != nil {
+		return err
+	}
+	// 媒体归档：保留的笔记没有的链接沿用被合并记录的归档结果
+	if err := tx.Exec(`INSERT INTO note_media (note_id, source_url, user_id, kind, status, archived_url, content_type,
+			size, thumbnail_url, dhash, ahash, attempts, last_error, next_attempt_at, archived_at, created_at, updated_at)
+		SELECT DISTINCT ON (source_url) ?, source_url, user_id, kind, status, archived_url, content_type,
+			size, thumbnail_url, dhash, ahash, attempts, last_error, next_attempt_at, archived_at, created_at, updated_at
+		FROM note_media WHERE note_id IN ?
+		ORDER BY source_url, status = 'archived' DESC
+		ON CONFLICT DO NOTHING`, keep.ID, mergedIDs).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("note_id IN ?", mergedIDs).Delete(&model.NoteMedia{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("id IN ?", mergedIDs).Delete(&model.Note{}).Error; err != nil {
+		return err
+	}
+
+	keep.Version++
+	if err := tx.Save(keep).Error; err != nil {
+		return err
+	}
+	if err := indexNote(tx, keep); err != nil {
+		return err
+	}
+	return syncNoteMedia(tx, keep)
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/keenchase/edit-business/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NoteEditorialRepository 笔记编辑信息仓库
+type NoteEditorialRepository struct {
+	db *gorm.DB
+}
+
+// NewNoteEditorialRepository 创建笔记编辑信息仓库实例
+func NewNoteEditorialRepository(db *gorm.DB) *NoteEditorialRepository {
+	return &NoteEditorialRepository{db: db}
+}
+
+// Get 获取笔记的编辑信息（按用户隔离），没有记录时返回默认值
+func (r *NoteEditorialRepository) Get(userID, noteID string) (*model.NoteEditorial, error) {
+	var editorial model.NoteEditorial
+	err := r.db.Where("note_id = ? AND user_id = ?", noteID, userID).First(&editorial).Error
+	if err == gorm.ErrRecordNotFound {
+		return model.NewNoteEditorial(userID, noteID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &editorial, nil
+}
+
+// GetByNoteIDs 批量获取编辑信息（按用户隔离），没有记录的笔记返回默认值
+func (r *NoteEditorialRepository) GetByNoteIDs(userID string, noteIDs []string) (map[string]*model.NoteEditorial, error) {
+	result := make(map[string]*model.NoteEditorial, len(noteIDs))
+	if len(noteIDs) == 0 {
+		return result, nil
+	}
+
+	var editorials []*model.NoteEditorial
+	if err := r.db.Where("user_id = ? AND note_id IN ?", userID, noteIDs).Find(&editorials).Error; err != nil {
+		return nil, err
+	}
+	for _, e := range editorials {
+		result[e.NoteID] = e
+	}
+	for _, id := range noteIDs {
+		if result[id] == nil {
+			result[id] = model.NewNoteEditorial(userID, id)
+		}
+	}
+	return result, nil
+}
+
+// Update 更新笔记的编辑信息（调用方负责校验笔记归属）
+// 状态发生变化时记录一条状态变更并更新 status_changed_at
+func (r *NoteEditorialRepository) Update(userID, noteID string, updates map[string]interface{}, now time.Time) (*model.NoteEditorial, error) {
+	var editorial model.NoteEditorial
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// 首次编辑时创建默认记录，再加行锁读取，避免并发修改状态时漏记变更
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(model.NewNoteEditorial(userID, noteID)).Error; err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("note_id = ?", noteID).First(&editorial).Error; err != nil {
+			return err
+		}
+
+		values := make(map[string]interface{}, len(updates)+1)
+		for column, v := range updates {
+			values[column] = v
+		}
+		if status, ok := updates["status"].(string); ok && status != editorial.Status {
+			change := &model.NoteStatusChange{
+				NoteID:     noteID,
+				UserID:     userID,
+				FromStatus: editorial.Status,
+				ToStatus:   status,
+				ChangedAt:  now,
+			}
+			if err := tx.Create(change).Error; err != nil {
+				return err
+			}
+			values["status_changed_at"] = now
+		}
+		if len(values) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&model.NoteEditorial{}).Where("note_id = ?", noteID).Updates(values).Error; err != nil {
+			return err
+		}
+		return tx.Where("note_id = ?", noteID).First(&editorial).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &editorial, nil
+}
+
+// ListStatusChanges 获取笔记的状态变更记录（按时间升序）
+func (r *NoteEditorialRepository) ListStatusChanges(userID, noteID string) ([]*model.NoteStatusChange, error) {
+	changes := []*model.NoteStatusChange{}
+	err := r.db.Where("user_id = ? AND note_id = ?", userID, noteID).
+		Order("changed_at, id").
+		Find(&changes).Error
+	return changes, err
+}
+
+// StatusThroughput 某天进入某状态的笔记数
+type StatusThroughput struct {
+	Date   string `json:"date"` // YYYY-MM-DD
+	Status string `json:"status"`
+	Count  int64  `json:"count"`
+}
+
+// Throughput 按天统计进入各状态的次数（按用户隔离），from/to 为零值时不限制
+func (r *NoteEditorialRepository) Throughput(userID string, from, to time.Time) ([]*StatusThroughput, error) {
+	q := r.db.Model(&model.NoteStatusChange{}).Where("user_id = ?", userID)
+	if !from.IsZero() {
+		q = q.Where("changed_at >= ?", from)
+	}
+	if !to.IsZero() {
+		q = q.Where("changed_at <= ?", to)
+	}
+
+	rows := []*StatusThroughput{}
+	err := q.Select("TO_CHAR(DATE(changed_at), 'YYYY-MM-DD') AS date, to_status AS status, COUNT(*) AS count").
+		Group("DATE(changed_at), to_status").
+		Order("DATE(changed_at), to_status").
+		Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"github.com/keenchase/edit-business/internal/model"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// 标签匹配方式
+const (
+	TagModeAny = "any" // 命中任一标签
+	TagModeAll = "all" // 包含全部标签
+)
+
+// NoteFilter 笔记列表组合筛选条件，零值字段不参与筛选
+// 时间范围均为毫秒时间戳，与 publishDate / captureTimestamp 一致；JSON 字段名与列表查询参数一致
+type NoteFilter struct {
+	Source       string   `json:"source"`
+	Author       string   `json:"author"`
+	AuthorXhsID  string   `json:"authorXhsId"`
+	Tags         []string `json:"tags"`
+	TagMode      string   `json:"tagMode"` // any（默认）或 all
+	NoteType     string   `json:"noteType"`
+	LikesMin     *int32   `json:"likesMin"`
+	LikesMax     *int32   `json:"likesMax"`
+	CollectsMin  *int32   `json:"collectsMin"`
+	CollectsMax  *int32   `json:"collectsMax"`
+	PublishFrom  *int64   `json:"publishFrom"`
+	PublishTo    *int64   `json:"publishTo"`
+	CaptureFrom  *int64   `json:"captureFrom"`
+	CaptureTo    *int64   `json:"captureTo"`
+	HasVideo     *bool    `json:"hasVideo"`
+	HasContent   *bool    `json:"hasContent"`
+	CollectionID string   `json:"collectionId"` // 只返回该收藏夹中的笔记
+
+	// 编辑信息筛选：没有编辑记录的笔记视为 inbox、0 星、无备注
+	Statuses   []string `json:"status"` // 命中任一状态
+	RatingMin  *int32   `json:"ratingMin"`
+	RatingMax  *int32   `json:"ratingMax"`
+	HasRemarks *bool    `json:"hasRemarks"`
+}
+
+// IsEmpty 是否没有任何筛选条件
+func (f *NoteFilter) IsEmpty() bool {
+	return f.Source == "" && f.Author == "" && f.AuthorXhsID == "" && len(f.Tags) == 0 && f.NoteType == "" &&
+		f.LikesMin == nil && f.LikesMax == nil && f.CollectsMin == nil && f.CollectsMax == nil &&
+		f.PublishFrom == nil && f.PublishTo == nil && f.CaptureFrom == nil && f.CaptureTo == nil &&
+		f.HasVideo == nil && f.HasContent == nil && f.CollectionID == "" &&
+		len(f.Statuses) == 0 && f.RatingMin == nil && f.RatingMax == nil && f.HasRemarks == nil
+}
+
+// 编辑信息取值表达式（笔记没有编辑记录时取默认值）
+const (
+	editorialStatusExpr = "COALESCE((SELECT e.status FROM note_editorials e WHERE e.note_id = notes.id), 'inbox')"
+	editorialRatingExpr = "COALESCE((SELECT e.rating FROM note_editorials e WHERE e.note_id = notes.id), 0)"
+	editorialRemarksSQL = "EXISTS (SELECT 1 FROM note_editorials e WHERE e.note_id = notes.id AND TRIM(COALESCE(e.remarks, '')) <> '')"
+)
+
+// noteSortColumns 允许排序的字段（API 字段名 -> 排序表达式）
+// publish_date 可能为空，按 0 处理以保证游标比较有效
+var noteSortColumns = map[string]sortColumn{
+	"likes":            {expr: "likes"},
+	"collects":         {expr: "collects"},
+	"comments":         {expr: "comments"},
+	"publishDate":      {expr: "COALESCE(publish_date, 0)"},
+	"captureTimestamp": {expr: "capture_timestamp"},
+	"createdAt":        {expr: "created_at", isTime: true},
+	"updatedAt":        {expr: "updated_at", isTime: true},
+}
+
+// ParseNoteSort 解析笔记排序参数，field 为空时默认按采集时间倒序，order 为 asc / desc（默认 desc）
+func ParseNoteSort(field, order string) (Sort, error) {
+	return parseSort(noteSortColumns, "captureTimestamp", field, order)
+}
+
+// noteSortValue 取笔记在排序字段上的值，用于生成游标
+func noteSortValue(n *model.Note, field string) interface{} {
+	switch field {
+	case "likes":
+		return int64(n.Likes)
+	case "collects":
+		return int64(n.Collects)
+	case "comments":
+		return int64(n.Comments)
+	case "publishDate":
+		return n.PublishDate
+	case "createdAt":
+		return n.CreatedAt
+	case "updatedAt":
+		return n.UpdatedAt
+	default:
+		return n.CaptureTimestamp
+	}
+}
+
+// applyNoteFilter 在查询上叠加组合筛选条件
+func applyNoteFilter(q *gorm.DB, f *NoteFilter) *gorm.DB {
+	if f == nil {
+		return q
+	}
+	if f.Source != "" {
+		q = q.Where("source = ?", f.Source)
+	}
+	if f.Author != "" {
+		q = q.Where("author = ?", f.Author)
+	}
+	if f.AuthorXhsID != "" {
+		q = q.Where("author_xhs_id = ?", f.AuthorXhsID)
+	}
+	if len(f.Tags) > 0 {
+		if f.TagMode == TagModeAll {
+			q = q.Where("tags @> ?", pq.Array(f.Tags))
+		} else {
+			q = q.Where("tags && ?", pq.Array(f.Tags))
+		}
+	}
+	if f.NoteType != "" {
+		q = q.Where("note_type = ?", f.NoteType)
+	}
+	if f.LikesMin != nil {
+		q = q.Where("likes >= ?", *f.LikesMin)
+	}
+	if f.LikesMax != nil {
+		q = q.Where("likes <= ?", *f.LikesMax)
+	}
+	if f.CollectsMin != nil {
+		q = q.Where("collects >= ?", *f.CollectsMin)
+	}
+	if f.CollectsMax != nil {
+		q = q.Where("collects <= ?", *f.CollectsMax)
+	}
+	if f.PublishFrom != nil {
+		q = q.Where("publish_date >= ?", *f.PublishFrom)
+	}
+	if f.PublishTo != nil {
+		q = q.Where("publish_date <= ?", *f.PublishTo)
+	}
+	if f.CaptureFrom != nil {
+		q = q.Where("capture_timestamp >= ?", *f.CaptureFrom)
+	}
+	if f.CaptureTo != nil {
+		q = q.Where("capture_timestamp <= ?", *f.CaptureTo)
+	}
+	if f.HasVideo != nil {
+		if *f.HasVideo {
+			q = q.Where("video_url IS NOT NULL AND video_url <> ''")
+		} else {
+			q = q.Where("(video_url IS NULL OR video_url = '')")
+		}
+	}
+	if f.HasContent != nil {
+		if *f.HasContent {
+			q = q.Where("content IS NOT NULL AND TRIM(content) <> ''")
+		} else {
+			q = q.Where("(content IS NULL OR TRIM(content) = '')")
+		}
+	}
+	if len(f.Statuses) > 0 {
+		q = q.Where(editorialStatusExpr+" IN ?", f.Statuses)
+	}
+	if f.RatingMin != nil {
+		q = q.Where(editorialRatingExpr+" >= ?", *f.RatingMin)
+	}
+	if f.RatingMax != nil {
+		q = q.Where(editorialRatingExpr+" <= ?", *f.RatingMax)
+	}
+	if f.HasRemarks != nil {
+		if *f.HasRemarks {
+			q = q.Where(editorialRemarksSQL)
+		} else {
+			q = q.Where("NOT " + editorialRemarksSQL)
+		}
+	}
+	if f.CollectionID != "" {
+		q = q.Where("id IN (SELECT note_id FROM collection_notes WHERE collection_id = ?)", f.CollectionID)
+	}
+	return q
+}
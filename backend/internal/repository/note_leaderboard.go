@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 排行榜维度
+const (
+	DimensionAuthor   = "author"
+	DimensionTag      = "tag"
+	DimensionNoteType = "noteType"
+)
+
+// 排行榜排序方式
+const (
+	LeaderboardSortNotes         = "notes"         // 笔记数
+	LeaderboardSortLikes         = "likes"         // 点赞总数
+	LeaderboardSortEngagement    = "engagement"    // 互动总量（点赞 + 收藏 + 评论）
+	LeaderboardSortAvgEngagement = "avgEngagement" // 每篇笔记的平均互动
+)
+
+// leaderboardOrders 排序方式对应的 ORDER BY，并列时按 key 保证分页稳定
+var leaderboardOrders = map[string]string{
+	LeaderboardSortNotes:         "notes DESC, engagement DESC, key",
+	LeaderboardSortLikes:         "likes DESC, notes DESC, key",
+	LeaderboardSortEngagement:    "engagement DESC, notes DESC, key",
+	LeaderboardSortAvgEngagement: "avg_engagement DESC, notes DESC, key",
+}
+
+// tagExpr 标签归一化：去掉首尾的 # 和空白并转小写
+const tagExpr = "lower(btrim(tag.name, '# '))"
+
+// LeaderboardQuery 排行榜查询条件
+type LeaderboardQuery struct {
+	DateField string // captureTimestamp / publishDate
+	From      *int64 // 毫秒时间戳，含
+	To        *int64 // 毫秒时间戳，含
+	Sort      string
+	MinNotes  int
+	Tag       string // 仅标签组合：只返回包含该标签的组合（已归一化）
+	Offset    int
+	Limit     int
+}
+
+// LeaderboardEntry 排行榜单项
+type LeaderboardEntry struct {
+	Key           string  `json:"key"`  // 作者为 author_xhs_id（缺失时为昵称），标签为归一化后的标签，类型为 note_type
+	Name          string  `json:"name"` // 作者取最近采集笔记上的昵称，其余同 key
+	Notes         int64   `json:"notes"`
+	Likes         int64   `json:"likes"`
+	Collects      int64   `json:"collects"`
+	Comments      int64   `json:"comments"`
+	Engagement    int64   `json:"engagement"`
+	AvgEngagement float64 `json:"avgEngagement"`
+	Share         float64 `json:"share"` // 占时间范围内笔记数的比例
+}
+
+// TagPairEntry 标签共现组合
+type TagPairEntry struct {
+	TagA          string  `json:"tagA"`
+	TagB          string  `json:"tagB"`
+	Notes         int64   `json:"notes"` // 同时带有两个标签的笔记数
+	Likes         int64   `json:"likes"`
+	Engagement    int64   `json:"engagement"`
+	AvgEngagement float64 `json:"avgEngagement"`
+	Jaccard       float64 `json:"jaccard"` // 共现笔记数 / 带有任一标签的笔记数
+}
+
+// leaderboardSource 各维度的明细子查询，每行一篇笔记（标签维度为一篇笔记的一个标签），
+// 返回的 SQL 带一个 user_id 占位符，rangeSQL 追加在 WHERE 末尾
+func leaderboardSource(dimension, rangeSQL string) (string, error) {
+	const metrics = "n.id, n.likes, n.collects, n.comments, n.created_at"
+	switch dimension {
+	case DimensionAuthor:
+		return `SELECT ` + metrics + `,
+				COALESCE(NULLIF(n.author_xhs_id, ''), n.author) AS key,
+				n.author AS name
+			FROM notes n
+			WHERE n.user_id = ? AND n.deleted_at IS NULL
+				AND COALESCE(NULLIF(n.author_xhs_id, ''), n.author) <> ''` + rangeSQL, nil
+	case DimensionNoteType:
+		return `SELECT ` + metrics + `,
+				COALESCE(NULLIF(n.note_type, ''), 'unknown') AS key,
+				COALESCE(NULLIF(n.note_type, ''), 'unknown') AS name
+			FROM notes n
+			WHERE n.user_id = ? AND n.deleted_at IS NULL` + rangeSQL, nil
+	case DimensionTag:
+		return `SELECT DISTINCT ` + metrics + `,
+				` + tagExpr + ` AS key,
+				` + tagExpr + ` AS name
+			FROM notes n
+			CROSS JOIN LATERAL unnest(n.tags) AS tag(name)
+			WHERE n.user_id = ? AND n.deleted_at IS NULL
+				AND ` + tagExpr + ` <> ''` + rangeSQL, nil
+	}
+	return "", fmt.Errorf("invalid leaderboard dimension %q", dimension)
+}
+
+// Leaderboard 按维度聚合用户笔记（回收站中的笔记除外），返回当前页、满足条件的分组总数和时间范围内的笔记总数
+func (r *NoteRepository) Leaderboard(userID, dimension string, q *LeaderboardQuery) ([]*LeaderboardEntry, int64, int64, error) {
+	rangeSQL, rangeArgs, err := dateRangeSQL(q.DateField, q.From, q.To)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	source, err := leaderboardSource(dimension, rangeSQL)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	orderBy, ok := leaderboardOrders[q.Sort]
+	if !ok {
+		return nil, 0, 0, fmt.Errorf("invalid leaderboard sort %q", q.Sort)
+	}
+	sourceArgs := append([]interface{}{userID}, rangeArgs...)
+
+	var totalNotes int64
+	err = r.db.Raw("SELECT COUNT(*) FROM notes n WHERE n.user_id = ? AND n.deleted_at IS NULL"+rangeSQL,
+		sourceArgs...).Scan(&totalNotes).Error
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	var total int64
+	err = r.db.Raw(`SELECT COUNT(*) FROM (
+			SELECT key FROM (`+source+`) src GROUP BY key HAVING COUNT(*) >= ?
+		) g`, append(sourceArgs, q.MinNotes)...).Scan(&total).Error
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if total == 0 {
+		return []*LeaderboardEntry{}, 0, totalNotes, nil
+	}
+
+	var entries []*LeaderboardEntry
+	err = r.db.Raw(`SELECT key,
+			(array_agg(name ORDER BY created_at DESC))[1] AS name,
+			COUNT(*) AS notes,
+			SUM(likes) AS likes,
+			SUM(collects) AS collects,
+			SUM(comments) AS comments,
+			SUM(likes + collects + comments) AS engagement,
+			AVG(likes + collects + comments) AS avg_engagement
+		FROM (`+source+`) src
+		GROUP BY key
+		HAVING COUNT(*) >= ?
+		ORDER BY `+orderBy+`
+		LIMIT ? OFFSET ?`, append(sourceArgs, q.MinNotes, q.Limit, q.Offset)...).Scan(&entries).Error
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	for _, e := range entries {
+		if totalNotes > 0 {
+			e.Share = float64(e.Notes) / float64(totalNotes)
+		}
+	}
+	return entries, total, totalNotes, nil
+}
+
+// TagPairs 统计同一篇笔记上同时出现的标签组合（回收站中的笔记除外），返回当前页和满足条件的组合总数
+func (r *NoteRepository) TagPairs(userID string, q *LeaderboardQuery) ([]*TagPairEntry, int64, error) {
+	rangeSQL, rangeArgs, err := dateRangeSQL(q.DateField, q.From, q.To)
+	if err != nil {
+		return nil, 0, err
+	}
+	source, err := leaderboardSource(DimensionTag, rangeSQL)
+	if err != nil {
+		return nil, 0, err
+	}
+	orderBy, ok := leaderboardOrders[q.Sort]
+	if !ok {
+		return nil, 0, fmt.Errorf("invalid leaderboard sort %q", q.Sort)
+	}
+	// 组合以 tag_a < tag_b 去重，按 key 排序时改为按组合排序
+	orderBy = strings.ReplaceAll(orderBy, "key", "tag_a, tag_b")
+
+	args := append([]interface{}{userID}, rangeArgs...)
+	tagFilter := ""
+	if q.Tag != "" {
+		tagFilter = " AND (a.key = ? OR b.key = ?)"
+		args = append(args, q.Tag, q.Tag)
+	}
+	args = append(args, q.MinNotes)
+	pairs := `WITH src AS (` + source + `),
+		pairs AS (
+			SELECT a.key AS tag_a, b.key AS tag_b,
+				COUNT(*) AS notes,
+				SUM(a.likes) AS likes,
+				SUM(a.likes + a.collects + a.comments) AS engagement,
+				AVG(a.likes + a.collects + a.comments) AS avg_engagement
+			FROM src a
+			JOIN src b ON b.id = a.id AND a.key < b.key
+			WHERE TRUE` + tagFilter + `
+			GROUP BY a.key, b.key
+			HAVING COUNT(*) >= ?
+		)`
+
+	var total int64
+	if err := r.db.Raw(pairs+` SELECT COUNT(*) FROM pairs`, args...).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []*TagPairEntry{}, 0, nil
+	}
+
+	var entries []*TagPairEntry
+	err = r.db.Raw(pairs+`,
+		tag_notes AS (
+			SELECT key, COUNT(*) AS notes FROM src GROUP BY key
+		)
+		SELECT * FROM (
+			SELECT p.*,
+				p.notes::float8 / (ta.notes + tb.notes - p.notes) AS jaccard
+			FROM pairs p
+			JOIN tag_notes ta ON ta.key = p.tag_a
+			JOIN tag_notes tb ON tb.key = p.tag_b
+		) x
+		ORDER BY `+orderBy+`
+		LIMIT ? OFFSET ?`, append(args, q.Limit, q.Offset)...).Scan(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/keenchase/edit-business/internal/model"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NoteMediaRepository 笔记媒体归档队列
+type NoteMediaRepository struct {
+	db *gorm.DB
+}
+
+// NewNoteMediaRepository 创建笔记媒体仓库实例
+func NewNoteMediaRepository(db *gorm.DB) *NoteMediaRepository {
+	return &NoteMediaRepository{db: db}
+}
+
+// ClaimDue 领取到期的待归档媒体（回收站中的笔记除外），领取后 lease 时间内不会被再次领取，
+// 多个实例同时运行时互不重复
+func (r *NoteMediaRepository) ClaimDue(now time.Time, limit int, lease time.Duration) ([]*model.NoteMedia, error) {
+	var media []*model.NoteMedia
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", model.NoteMediaPending, now).
+			Where("note_id IN (SELECT id FROM notes WHERE deleted_at IS NULL)").
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&media).Error
+		if err != nil || len(media) == 0 {
+			return err
+		}
+		for _, m := range media {
+			err := tx.Model(&model.NoteMedia{}).
+				Where("note_id = ? AND source_url = ?", m.NoteID, m.SourceURL).
+				Updates(map[string]interface{}{"next_attempt_at": now.Add(lease), "updated_at": now}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return media, err
+}
+
+// ArchivedMedia 归档结果；图片无法生成缩略图时 ThumbnailURL 为空、哈希为 nil
+type ArchivedMedia struct {
+	URL          string
+	ContentType  string
+	Size         int64
+	ThumbnailURL string
+	DHash        *int64
+	AHash        *int64
+}
+
+// MarkArchived 记录归档结果，并更新笔记上的归档链接和封面哈希
+func (r *NoteMediaRepository) MarkArchived(m *model.NoteMedia, archived *ArchivedMedia, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.NoteMedia{}).
+			Where("note_id = ? AND source_url = ?", m.NoteID, m.SourceURL).
+			Updates(map[string]interface{}{
+				"status":        model.NoteMediaArchived,
+				"archived_url":  archived.URL,
+				"content_type":  archived.ContentType,
+				"size":          archived.Size,
+				"thumbnail_url": archived.ThumbnailURL,
+				"dhash":         archived.DHash,
+				"ahash":         archived.AHash,
+				"attempts":      gorm.Expr("attempts + 1"),
+				"last_error":    "",
+				"archived_at":   now,
+				"updated_at":    now,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		var note model.Note
+		err = tx.Unscoped().Select("id", "image_urls", "cover_image_url", "video_url").
+			Where("id = ?", m.NoteID).First(&note).Error
+		if err != nil {
+			return err
+		}
+		return applyArchivedURLs(tx, &note)
+	})
+}
+
+// MarkFailed 记录归档失败：retryAt 非空时等待重试，否则不再尝试
+func (r *NoteMediaRepository) MarkFailed(m *model.NoteMedia, reason string, retryAt *time.Time, now time.Time) error {
+	updates := map[string]interface{}{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": reason,
+		"updated_at": now,
+	}
+	if retryAt != nil {
+		updates["next_attempt_at"] = *retryAt
+	} else {
+		updates["status"] = model.NoteMediaFailed
+	}
+	return r.db.Model(&model.NoteMedia{}).
+		Where("note_id = ? AND source_url = ?", m.NoteID, m.SourceURL).
+		Updates(updates).Error
+}
+
+// syncNoteMedia 笔记写入后调用：把新出现的媒体链接加入归档队列，并按已归档的结果填充笔记的归档链接
+func syncNoteMedia(tx *gorm.DB, note *model.Note) error {
+	var media []*model.NoteMedia
+	add := func(sourceURL, kind string) {
+		if isArchivableURL(sourceURL) {
+			media = append(media, &model.NoteMedia{NoteID: note.ID, SourceURL: sourceURL, UserID: note.UserID, Kind: kind, Status: model.NoteMediaPending})
+		}
+	}
+	add(note.CoverImageURL, model.NoteMediaCover)
+	for _, u := range note.ImageURLs {
+		add(u, model.NoteMediaImage)
+	}
+	if note.VideoURL != nil {
+		add(*note.VideoURL, model.NoteMediaVideo)
+	}
+
+	if len(media) > 0 {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&media).Error; err != nil {
+			return err
+		}
+	}
+	return applyArchivedURLs(tx, note)
+}
+
+// applyArchivedURLs 按笔记当前的媒体链接填充归档链接和封面哈希（不递增版本号：归档是后台行为，不应使编辑中的 ETag 失效）
+func applyArchivedURLs(tx *gorm.DB, note *model.Note) error {
+	var media []*model.NoteMedia
+	err := tx.Select("source_url", "archived_url", "thumbnail_url", "dhash", "ahash").
+		Where("note_id = ? AND status = ?", note.ID, model.NoteMediaArchived).
+		Find(&media).Error
+	if err != nil {
+		return err
+	}
+	archived := make(map[string]string, len(media))
+	var cover *model.NoteMedia
+	for _, m := range media {
+		archived[m.SourceURL] = m.ArchivedURL
+		if m.SourceURL == note.CoverImageURL {
+			cover = m
+		}
+	}
+
+	note.ArchivedImageURLs = make(pq.StringArray, len(note.ImageURLs))
+	for i, u := range note.ImageURLs {
+		note.ArchivedImageURLs[i] = archived[u]
+	}
+	note.ArchivedCoverImageURL = archived[note.CoverImageURL]
+	note.CoverThumbnailURL, note.CoverDHash, note.CoverAHash = "", nil, nil
+	if cover != nil {
+		note.CoverThumbnailURL, note.CoverDHash, note.CoverAHash = cover.ThumbnailURL, cover.DHash, cover.AHash
+	}
+	note.ArchivedVideoURL = ""
+	if note.VideoURL != nil {
+		note.ArchivedVideoURL = archived[*note.VideoURL]
+	}
+
+	return tx.Unscoped().Model(&model.Note{}).Where("id = ?", note.ID).UpdateColumns(map[string]interface{}{
+		"archived_image_urls":      note.ArchivedImageURLs,
+		"archived_cover_image_url": note.ArchivedCoverImageURL,
+		"archived_video_url":       note.ArchivedVideoURL,
+		"cover_thumbnail_url":      note.CoverThumbnailURL,
+		"cover_dhash":              note.CoverDHash,
+		"cover_ahash":              note.CoverAHash,
+	}).Error
+}
+
+// isArchivableURL 只归档 http(s) 链接（插件可能传入 blob: 等无法在服务端下载的地址）
+func isArchivableURL(u string) bool {
+	return strings.HasPrefix(u, "https://") || strings.HasPrefix(u, "http://")
+}
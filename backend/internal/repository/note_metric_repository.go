@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/keenchase/edit-business/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// NoteMetricRepository 笔记互动快照仓库
+// 快照由 NoteRepository 在 Create/Upsert 时写入，这里只负责查询
+type NoteMetricRepository struct {
+	db *gorm.DB
+}
+
+// NewNoteMetricRepository 创建笔记互动快照仓库实例
+func NewNoteMetricRepository(db *gorm.DB) *NoteMetricRepository {
+	return &NoteMetricRepository{db: db}
+}
+
+// ListByNoteID 获取笔记的快照时间序列（按用户隔离，按时间升序）
+// from/to 为零值时不限制
+func (r *NoteMetricRepository) ListByNoteID(userID, noteID string, from, to time.Time) ([]*model.NoteMetricSnapshot, error) {
+	var snapshots []*model.NoteMetricSnapshot
+	q := r.db.Where("user_id = ? AND note_id = ?", userID, noteID)
+	if !from.IsZero() {
+		q = q.Where("created_at >= ?", from)
+	}
+	if !to.IsZero() {
+		q = q.Where("created_at <= ?", to)
+	}
+	err := q.Order("created_at ASC").Find(&snapshots).Error
+	return snapshots, err
+}
+
+// MetricDelta 一段时间内的互动增量
+type MetricDelta struct {
+	Likes    int32 `json:"likes"`
+	Collects int32 `json:"collects"`
+	Comments int32 `json:"comments"`
+}
+
+// NoteGrowth 笔记互动增长（24 小时 / 7 天）
+// 窗口起点之前没有快照时，以窗口内最早的快照为基准；只有一条快照时为 nil
+type NoteGrowth struct {
+	Delta24h *MetricDelta `json:"delta24h"`
+	Delta7d  *MetricDelta `json:"delta7d"`
+}
+
+// GetGrowth 批量计算笔记的互动增长（按用户隔离）
+// 以最新快照为当前值，与窗口起点处的快照做差
+func (r *NoteMetricRepository) GetGrowth(userID string, noteIDs []string, now time.Time) (map[string]*NoteGrowth, error) {
+	result := make(map[string]*NoteGrowth, len(noteIDs))
+	if len(noteIDs) == 0 {
+		return result, nil
+	}
+
+	latest, err := r.latestSnapshotsBefore(userID, noteIDs, now)
+	if err != nil {
+		return nil, err
+	}
+	earliest, err := r.earliestSnapshots(userID, noteIDs)
+	if err != nil {
+		return nil, err
+	}
+	dayBase, err := r.latestSnapshotsBefore(userID, noteIDs, now.Add(-24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	weekBase, err := r.latestSnapshotsBefore(userID, noteIDs, now.Add(-7*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range noteIDs {
+		current, ok := latest[id]
+		if !ok {
+			continue
+		}
+		result[id] = &NoteGrowth{
+			Delta24h: metricDelta(current, pickBaseline(dayBase[id], earliest[id])),
+			Delta7d:  metricDelta(current, pickBaseline(weekBase[id], earliest[id])),
+		}
+	}
+	return result, nil
+}
+
+// latestSnapshotsBefore 每篇笔记在 at 之前（含）最新的一条快照
+func (r *NoteMetricRepository) latestSnapshotsBefore(userID string, noteIDs []string, at time.Time) (map[string]*model.NoteMetricSnapshot, error) {
+	var snapshots []*model.NoteMetricSnapshot
+	err := r.db.Raw(`SELECT DISTINCT ON (note_id) * FROM note_metric_snapshots
+		WHERE user_id = ? AND note_id IN ? AND created_at <= ?
+		ORDER BY note_id, created_at DESC`, userID, noteIDs, at).Scan(&snapshots).Error
+	if err != nil {
+		return nil, err
+	}
+	return snapshotsByNote(snapshots), nil
+}
+
+// earliestSnapshots 每篇笔记最早的一条快照
+func (r *NoteMetricRepository) earliestSnapshots(userID string, noteIDs []string) (map[string]*model.NoteMetricSnapshot, error) {
+	var snapshots []*model.NoteMetricSnapshot
+	err := r.db.Raw(`SELECT DISTINCT ON (note_id) * FROM note_metric_snapshots
+		WHERE user_id = ? AND note_id IN ?
+		ORDER BY note_id, created_at ASC`, userID, noteIDs).Scan(&snapshots).Error
+	if err != nil {
+		return nil, err
+	}
+	return snapshotsByNote(snapshots), nil
+}
+
+func snapshotsByNote(snapshots []*model.NoteMetricSnapshot) map[string]*model.NoteMetricSnapshot {
+	byNote := make(map[string]*model.NoteMetricSnapshot, len(snapshots))
+	for _, s := range snapshots {
+		byNote[s.NoteID] = s
+	}
+	return byNote
+}
+
+// pickBaseline 窗口起点前有快照则用之，否则退回最早的快照
+func pickBaseline(base, earliest *model.NoteMetricSnapshot) *model.NoteMetricSnapshot {
+	if base != nil {
+		return base
+	}
+	return earliest
+}
+
+// metricDelta 计算两条快照之间的增量，基准缺失或与当前为同一条时返回 nil
+func metricDelta(current, base *model.NoteMetricSnapshot) *MetricDelta {
+	if current == nil || base == nil || current.ID == base.ID {
+		return nil
+	}
+	return &MetricDelta{
+		Likes:    current.Likes - base.Likes,
+		Collects: current.Collects - base.Collects,
+		Comments: current.Comments - base.Comments,
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"time"
 
 	"github.com/keenchase/edit-business/internal/model"
+	"github.com/keenchase/edit-business/internal/xhsurl"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // NoteRepository 笔记数据仓库
@@ -18,9 +20,21 @@ func NewNoteRepository(db *gorm.DB) *NoteRepository {
 	return &NoteRepository{db: db}
 }
 
-// Create 创建笔记
+// Create 创建笔记（同时写入互动数据快照和检索索引，媒体加入归档队列）
 func (r *NoteRepository) Create(note *model.Note) error {
-	return r.db.Create(note).Error
+	fillXhsNoteID(note)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(note).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(model.NewNoteMetricSnapshot(note)).Error; err != nil {
+			return err
+		}
+		if err := indexNote(tx, note); err != nil {
+			return err
+		}
+		return syncNoteMedia(tx, note)
+	})
 }
 
 // GetByID 根据 ID 获取笔记
@@ -53,39 +67,44 @@ func (r *NoteRepository) GetByUserIDAndURL(userID string, url string) (*model.No
 	return &note, nil
 }
 
-// List 获取笔记列表（按用户隔离）
-func (r *NoteRepository) List(userID string, offset, limit int) ([]*model.Note, int64, error) {
-	var notes []*model.Note
-	var total int64
-
-	// 计算总数
-	if err := r.db.Model(&model.Note{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
-		return nil, 0, err
+// GetByIdentity 获取同一篇笔记（按用户隔离）：有笔记 ID 时按笔记 ID 或链接匹配，否则按链接匹配
+func (r *NoteRepository) GetByIdentity(userID, xhsNoteID, url string) (*model.Note, error) {
+	var note model.Note
+	err := whereNoteIdentity(r.db.Where("user_id = ?", userID), xhsNoteID, url).First(&note).Error
+	if err != nil {
+		return nil, err
 	}
+	return &note, nil
+}
 
-	// 分页查询
-	err := r.db.Where("user_id = ?", userID).
-		Order("capture_timestamp DESC").
-		Offset(offset).
-		Limit(limit).
-		Find(&notes).Error
+// whereNoteIdentity 按笔记 ID 或链接匹配同一篇笔记（兼容尚未回填笔记 ID 的历史记录）
+func whereNoteIdentity(q *gorm.DB, xhsNoteID, url string) *gorm.DB {
+	if xhsNoteID != "" {
+		return q.Where("(xhs_note_id = ? OR url = ?)", xhsNoteID, url)
+	}
+	return q.Where("url = ?", url)
+}
 
-	return notes, total, err
+// fillXhsNoteID 从链接中提取笔记 ID（调用方未提供时）
+func fillXhsNoteID(note *model.Note) {
+	if note.XhsNoteID == "" {
+		note.XhsNoteID = xhsurl.NoteID(note.URL)
+	}
 }
 
-// ListBySource 根据 source 获取笔记列表（按用户隔离）
-func (r *NoteRepository) ListBySource(userID, source string, offset, limit int) ([]*model.Note, int64, error) {
+// List 获取笔记列表（按用户隔离，支持组合筛选和排序）
+func (r *NoteRepository) List(userID string, filter *NoteFilter, sort Sort, offset, limit int) ([]*model.Note, int64, error) {
 	var notes []*model.Note
 	var total int64
 
 	// 计算总数
-	if err := r.db.Model(&model.Note{}).Where("user_id = ? AND source = ?", userID, source).Count(&total).Error; err != nil {
+	if err := r.filteredQuery(userID, filter).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// 分页查询
-	err := r.db.Where("user_id = ? AND source = ?", userID, source).
-		Order("capture_timestamp DESC").
+	err := r.filteredQuery(userID, filter).
+		Order(sort.orderClause()).
 		Offset(offset).
 		Limit(limit).
 		Find(&notes).Error
@@ -93,49 +112,98 @@ func (r *NoteRepository) ListBySource(userID, source string, offset, limit int)
 	return notes, total, err
 }
 
-// ListByAuthor 根据作者获取笔记列表（按用户隔离）
-func (r *NoteRepository) ListByAuthor(userID, author string, offset, limit int) ([]*model.Note, int64, error) {
-	var notes []*model.Note
-	var total int64
-
-	// 计算总数
-	if err := r.db.Model(&model.Note{}).Where("user_id = ? AND author = ?", userID, author).Count(&total).Error; err != nil {
-		return nil, 0, err
+// ListByCursor 游标分页获取笔记列表（按用户隔离）
+// cursor 为空时从第一页开始；返回的 nextCursor 为空表示没有更多数据
+func (r *NoteRepository) ListByCursor(userID string, filter *NoteFilter, sort Sort, cursor string, limit int) ([]*model.Note, string, error) {
+	q := r.filteredQuery(userID, filter)
+	if cursor != "" {
+		value, id, err := sort.decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		q = q.Where(sort.keysetCondition(), value, id)
 	}
 
-	// 分页查询
-	err := r.db.Where("user_id = ? AND author = ?", userID, author).
-		Order("capture_timestamp DESC").
-		Offset(offset).
-		Limit(limit).
-		Find(&notes).Error
+	// 多取一条判断是否还有下一页
+	var notes []*model.Note
+	if err := q.Order(sort.orderClause()).Limit(limit + 1).Find(&notes).Error; err != nil {
+		return nil, "", err
+	}
+	if len(notes) <= limit {
+		return notes, "", nil
+	}
 
-	return notes, total, err
+	notes = notes[:limit]
+	last := notes[limit-1]
+	return notes, sort.encodeCursor(noteSortValue(last, sort.Field), last.ID), nil
 }
 
-// ListByTags 根据标签获取笔记列表（按用户隔离）
-func (r *NoteRepository) ListByTags(userID string, tags []string, offset, limit int) ([]*model.Note, int64, error) {
-	var notes []*model.Note
-	var total int64
+// Stream 按筛选条件逐行读取笔记（按用户隔离），不缓存整个结果集，用于导出
+func (r *NoteRepository) Stream(userID string, filter *NoteFilter, sort Sort, fn func(*model.Note) error) error {
+	rows, err := r.filteredQuery(userID, filter).Order(sort.orderClause()).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
 
-	// 计算总数
-	if err := r.db.Model(&model.Note{}).Where("user_id = ? AND tags && ?", userID, tags).Count(&total).Error; err != nil {
-		return nil, 0, err
+	for rows.Next() {
+		var note model.Note
+		if err := r.db.ScanRows(rows, &note); err != nil {
+			return err
+		}
+		if err := fn(&note); err != nil {
+			return err
+		}
 	}
+	return rows.Err()
+}
 
-	// 分页查询
-	err := r.db.Where("user_id = ? AND tags && ?", userID, tags).
-		Order("capture_timestamp DESC").
-		Offset(offset).
-		Limit(limit).
-		Find(&notes).Error
+// Count 统计符合筛选条件的笔记数（按用户隔离）
+func (r *NoteRepository) Count(userID string, filter *NoteFilter) (int64, error) {
+	var total int64
+	err := r.filteredQuery(userID, filter).Count(&total).Error
+	return total, err
+}
 
-	return notes, total, err
+// filteredQuery 按用户隔离并叠加组合筛选条件的基础查询
+func (r *NoteRepository) filteredQuery(userID string, filter *NoteFilter) *gorm.DB {
+	return applyNoteFilter(r.db.Model(&model.Note{}).Where("user_id = ?", userID), filter)
 }
 
-// Update 更新笔记
+// Update 更新笔记（同时重建检索索引，新的媒体链接加入归档队列）
 func (r *NoteRepository) Update(note *model.Note) error {
-	return r.db.Save(note).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		note.Version++
+		if err := tx.Save(note).Error; err != nil {
+			return err
+		}
+		if err := indexNote(tx, note); err != nil {
+			return err
+		}
+		return syncNoteMedia(tx, note)
+	})
+}
+
+// Patch 按版本号条件更新笔记的部分字段（同时重建检索索引，新的媒体链接加入归档队列）
+// version 与当前记录不一致时返回 ErrVersionConflict
+func (r *NoteRepository) Patch(userID, id string, version int64, updates map[string]interface{}) (*model.Note, error) {
+	var note model.Note
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := patchWithVersion(tx, &model.Note{}, userID, id, version, updates); err != nil {
+			return err
+		}
+		if err := tx.Where("id = ?", id).First(&note).Error; err != nil {
+			return err
+		}
+		if err := indexNote(tx, &note); err != nil {
+			return err
+		}
+		return syncNoteMedia(tx, &note)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
 }
 
 // Upsert 创建或更新笔记（智能合并）
@@ -143,32 +211,126 @@ func (r *NoteRepository) Update(note *model.Note) error {
 // 如果记录存在但无完整数据，更新为新数据
 // 如果记录不存在，创建新记录
 func (r *NoteRepository) Upsert(note *model.Note) (*model.Note, error) {
-	// 查找是否存在相同 user_id + url 的记录
-	existing, err := r.GetByUserIDAndURL(note.UserID, note.URL)
+	return r.UpsertToCollection(note, "")
+}
+
+// UpsertToCollection 创建或更新笔记，并在同一事务中追加到收藏夹（collectionID 为空时不处理）
+// 调用方负责校验收藏夹归属
+func (r *NoteRepository) UpsertToCollection(note *model.Note, collectionID string) (*model.Note, error) {
+	var result *model.Note
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		saved, _, err := saveNote(tx, note, collectionID)
+		result = saved
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// NoteBatchItem 批量写入的一条笔记及其目标收藏夹
+type NoteBatchItem struct {
+	Note         *model.Note
+	CollectionID string
+}
+
+// UpsertBatch 批量创建或更新笔记，结果与 items 一一对应
+// atomic 为 true 时全部在一个事务中写入，任一条失败整体回滚并返回 *BatchItemError；
+// 否则每条单独提交，失败的条目在结果中带上错误
+func (r *NoteRepository) UpsertBatch(items []*NoteBatchItem, atomic bool) ([]*BatchResult, error) {
+	results := make([]*BatchResult, len(items))
+	save := func(tx *gorm.DB, i int) error {
+		saved, outcome, err := saveNote(tx, items[i].Note, items[i].CollectionID)
+		if err != nil {
+			return err
+		}
+		results[i] = &BatchResult{ID: saved.ID, Outcome: outcome}
+		return nil
+	}
+
+	if err := runBatch(r.db, len(items), atomic, save, results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// saveNote 在调用方的事务中合并写入笔记，并记录互动数据快照、更新检索索引和收藏夹，
+// 新的媒体链接加入归档队列
+func saveNote(tx *gorm.DB, note *model.Note, collectionID string) (*model.Note, string, error) {
+	saved, outcome, err := upsertNote(tx, note)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// 无论是否覆盖现有记录，都记录本次采集看到的互动数据
+	snapshot := model.NewNoteMetricSnapshot(note)
+	snapshot.NoteID = saved.ID
+	if err := tx.Create(snapshot).Error; err != nil {
+		return nil, "", err
+	}
+	if err := indexNote(tx, saved); err != nil {
+		return nil, "", err
+	}
+	if err := syncNoteMedia(tx, saved); err != nil {
+		return nil, "", err
+	}
+	if collectionID != "" {
+		if _, err := addCollectionNotes(tx, collectionID, []string{saved.ID}); err != nil {
+			return nil, "", err
+		}
+	}
+	return saved, outcome, nil
+}
+
+// upsertNote Upsert 的合并逻辑，在调用方的事务中执行，返回写入结果（UpsertCreated 等）
+func upsertNote(tx *gorm.DB, note *model.Note) (*model.Note, string, error) {
+	if note.AuthorXhsID == "" {
+		id, err := matchAuthorXhsID(tx, note.UserID, note.Author)
+		if err != nil {
+			return nil, "", err
+		}
+		note.AuthorXhsID = id
+	}
+
+	fillXhsNoteID(note)
+
+	// 查找同一用户的同一篇笔记（按笔记 ID，兼容按链接匹配；加行锁，避免与编辑接口交错写入）
+	var existing model.Note
+	err := whereNoteIdentity(tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", note.UserID), note.XhsNoteID, note.URL).
+		Order("created_at, id").
+		First(&existing).Error
 
 	if err != nil {
 		// 记录不存在，创建新记录
 		if err == gorm.ErrRecordNotFound {
-			if err := r.db.Create(note).Error; err != nil {
-				return nil, err
+			if err := tx.Create(note).Error; err != nil {
+				return nil, "", err
 			}
-			return note, nil
+			return note, UpsertCreated, nil
 		}
 		// 其他错误
-		return nil, err
+		return nil, "", err
 	}
 
 	// 记录存在，判断是否需要更新
 	// 如果现有记录有完整数据（content 不为空），且新数据没有更完整的信息，保留现有记录
 	if existing.Content != "" && note.Content == "" {
 		// 现有记录已经是完整版，新数据是简化版，保留完整版
-		return existing, nil
+		return &existing, UpsertSkipped, nil
 	}
 
 	// 更新记录：使用新数据填充现有记录
 	// 保留 ID 和创建时间，更新其他字段
+	existing.URL = note.URL // 使用最新链接（xsec_token 等参数会过期）
+	if note.XhsNoteID != "" {
+		existing.XhsNoteID = note.XhsNoteID
+	}
 	existing.Title = note.Title
 	existing.Author = note.Author
+	if note.AuthorXhsID != "" {
+		existing.AuthorXhsID = note.AuthorXhsID
+	}
 	existing.Likes = note.Likes
 	existing.Collects = note.Collects
 	existing.Comments = note.Comments
@@ -190,24 +352,124 @@ func (r *NoteRepository) Upsert(note *model.Note) (*model.Note, error) {
 	}
 
 	// 保存更新
-	if err := r.db.Save(existing).Error; err != nil {
-		return nil, err
+	existing.Version++
+	if err := tx.Save(&existing).Error; err != nil {
+		return nil, "", err
 	}
 
-	return existing, nil
+	return &existing, UpsertUpdated, nil
+}
+
+// matchAuthorXhsID 按作者名称匹配用户已采集的博主，仅在同名博主唯一时返回其 xhs_id
+func matchAuthorXhsID(tx *gorm.DB, userID, author string) (string, error) {
+	if author == "" {
+		return "", nil
+	}
+	var xhsIDs []string
+	err := tx.Model(&model.Blogger{}).
+		Where("user_id = ? AND blogger_name = ?", userID, author).
+		Limit(2).
+		Pluck("xhs_id", &xhsIDs).Error
+	if err != nil || len(xhsIDs) != 1 {
+		return "", err
+	}
+	return xhsIDs[0], nil
 }
 
-// Delete 删除笔记（按用户隔离，防止越权删除）
+// Delete 删除笔记到回收站（按用户隔离，防止越权删除）
+// 同时移除检索词项，恢复时重建
 func (r *NoteRepository) Delete(userID, id string) error {
-	return r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&model.Note{}).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&model.Note{})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return deleteNoteTerms(tx, "note_id = ?", id)
+	})
+}
+
+// GetTrashed 获取回收站中的笔记（按用户隔离）
+func (r *NoteRepository) GetTrashed(userID, id string) (*model.Note, error) {
+	var note model.Note
+	err := r.db.Unscoped().
+		Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userID).
+		First(&note).Error
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+// GetTrashedByIdentity 获取回收站中的同一篇笔记（按笔记 ID 或链接匹配，最近删除的一条）
+func (r *NoteRepository) GetTrashedByIdentity(userID, xhsNoteID, url string) (*model.Note, error) {
+	var note model.Note
+	err := whereNoteIdentity(r.db.Unscoped().Where("user_id = ? AND deleted_at IS NOT NULL", userID), xhsNoteID, url).
+		Order("deleted_at DESC").
+		First(&note).Error
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+// Restore 从回收站恢复笔记并重建检索索引
+// replaceID 非空时先把该笔记（通常是重新采集产生的同链接记录）移入回收站
+func (r *NoteRepository) Restore(userID, id, replaceID string) (*model.Note, error) {
+	var note model.Note
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if replaceID != "" {
+			if err := tx.Where("id = ? AND user_id = ?", replaceID, userID).Delete(&model.Note{}).Error; err != nil {
+				return err
+			}
+			if err := deleteNoteTerms(tx, "note_id = ?", replaceID); err != nil {
+				return err
+			}
+		}
+
+		res := tx.Unscoped().Model(&model.Note{}).
+			Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userID).
+			Updates(map[string]interface{}{"deleted_at": nil, "version": gorm.Expr("version + 1")})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Where("id = ?", id).First(&note).Error; err != nil {
+			return err
+		}
+		return indexNote(tx, &note)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
 }
 
-// BatchCreate 批量创建笔记
+// BatchCreate 批量创建笔记（同时写入互动数据快照和检索索引）
 func (r *NoteRepository) BatchCreate(notes []*model.Note) error {
 	if len(notes) == 0 {
 		return nil
 	}
-	return r.db.Create(&notes).Error
+	for _, note := range notes {
+		fillXhsNoteID(note)
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&notes).Error; err != nil {
+			return err
+		}
+		snapshots := make([]*model.NoteMetricSnapshot, len(notes))
+		for i, note := range notes {
+			snapshots[i] = model.NewNoteMetricSnapshot(note)
+			if err := indexNote(tx, note); err != nil {
+				return err
+			}
+			if err := syncNoteMedia(tx, note); err != nil {
+				return err
+			}
+		}
+		return tx.Create(&snapshots).Error
+	})
 }
 
 // NoteStats 笔记统计数据
@@ -265,19 +527,121 @@ func (r *NoteRepository) TotalCount() (int64, error) {
 	return count, err
 }
 
-// CountByUserAndDate counts notes by user ID and date
-func (r *NoteRepository) CountByUserAndDate(userID string, date string) (int64, error) {
+// CountCreatedBetween 统计用户在 [from, to) 内新增的笔记数量（含回收站，删除不返还采集额度）
+func (r *NoteRepository) CountCreatedBetween(userID string, from, to time.Time) (int64, error) {
 	var count int64
-	query := r.db.Model(&model.Note{}).Where("user_id = ?", userID)
+	err := r.db.Unscoped().Model(&model.Note{}).
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, from, to).
+		Count(&count).Error
+	return count, err
+}
+
+// ExistingIDs 返回 ids 中属于该用户且不在回收站中的笔记 ID
+func (r *NoteRepository) ExistingIDs(userID string, ids []string) ([]string, error) {
+	var found []string
+	if len(ids) == 0 {
+		return found, nil
+	}
+	err := r.db.Model(&model.Note{}).
+		Where("user_id = ? AND id IN ?", userID, ids).
+		Pluck("id", &found).Error
+	return found, err
+}
+
+// MatchExisting 逐条判断 notes 是否会更新已有笔记（与 Upsert 相同的去重规则，不含回收站），
+// 批内较早出现的同一篇笔记也算已有；用于只对新增的笔记计算每日额度
+func (r *NoteRepository) MatchExisting(userID string, notes []*model.Note) ([]bool, error) {
+	matched := make([]bool, len(notes))
+	if len(notes) == 0 {
+		return matched, nil
+	}
+	var xhsNoteIDs, urls []string
+	for _, note := range notes {
+		fillXhsNoteID(note)
+		if note.XhsNoteID != "" {
+			xhsNoteIDs = append(xhsNoteIDs, note.XhsNoteID)
+		}
+		urls = append(urls, note.URL)
+	}
 
-	if date != "" {
-		query = query.Where("DATE(created_at) = ?", date)
+	q := r.db.Model(&model.Note{}).Select("xhs_note_id", "url").Where("user_id = ?", userID)
+	if len(xhsNoteIDs) > 0 {
+		q = q.Where("(xhs_note_id IN ? OR url IN ?)", xhsNoteIDs, urls)
 	} else {
-		// If no date specified, use today
-		today := time.Now().Format("2006-01-02")
-		query = query.Where("DATE(created_at) = ?", today)
+		q = q.Where("url IN ?", urls)
+	}
+	var existing []*model.Note
+	if err := q.Find(&existing).Error; err != nil {
+		return nil, err
 	}
 
-	err := query.Count(&count).Error
-	return count, err
+	seenIDs := make(map[string]bool)
+	seenURLs := make(map[string]bool)
+	for _, note := range existing {
+		if note.XhsNoteID != "" {
+			seenIDs[note.XhsNoteID] = true
+		}
+		seenURLs[note.URL] = true
+	}
+	for i, note := range notes {
+		matched[i] = seenURLs[note.URL] || (note.XhsNoteID != "" && seenIDs[note.XhsNoteID])
+		if note.XhsNoteID != "" {
+			seenIDs[note.XhsNoteID] = true
+		}
+		seenURLs[note.URL] = true
+	}
+	return matched, nil
+}
+
+// AuthorStats 某个作者（博主）已采集笔记的汇总数据
+type AuthorStats struct {
+	NoteCount   int64       `json:"noteCount"`
+	TotalLikes  int64       `json:"totalLikes"`
+	AvgLikes    float64     `json:"avgLikes"`
+	AvgCollects float64     `json:"avgCollects"`
+	AvgComments float64     `json:"avgComments"`
+	BestNote    *model.Note `json:"bestNote"` // 点赞最多的笔记，没有笔记时为 null
+}
+
+// GetAuthorStats 统计某个作者（按 author_xhs_id）已采集笔记的汇总数据（按用户隔离）
+func (r *NoteRepository) GetAuthorStats(userID, authorXhsID string) (*AuthorStats, error) {
+	// 聚合结果先扫描到不含关联字段的结构体，避免 GORM 把 BestNote 解析为关联
+	var agg struct {
+		NoteCount   int64
+		TotalLikes  int64
+		AvgLikes    float64
+		AvgCollects float64
+		AvgComments float64
+	}
+	err := r.db.Model(&model.Note{}).
+		Where("user_id = ? AND author_xhs_id = ?", userID, authorXhsID).
+		Select(`COUNT(*) AS note_count,
+			COALESCE(SUM(likes), 0) AS total_likes,
+			COALESCE(AVG(likes), 0) AS avg_likes,
+			COALESCE(AVG(collects), 0) AS avg_collects,
+			COALESCE(AVG(comments), 0) AS avg_comments`).
+		Scan(&agg).Error
+	if err != nil {
+		return nil, err
+	}
+	stats := AuthorStats{
+		NoteCount:   agg.NoteCount,
+		TotalLikes:  agg.TotalLikes,
+		AvgLikes:    agg.AvgLikes,
+		AvgCollects: agg.AvgCollects,
+		AvgComments: agg.AvgComments,
+	}
+	if stats.NoteCount == 0 {
+		return &stats, nil
+	}
+
+	var best model.Note
+	err = r.db.Where("user_id = ? AND author_xhs_id = ?", userID, authorXhsID).
+		Order("likes DESC, collects DESC, id").
+		First(&best).Error
+	if err != nil {
+		return nil, err
+	}
+	stats.BestNote = &best
+	return &stats, nil
 }
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"github.com/keenchase/edit-business/internal/analyzer"
+	"github.com/keenchase/edit-business/internal/model"
+	"github.com/keenchase/edit-business/internal/search"
+
+	"gorm.io/gorm"
+)
+
+// titleWeight 标题命中的权重（相对正文）
+const titleWeight = 3
+
+// deleteNoteTerms 删除笔记的检索词项和内容分析结果（query 为 note_id 条件）
+func deleteNoteTerms(tx *gorm.DB, query interface{}, args ...interface{}) error {
+	if err := tx.Where(query, args...).Delete(&model.NoteSearchTerm{}).Error; err != nil {
+		return err
+	}
+	return tx.Where(query, args...).Delete(&model.NoteContentTerm{}).Error
+}
+
+// indexNote 重建单篇笔记的检索词项和内容分析结果，在调用方的事务中执行
+func indexNote(tx *gorm.DB, note *model.Note) error {
+	if err := deleteNoteTerms(tx, "note_id = ?", note.ID); err != nil {
+		return err
+	}
+	if err := analyzeNote(tx, note); err != nil {
+		return err
+	}
+
+	titleTerms := search.IndexTerms(note.Title)
+	contentTerms := search.IndexTerms(note.Content)
+
+	rows := make(map[string]*model.NoteSearchTerm, len(titleTerms)+len(contentTerms))
+	termRow := func(term string) *model.NoteSearchTerm {
+		row, ok := rows[term]
+		if !ok {
+			row = &model.NoteSearchTerm{NoteID: note.ID, Term: term, UserID: note.UserID}
+			rows[term] = row
+		}
+		return row
+	}
+	for term, tf := range titleTerms {
+		termRow(term).TitleTF = tf
+	}
+	for term, tf := range contentTerms {
+		termRow(term).ContentTF = tf
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	terms := make([]*model.NoteSearchTerm, 0, len(rows))
+	for _, row := range rows {
+		terms = append(terms, row)
+	}
+	return tx.CreateInBatches(terms, 500).Error
+}
+
+// analyzeNote 写入笔记的话题、提及、表情和关键词（调用方已删除旧结果）
+func analyzeNote(tx *gorm.DB, note *model.Note) error {
+	result := analyzer.Analyze(note.Title, note.Content, note.Tags)
+	var terms []*model.NoteContentTerm
+	for _, kind := range analyzer.Kinds {
+		for term, count := range result.Terms(kind) {
+			terms = append(terms, &model.NoteContentTerm{NoteID: note.ID, Kind: kind, Term: term, UserID: note.UserID, Count: count})
+		}
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(terms, 500).Error
+}
+
+// Reindex 重建某个用户全部笔记的检索索引和内容分析结果（用于历史数据回填）
+func (r *NoteRepository) Reindex(userID string) (int, error) {
+	var notes []*model.Note
+	count := 0
+	err := r.db.Where("user_id = ?", userID).FindInBatches(&notes, 200, func(batch *gorm.DB, _ int) error {
+		return r.db.Transaction(func(tx *gorm.DB) error {
+			for _, note := range notes {
+				if err := indexNote(tx, note); err != nil {
+					return err
+				}
+				count++
+			}
+			return nil
+		})
+	}).Error
+	return count, err
+}
+
+// NoteSearchHit 检索命中结果
+type NoteSearchHit struct {
+	Note    *model.Note
+	Score   float64
+	Matched int
+}
+
+// Search 全文检索笔记（按用户隔离）
+// 要求命中全部查询词项；按 TF-IDF 打分排序，标题命中权重更高
+func (r *NoteRepository) Search(userID string, terms []string, offset, limit int) ([]*NoteSearchHit, int64, error) {
+	if len(terms) == 0 {
+		return []*NoteSearchHit{}, 0, nil
+	}
+
+	matchSQL := `SELECT t.note_id,
+			COUNT(*) AS matched,
+			SUM((t.title_tf * ? + LEAST(t.content_tf, 10)) * LN(1 + total.n / df.df::float)) AS score
+		FROM note_search_terms t
+		JOIN (SELECT term, COUNT(*) AS df FROM note_search_terms
+			WHERE user_id = ? AND term IN ? GROUP BY term) df ON df.term = t.term
+		CROSS JOIN (SELECT COUNT(*) AS n FROM notes WHERE user_id = ? AND deleted_at IS NULL) total
+		WHERE t.user_id = ? AND t.term IN ?
+		GROUP BY t.note_id
+		HAVING COUNT(*) = ?`
+	args := []interface{}{titleWeight, userID, terms, userID, userID, terms, len(terms)}
+
+	var total int64
+	if err := r.db.Raw("SELECT COUNT(*) FROM ("+matchSQL+") m", args...).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []struct {
+		NoteID  string
+		Matched int
+		Score   float64
+	}
+	err := r.db.Raw(matchSQL+" ORDER BY score DESC, t.note_id LIMIT ? OFFSET ?", append(args, limit, offset)...).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(rows) == 0 {
+		return []*NoteSearchHit{}, total, nil
+	}
+
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = row.NoteID
+	}
+	var notes []*model.Note
+	if err := r.db.Where("user_id = ? AND id IN ?", userID, ids).Find(&notes).Error; err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[string]*model.Note, len(notes))
+	for _, note := range notes {
+		byID[note.ID] = note
+	}
+
+	hits := make([]*NoteSearchHit, 0, len(rows))
+	for _, row := range rows {
+		if note, ok := byID[row.NoteID]; ok {
+			hits = append(hits, &NoteSearchHit{Note: note, Score: row.Score, Matched: row.Matched})
+		}
+	}
+	return hits, total, nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+)
+
+// 时间序列的分桶粒度
+const (
+	IntervalDay  = "day"
+	IntervalWeek = "week" // 自然周，周一开始
+)
+
+// SeriesRow 时间序列的一个分桶（按来源、笔记类型细分）
+// Bucket 为用户时区下分桶起始日期（YYYY-MM-DD）
+type SeriesRow struct {
+	Bucket   string
+	Source   string
+	NoteType string
+	Count    int64
+	Likes    int64
+	Collects int64
+	Comments int64
+}
+
+// bucketExpr 按用户时区分桶的 SQL 表达式（interval 和时区由调用方校验，以参数传入）
+func bucketExpr(column string) string {
+	return fmt.Sprintf("to_char(date_trunc(?, %s AT TIME ZONE ?), 'YYYY-MM-DD')", column)
+}
+
+// CaptureSeries 按天/周统计用户在 [from, to) 内采集的笔记数和互动量，按来源、笔记类型细分
+func (r *NoteRepository) CaptureSeries(userID, interval, timezone string, from, to time.Time) ([]*SeriesRow, error) {
+	var rows []*SeriesRow
+	err := r.db.Raw(`SELECT `+bucketExpr("created_at")+` AS bucket,
+			COALESCE(NULLIF(source, ''), 'unknown') AS source,
+			COALESCE(NULLIF(note_type, ''), 'unknown') AS note_type,
+			COUNT(*) AS count,
+			COALESCE(SUM(likes), 0) AS likes,
+			COALESCE(SUM(collects), 0) AS collects,
+			COALESCE(SUM(comments), 0) AS comments
+		FROM notes
+		WHERE user_id = ? AND deleted_at IS NULL AND created_at >= ? AND created_at < ?
+		GROUP BY 1, 2, 3
+		ORDER BY 1`, interval, timezone, userID, from, to).Scan(&rows).Error
+	return rows, err
+}
+
+// CaptureSeries 按天/周统计用户在 [from, to) 内采集的博主数
+func (r *BloggerRepository) CaptureSeries(userID, interval, timezone string, from, to time.Time) ([]*SeriesRow, error) {
+	var rows []*SeriesRow
+	err := r.db.Raw(`SELECT `+bucketExpr("created_at")+` AS bucket,
+			COUNT(*) AS count
+		FROM bloggers
+		WHERE user_id = ? AND deleted_at IS NULL AND created_at >= ? AND created_at < ?
+		GROUP BY 1
+		ORDER BY 1`, interval, timezone, userID, from, to).Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidSort   = errors.New("invalid sort field")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// sortColumn 可排序字段对应的 SQL 表达式
+type sortColumn struct {
+	expr   string
+	isTime bool // 时间类型字段，游标中以 RFC3339Nano 字符串保存
+}
+
+// Sort 列表排序（按白名单字段 + id 次级排序，保证结果稳定、可用于游标分页）
+type Sort struct {
+	Field  string // API 字段名
+	Desc   bool
+	column sortColumn
+}
+
+// parseSort 按白名单解析排序参数，field 为空时使用 defaultField，order 为 asc / desc（默认 desc）
+func parseSort(columns map[string]sortColumn, defaultField, field, order string) (Sort, error) {
+	if field == "" {
+		field = defaultField
+	}
+	column, ok := columns[field]
+	if !ok {
+		return Sort{}, ErrInvalidSort
+	}
+	switch order {
+	case "", "desc":
+		return Sort{Field: field, Desc: true, column: column}, nil
+	case "asc":
+		return Sort{Field: field, Desc: false, column: column}, nil
+	default:
+		return Sort{}, ErrInvalidSort
+	}
+}
+
+func (s Sort) direction() string {
+	if s.Desc {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// orderClause 生成 ORDER BY 子句
+func (s Sort) orderClause() string {
+	return fmt.Sprintf("%s %s, id %s", s.column.expr, s.direction(), s.direction())
+}
+
+// keysetCondition 生成游标之后的行条件：(排序值, id) 严格位于游标之后
+func (s Sort) keysetCondition() string {
+	op := ">"
+	if s.Desc {
+		op = "<"
+	}
+	return fmt.Sprintf("(%s, id) %s (?, ?)", s.column.expr, op)
+}
+
+// cursorPayload 游标内容（对客户端不透明）
+type cursorPayload struct {
+	Field string          `json:"f"`
+	Desc  bool            `json:"d"`
+	Value json.RawMessage `json:"v"`
+	ID    string          `json:"id"`
+}
+
+// encodeCursor 根据最后一行的排序值和 id 生成游标
+func (s Sort) encodeCursor(value interface{}, id string) string {
+	if t, ok := value.(time.Time); ok {
+		value = t.UTC().Format(time.RFC3339Nano)
+	}
+	raw, _ := json.Marshal(value)
+	data, _ := json.Marshal(cursorPayload{Field: s.Field, Desc: s.Desc, Value: raw, ID: id})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor 解析游标，返回排序值和 id；游标与当前排序不一致时返回 ErrInvalidCursor
+func (s Sort) decodeCursor(cursor string) (interface{}, string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, "", ErrInvalidCursor
+	}
+	var p cursorPayload
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, "", ErrInvalidCursor
+	}
+	if p.Field != s.Field || p.Desc != s.Desc || p.ID == "" {
+		return nil, "", ErrInvalidCursor
+	}
+
+	if s.column.isTime {
+		var v string
+		if err := json.Unmarshal(p.Value, &v); err != nil {
+			return nil, "", ErrInvalidCursor
+		}
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return nil, "", ErrInvalidCursor
+		}
+		return t, p.ID, nil
+	}
+
+	var v int64
+	if err := json.Unmarshal(p.Value, &v); err != nil {
+		return nil, "", ErrInvalidCursor
+	}
+	return v, p.ID, nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseNoteSort(t *testing.T) {
+	tests := []struct {
+		field, order string
+		wantField    string
+		wantDesc     bool
+		wantErr      bool
+	}{
+		{"", "", "captureTimestamp", true, false},
+		{"likes", "asc", "likes", false, false},
+		{"createdAt", "desc", "createdAt", true, false},
+		{"password", "", "", false, true},
+		{"likes", "sideways", "", false, true},
+	}
+	for _, tt := range tests {
+		s, err := ParseNoteSort(tt.field, tt.order)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidSort) {
+				t.Errorf("ParseNoteSort(%q, %q) err = %v, want ErrInvalidSort", tt.field, tt.order, err)
+			}
+			continue
+		}
+		if err != nil || s.Field != tt.wantField || s.Desc != tt.wantDesc {
+			t.Errorf("ParseNoteSort(%q, %q) = %+v, %v", tt.field, tt.order, s, err)
+		}
+	}
+}
+
+func TestSortClauses(t *testing.T) {
+	desc, _ := ParseNoteSort("publishDate", "desc")
+	if got, want := desc.orderClause(), "COALESCE(publish_date, 0) DESC, id DESC"; got != want {
+		t.Errorf("orderClause = %q, want %q", got, want)
+	}
+	if got, want := desc.keysetCondition(), "(COALESCE(publish_date, 0), id) < (?, ?)"; got != want {
+		t.Errorf("keysetCondition = %q, want %q", got, want)
+	}
+	asc, _ := ParseNoteSort("likes", "asc")
+	if got, want := asc.keysetCondition(), "(likes, id) > (?, ?)"; got != want {
+		t.Errorf("keysetCondition = %q, want %q", got, want)
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	ts := time.Date(2026, 3, 1, 8, 30, 0, 123456789, time.FixedZone("CST", 8*3600))
+	tests := []struct {
+		field string
+		value interface{}
+		want  interface{}
+	}{
+		{"likes", int64(42), int64(42)},
+		{"captureTimestamp", int64(1767225600000), int64(1767225600000)},
+		{"createdAt", ts, ts.UTC()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			s, err := ParseNoteSort(tt.field, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			value, id, err := s.decodeCursor(s.encodeCursor(tt.value, "note-1"))
+			if err != nil {
+				t.Fatalf("decodeCursor: %v", err)
+			}
+			if id != "note-1" {
+				t.Errorf("id = %q", id)
+			}
+			if got, ok := value.(time.Time); ok {
+				if !got.Equal(tt.want.(time.Time)) {
+					t.Errorf("value = %v, want %v", got, tt.want)
+				}
+			} else if value != tt.want {
+				t.Errorf("value = %v (%T), want %v", value, value, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejectsMismatch(t *testing.T) {
+	likesDesc, _ := ParseNoteSort("likes", "desc")
+	likesAsc, _ := ParseNoteSort("likes", "asc")
+	created, _ := ParseNoteSort("createdAt", "desc")
+	cursor := likesDesc.encodeCursor(int64(10), "note-1")
+
+	tests := []struct {
+		name   string
+		sort   Sort
+		cursor string
+	}{
+		{"other field", created, cursor},
+		{"other direction", likesAsc, cursor},
+		{"not base64", likesDesc, "%%%"},
+		{"not json", likesDesc, base64.RawURLEncoding.EncodeToString([]byte("nope"))},
+		{"missing id", likesDesc, likesDesc.encodeCursor(int64(10), "")},
+		{"wrong value type", created, created.encodeCursor(int64(10), "note-1")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := tt.sort.decodeCursor(tt.cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("err = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/keenchase/edit-business/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StorageDeletionRepository 存储对象删除队列
+type StorageDeletionRepository struct {
+	db *gorm.DB
+}
+
+// NewStorageDeletionRepository 创建存储对象删除队列仓库实例
+func NewStorageDeletionRepository(db *gorm.DB) *StorageDeletionRepository {
+	return &StorageDeletionRepository{db: db}
+}
+
+// ClaimDue 领取到期的待删除对象，领取后 lease 时间内不会被再次领取
+func (r *StorageDeletionRepository) ClaimDue(now time.Time, limit int, lease time.Duration) ([]*model.StorageDeletion, error) {
+	var items []*model.StorageDeletion
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("next_attempt_at <= ?", now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&items).Error
+		if err != nil || len(items) == 0 {
+			return err
+		}
+		urls := make([]string, len(items))
+		for i, item := range items {
+			urls[i] = item.URL
+		}
+		return tx.Model(&model.StorageDeletion{}).Where("url IN ?", urls).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	return items, err
+}
+
+// Done 对象已删除（或无需删除），移出队列
+func (r *StorageDeletionRepository) Done(url string) error {
+	return r.db.Where("url = ?", url).Delete(&model.StorageDeletion{}).Error
+}
+
+// MarkFailed 记录删除失败，retryAt 时间后重试
+func (r *StorageDeletionRepository) MarkFailed(url, reason string, retryAt time.Time) error {
+	return r.db.Model(&model.StorageDeletion{}).Where("url = ?", url).Updates(map[string]interface{}{
+		"attempts":        gorm.Expr("attempts + 1"),
+		"last_error":      reason,
+		"next_attempt_at": retryAt,
+	}).Error
+}
+
+// purgedUploadsSQL 属于被永久删除笔记的直传文件：已关联到这些笔记，或笔记保存前上传、
+// 只记录了小红书笔记 ID 且该用户没有其他同 ID 的笔记（占位符依次为：被删除笔记 ×3）
+const purgedUploadsSQL = `(o.note_id IN (?) OR (o.note_id IS NULL AND o.xhs_note_id <> ''
+	AND EXISTS (SELECT 1 FROM notes n WHERE n.id IN (?) AND n.user_id = o.user_id AND n.xhs_note_id = o.xhs_note_id)
+	AND NOT EXISTS (SELECT 1 FROM notes n WHERE n.id NOT IN (?) AND n.user_id = o.user_id AND n.xhs_note_id = o.xhs_note_id)))`
+
+// purgeNoteObjects 永久删除笔记前调用：把不再被其他笔记引用的归档媒体（含缩略图）和直传文件加入删除队列，
+// 并删除直传文件记录；noteIDs 每次调用返回被删除笔记 ID 的子查询
+func purgeNoteObjects(tx *gorm.DB, noteIDs func() *gorm.DB) error {
+	err := tx.Exec(`INSERT INTO storage_deletions (url, attempts, next_attempt_at, created_at)
+		SELECT url, 0, NOW(), NOW() FROM (
+			SELECT c.url FROM (
+				SELECT archived_url AS url FROM note_media WHERE note_id IN (?)
+				UNION
+				SELECT thumbnail_url FROM note_media WHERE note_id IN (?)
+			) c
+			WHERE c.url <> '' AND NOT EXISTS (
+				SELECT 1 FROM note_media m
+				WHERE (m.archived_url = c.url OR m.thumbnail_url = c.url) AND m.note_id NOT IN (?)
+			)
+			UNION
+			SELECT o.url FROM uploaded_objects o WHERE `+purgedUploadsSQL+`
+		) d
+		ON CONFLICT (url) DO NOTHING`,
+		noteIDs(), noteIDs(), noteIDs(), noteIDs(), noteIDs(), noteIDs()).Error
+	if err != nil {
+		return err
+	}
+	return tx.Exec(`DELETE FROM uploaded_objects o WHERE `+purgedUploadsSQL,
+		noteIDs(), noteIDs(), noteIDs()).Error
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/keenchase/edit-business/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// 回收站条目类型
+const (
+	TrashTypeNote    = "note"
+	TrashTypeBlogger = "blogger"
+)
+
+// TrashRepository 回收站（已软删除的笔记和博主）
+type TrashRepository struct {
+	db *gorm.DB
+}
+
+// NewTrashRepository 创建回收站仓库实例
+func NewTrashRepository(db *gorm.DB) *TrashRepository {
+	return &TrashRepository{db: db}
+}
+
+// TrashEntry 回收站条目（笔记与博主统一展示）
+type TrashEntry struct {
+	Type      string
+	ID        string
+	Title     string
+	URL       string
+	DeletedAt time.Time
+}
+
+// List 按删除时间倒序列出回收站条目（按用户隔离），itemType 为空时包含笔记和博主
+func (r *TrashRepository) List(userID, itemType string, offset, limit int) ([]*TrashEntry, int64, error) {
+	var parts []string
+	var args []interface{}
+	if itemType == "" || itemType == TrashTypeNote {
+		parts = append(parts, `SELECT 'note' AS type, id, title, url, deleted_at FROM notes
+			WHERE user_id = ? AND deleted_at IS NOT NULL`)
+		args = append(args, userID)
+	}
+	if itemType == "" || itemType == TrashTypeBlogger {
+		parts = append(parts, `SELECT 'blogger' AS type, id, blogger_name AS title, blogger_url AS url, deleted_at FROM bloggers
+			WHERE user_id = ? AND deleted_at IS NOT NULL`)
+		args = append(args, userID)
+	}
+	if len(parts) == 0 {
+		return []*TrashEntry{}, 0, nil
+	}
+
+	unionSQL := parts[0]
+	for _, p := range parts[1:] {
+		unionSQL += " UNION ALL " + p
+	}
+
+	var total int64
+	if err := r.db.Raw("SELECT COUNT(*) FROM ("+unionSQL+") t", args...).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	entries := []*TrashEntry{}
+	err := r.db.Raw("SELECT * FROM ("+unionSQL+") t ORDER BY deleted_at DESC, id LIMIT ? OFFSET ?",
+		append(args, limit, offset)...).Scan(&entries).Error
+	return entries, total, err
+}
+
+// TrashScope 永久删除的范围：UserID 为空表示全部用户，ItemType 为空表示笔记和博主，
+// ID 非空时只删除该条，Before 非零时只删除早于该时间进入回收站的记录
+type TrashScope struct {
+	UserID   string
+	ItemType string
+	ID       string
+	Before   time.Time
+}
+
+func (s TrashScope) apply(q *gorm.DB) *gorm.DB {
+	q = q.Unscoped().Where("deleted_at IS NOT NULL")
+	if s.UserID != "" {
+		q = q.Where("user_id = ?", s.UserID)
+	}
+	if s.ID != "" {
+		q = q.Where("id = ?", s.ID)
+	}
+	if !s.Before.IsZero() {
+		q = q.Where("deleted_at < ?", s.Before)
+	}
+	return q
+}
+
+// Purge 永久删除回收站中的记录，返回删除的笔记数和博主数
+// 笔记的互动快照、检索词项、收藏夹成员关系、编辑信息、媒体归档记录和直传文件记录一并删除，博主的粉丝快照一并删除；
+// 不再被其他笔记引用的归档文件和直传文件加入存储删除队列，由 StorageCleanupService 从存储中删除
+func (r *TrashRepository) Purge(scope TrashScope) (notes, bloggers int64, err error) {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if scope.ItemType == "" || scope.ItemType == TrashTypeNote {
+			noteIDs := func() *gorm.DB { return scope.apply(tx.Model(&model.Note{})).Select("id") }
+			if err := tx.Where("note_id IN (?)", noteIDs()).Delete(&model.NoteMetricSnapshot{}).Error; err != nil {
+				return err
+			}
+			if err := deleteNoteTerms(tx, "note_id IN (?)", noteIDs()); err != nil {
+				return err
+			}
+			if err := tx.Where("note_id IN (?)", noteIDs()).Delete(&model.CollectionNote{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("note_id IN (?)", noteIDs()).Delete(&model.NoteEditorial{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("note_id IN (?)", noteIDs()).Delete(&model.NoteStatusChange{}).Error; err != nil {
+				return err
+			}
+			if err := purgeNoteObjects(tx, noteIDs); err != nil {
+				return err
+			}
+			if err := tx.Where("note_id IN (?)", noteIDs()).Delete(&model.NoteMedia{}).Error; err != nil {
+				return err
+			}
+			res := scope.apply(tx).Delete(&model.Note{})
+			if res.Error != nil {
+				return res.Error
+			}
+			notes = res.RowsAffected
+		}
+		if scope.ItemType == "" || scope.ItemType == TrashTypeBlogger {
+			bloggerIDs := scope.apply(tx.Model(&model.Blogger{})).Select("id")
+			if err := tx.Where("blogger_id IN (?)", bloggerIDs).Delete(&model.BloggerMetricSnapshot{}).Error; err != nil {
+				return err
+			}
+			res := scope.apply(tx).Delete(&model.Blogger{})
+			if res.Error != nil {
+				return res.Error
+			}
+			bloggers = res.RowsAffected
+		}
+		return nil
+	})
+	return notes, bloggers, err
+}
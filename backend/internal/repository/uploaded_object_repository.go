@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"github.com/keenchase/edit-business/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UploadedObjectRepository 直传文件记录
+type UploadedObjectRepository struct {
+	db *gorm.DB
+}
+
+// NewUploadedObjectRepository 创建直传文件仓库实例
+func NewUploadedObjectRepository(db *gorm.DB) *UploadedObjectRepository {
+	return &UploadedObjectRepository{db: db}
+}
+
+// Record 记录上传完成的文件；同一用户重复回调同一个 key 时更新文件信息和关联的笔记
+func (r *UploadedObjectRepository) Record(obj *model.UploadedObject) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"note_id", "xhs_note_id", "driver", "url", "size", "content_type"}),
+	}).Create(obj).Error
+}
+
+// GetByKey 获取用户的某个文件记录
+func (r *UploadedObjectRepository) GetByKey(userID, key string) (*model.UploadedObject, error) {
+	var obj model.UploadedObject
+	if err := r.db.Where("user_id = ? AND key = ?", userID, key).First(&obj).Error; err != nil {
+		return nil, err
+	}
+	return &obj, nil
+}
+
+// ListByUser 获取用户上传的文件（按时间倒序）；noteID、xhsNoteID 非空时只返回该笔记的文件，
+// 包括笔记保存前上传、只记录了小红书笔记 ID 的文件
+func (r *UploadedObjectRepository) ListByUser(userID, noteID, xhsNoteID string, limit int) ([]*model.UploadedObject, error) {
+	q := r.db.Where("user_id = ?", userID)
+	switch {
+	case noteID != "" && xhsNoteID != "":
+		q = q.Where("(note_id = ? OR (note_id IS NULL AND xhs_note_id = ?))", noteID, xhsNoteID)
+	case noteID != "":
+		q = q.Where("note_id = ?", noteID)
+	case xhsNoteID != "":
+		q = q.Where("xhs_note_id = ?", xhsNoteID)
+	}
+
+	var objs []*model.UploadedObject
+	err := q.Order("created_at DESC").Limit(limit).Find(&objs).Error
+	return objs, err
+}
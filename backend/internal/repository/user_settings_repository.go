@@ -29,6 +29,7 @@ func (r *UserSettingsRepository) GetByUserID(userID string) (*model.UserSettings
 				CollectionEnabled:    false,
 				CollectionDailyLimit: 500,
 				CollectionBatchLimit: 50,
+				Timezone:             model.DefaultTimezone,
 			}, nil
 		}
 		return nil, err
@@ -69,6 +70,13 @@ func (r *UserSettingsRepository) UpdateCollectionEnabled(userID string, enabled
 		Update("collection_enabled", enabled).Error
 }
 
+// UpdateTimezone 更新用户时区
+func (r *UserSettingsRepository) UpdateTimezone(userID, timezone string) error {
+	return r.db.Model(&model.UserSettings{}).
+		Where("user_id = ?", userID).
+		Update("timezone", timezone).Error
+}
+
 // IncrementDailyCount increments the daily collection count (stored separately)
 // This is called when a note is collected
 func (r *UserSettingsRepository) GetDailyCount(userID string) (int64, error) {
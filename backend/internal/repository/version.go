@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrVersionConflict 记录已被其他请求修改（版本号不匹配）
+var ErrVersionConflict = errors.New("version conflict")
+
+// patchWithVersion 按版本号条件更新（乐观并发控制），成功后版本号加一
+// updates 为列名 -> 新值；没有命中行时返回 ErrVersionConflict
+func patchWithVersion(tx *gorm.DB, model interface{}, userID, id string, version int64, updates map[string]interface{}) error {
+	values := make(map[string]interface{}, len(updates)+1)
+	for column, v := range updates {
+		values[column] = v
+	}
+	values["version"] = gorm.Expr("version + 1")
+
+	res := tx.Model(model).
+		Where("id = ? AND user_id = ? AND version = ?", id, userID, version).
+		Updates(values)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
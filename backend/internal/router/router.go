@@ -19,8 +19,13 @@ func SetupRouter(
 	apiKeyHandler *handler.APIKeyHandler,
 	userSettingsHandler *handler.UserSettingsHandler,
 	adminHandler *handler.AdminHandler,
-	qiniuHandler *handler.QiniuHandler,
+	storageHandler *handler.StorageHandler,
+	importHandler *handler.ImportHandler,
+	trashHandler *handler.TrashHandler,
+	collectionHandler *handler.CollectionHandler,
+	noteEditorialHandler *handler.NoteEditorialHandler,
 	authCenterService *service.AuthCenterService,
+	idempotencyService *service.IdempotencyService,
 	userRepo *repository.UserRepository,
 	adminAuthCenterUserIDs []string,
 ) *gin.Engine {
@@ -37,6 +42,9 @@ func SetupRouter(
 		})
 	})
 
+	// 插件同步接口的幂等处理（Idempotency-Key），需在 API Key 认证之后执行
+	idempotent := middleware.IdempotencyMiddleware(idempotencyService)
+
 	// API v1
 	v1 := router.Group("/api/v1")
 	{
@@ -57,15 +65,24 @@ func SetupRouter(
 		{
 			// 同步接口（支持 JWT 或 API Key 认证）- Chrome 插件使用
 			notes.Use(apiKeyHandler.ValidateAPIKeyMiddleware())
-			notes.POST("", noteHandler.Create)
-			notes.POST("/batch", noteHandler.BatchCreate)
+			notes.POST("", idempotent, noteHandler.Create)
+			notes.POST("/batch", idempotent, noteHandler.BatchCreate)
 
 			// 查询/删除接口（需要认证）
 			notesAuth := notes.Group("")
 			notesAuth.Use(middleware.AuthCenterMiddleware(authCenterService, userRepo))
 			{
 				notesAuth.GET("", noteHandler.List)
+				notesAuth.GET("/search", noteHandler.Search)
+				notesAuth.GET("/export", noteHandler.Export)
+				notesAuth.POST("/import", importHandler.ImportNotes)
+				notesAuth.POST("/bulk", noteHandler.Bulk)
+				notesAuth.GET("/editorial/throughput", noteEditorialHandler.Throughput)
 				notesAuth.GET("/:id", noteHandler.GetByID)
+				notesAuth.GET("/:id/metrics", noteHandler.GetMetrics)
+				notesAuth.GET("/:id/similar-covers", noteHandler.SimilarCovers)
+				notesAuth.GET("/:id/editorial", noteEditorialHandler.Get)
+				notesAuth.PUT("/:id/editorial", noteEditorialHandler.Update)
 				notesAuth.PUT("/:id", noteHandler.Update)
 				notesAuth.DELETE("/:id", noteHandler.Delete)
 			}
@@ -76,22 +93,51 @@ func SetupRouter(
 		{
 			// 同步接口（支持 JWT 或 API Key 认证）- Chrome 插件使用
 			bloggers.Use(apiKeyHandler.ValidateAPIKeyMiddleware())
-			bloggers.POST("", bloggerHandler.Create)
-			bloggers.POST("/batch", bloggerHandler.BatchCreate)
-			bloggers.POST("/upsert", bloggerHandler.UpsertByXhsID)
+			bloggers.POST("", idempotent, bloggerHandler.Create)
+			bloggers.POST("/batch", idempotent, bloggerHandler.BatchCreate)
+			bloggers.POST("/upsert", idempotent, bloggerHandler.UpsertByXhsID)
 
 			// 查询/删除接口（需要认证）
 			bloggersAuth := bloggers.Group("")
 			bloggersAuth.Use(middleware.AuthCenterMiddleware(authCenterService, userRepo))
 			{
 				bloggersAuth.GET("", bloggerHandler.List)
+				bloggersAuth.GET("/export", bloggerHandler.Export)
+				bloggersAuth.POST("/import", importHandler.ImportBloggers)
 				bloggersAuth.GET("/:id", bloggerHandler.GetByID)
+				bloggersAuth.GET("/:id/notes", bloggerHandler.ListNotes)
+				bloggersAuth.GET("/:id/history", bloggerHandler.GetHistory)
 				bloggersAuth.GET("/xhs/:xhsId", bloggerHandler.GetByXhsID)
 				bloggersAuth.PUT("/:id", bloggerHandler.Update)
 				bloggersAuth.DELETE("/:id", bloggerHandler.Delete)
 			}
 		}
 
+		// 收藏夹路由（需要认证）
+		collections := v1.Group("/collections")
+		collections.Use(middleware.AuthCenterMiddleware(authCenterService, userRepo))
+		{
+			collections.GET("", collectionHandler.List)
+			collections.POST("", collectionHandler.Create)
+			collections.GET("/:id", collectionHandler.GetByID)
+			collections.PUT("/:id", collectionHandler.Update)
+			collections.DELETE("/:id", collectionHandler.Delete)
+			collections.GET("/:id/notes", collectionHandler.ListNotes)
+			collections.POST("/:id/notes", collectionHandler.AddNotes)
+			collections.PUT("/:id/notes/order", collectionHandler.Reorder)
+			collections.DELETE("/:id/notes/:noteId", collectionHandler.RemoveNote)
+		}
+
+		// 回收站路由（需要认证）
+		trash := v1.Group("/trash")
+		trash.Use(middleware.AuthCenterMiddleware(authCenterService, userRepo))
+		{
+			trash.GET("", trashHandler.List)
+			trash.DELETE("", trashHandler.Empty)
+			trash.POST("/:id/restore", trashHandler.Restore)
+			trash.DELETE("/:id", trashHandler.Purge)
+		}
+
 		// 用户相关路由（需要认证）
 		users := v1.Group("/users")
 		users.Use(middleware.AuthCenterMiddleware(authCenterService, userRepo))
@@ -109,6 +155,13 @@ func SetupRouter(
 		stats.Use(middleware.AuthCenterMiddleware(authCenterService, userRepo))
 		{
 			stats.GET("", statsHandler.GetStats)
+			stats.GET("/timeseries", statsHandler.GetTimeseries)
+			stats.GET("/keywords", statsHandler.GetKeywords)
+			stats.GET("/hashtags", statsHandler.GetHashtags)
+			stats.GET("/authors", statsHandler.GetAuthorLeaderboard)
+			stats.GET("/tags", statsHandler.GetTagLeaderboard)
+			stats.GET("/tags/pairs", statsHandler.GetTagPairs)
+			stats.GET("/note-types", statsHandler.GetNoteTypeLeaderboard)
 		}
 
 		// API Key管理路由（需要认证）
@@ -136,6 +189,8 @@ func SetupRouter(
 		{
 			userSettings.GET("", userSettingsHandler.GetOrCreate)
 			userSettings.POST("/toggle-collection", userSettingsHandler.ToggleCollectionEnabled)
+			userSettings.GET("/quota", userSettingsHandler.GetQuota)
+			userSettings.POST("/timezone", userSettingsHandler.SetTimezone)
 		}
 
 		// 管理后台：检查是否为管理员（仅认证，不要求管理员）
@@ -154,11 +209,26 @@ func SetupRouter(
 			admin.GET("/stats/overview", adminHandler.GetStatsOverview)
 		}
 
-		// 七牛云相关路由（使用 API Key 认证）
+		// 对象存储直传路由（使用 API Key 认证）
+		storage := v1.Group("/storage")
+		{
+			// 本地存储驱动的上传接口，直传凭证即授权
+			storage.POST("/local/upload", storageHandler.LocalUpload)
+
+			storageAuth := storage.Group("")
+			storageAuth.Use(apiKeyHandler.ValidateAPIKeyMiddleware())
+			{
+				storageAuth.GET("/upload-token", storageHandler.GetUploadToken)
+				storageAuth.POST("/uploads", storageHandler.CompleteUpload)
+				storageAuth.GET("/uploads", storageHandler.ListUploads)
+			}
+		}
+
+		// 七牛云相关路由（使用 API Key 认证，兼容旧版插件）
 		qiniu := v1.Group("/qiniu")
 		qiniu.Use(apiKeyHandler.ValidateAPIKeyMiddleware())
 		{
-			qiniu.GET("/upload-token", qiniuHandler.GetUploadToken)
+			qiniu.GET("/upload-token", storageHandler.GetQiniuUploadToken)
 		}
 	}
 
@@ -199,7 +269,8 @@ func CORSMiddleware() gin.HandlerFunc {
 		}
 
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-User-ID, X-API-Key")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-User-ID, X-API-Key, If-Match, Idempotency-Key")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag, X-Quota-Limit, X-Quota-Remaining, X-Quota-Reset, X-Quota-Batch-Limit, Idempotent-Replayed")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
 
 		if c.Request.Method == "OPTIONS" {
@@ -0,0 +1,179 @@
+// Package search 笔记全文检索的分词与高亮
+// 不依赖 PostgreSQL 中文分词扩展：中文按单字 + 二元组（bigram）切分，
+// 英文/数字按连续单词切分并转小写，索引由 NoteRepository 维护
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// maxTermRunes 单个词项最大长度，超长的英文/数字串会被截断
+const maxTermRunes = 32
+
+// isCJK 判断是否为中日韩文字（按字切分）
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r)
+}
+
+// isWord 判断是否为英文/数字等按单词切分的字符
+func isWord(r rune) bool {
+	return (unicode.IsLetter(r) || unicode.IsDigit(r)) && !isCJK(r)
+}
+
+// tokenize 切分文本，withUnigrams 控制中文是否同时输出单字
+func tokenize(text string, withUnigrams bool) []string {
+	var terms []string
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case isCJK(r):
+			j := i
+			for j < len(runes) && isCJK(runes[j]) {
+				j++
+			}
+			terms = append(terms, cjkTerms(runes[i:j], withUnigrams)...)
+			i = j
+		case isWord(r):
+			j := i
+			for j < len(runes) && isWord(runes[j]) {
+				j++
+			}
+			word := runes[i:j]
+			if len(word) > maxTermRunes {
+				word = word[:maxTermRunes]
+			}
+			terms = append(terms, strings.ToLower(string(word)))
+			i = j
+		default:
+			i++
+		}
+	}
+	return terms
+}
+
+// cjkTerms 中文连续片段：二元组，单字片段输出单字
+func cjkTerms(run []rune, withUnigrams bool) []string {
+	if len(run) == 1 {
+		return []string{string(run)}
+	}
+	var terms []string
+	if withUnigrams {
+		for _, r := range run {
+			terms = append(terms, string(r))
+		}
+	}
+	for k := 0; k+1 < len(run); k++ {
+		terms = append(terms, string(run[k:k+2]))
+	}
+	return terms
+}
+
+// IndexTerms 生成索引词项及词频（中文同时索引单字和二元组，以支持单字查询）
+func IndexTerms(text string) map[string]int {
+	counts := make(map[string]int)
+	for _, t := range tokenize(text, true) {
+		counts[t]++
+	}
+	return counts
+}
+
+// Tokens 切分文本（中文二元组，单字片段输出单字），保留重复和出现顺序
+func Tokens(text string) []string {
+	return tokenize(text, false)
+}
+
+// QueryTerms 生成查询词项（去重，保持出现顺序）
+// 中文只用二元组匹配，单字查询时退化为单字
+func QueryTerms(q string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, t := range tokenize(q, false) {
+		if !seen[t] {
+			seen[t] = true
+			terms = append(terms, t)
+		}
+	}
+	return terms
+}
+
+// Highlight 截取包含命中词的片段，命中部分用 <em></em> 包裹，其余内容做 HTML 转义
+// maxRunes <= 0 时返回全文高亮
+func Highlight(text string, terms []string, maxRunes int) string {
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	// 标记所有命中位置
+	marked := make([]bool, len(runes))
+	first := -1
+	for _, term := range terms {
+		tr := []rune(term)
+		if len(tr) == 0 {
+			continue
+		}
+		for i := 0; i+len(tr) <= len(lower); i++ {
+			if runesEqual(lower[i:i+len(tr)], tr) {
+				for k := i; k < i+len(tr); k++ {
+					marked[k] = true
+				}
+				if first == -1 || i < first {
+					first = i
+				}
+			}
+		}
+	}
+
+	start, end := 0, len(runes)
+	if maxRunes > 0 && len(runes) > maxRunes {
+		if first > maxRunes/4 {
+			start = first - maxRunes/4
+		}
+		end = start + maxRunes
+		if end > len(runes) {
+			end = len(runes)
+			start = end - maxRunes
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	for i := start; i < end; {
+		j := i
+		for j < end && marked[j] == marked[i] {
+			j++
+		}
+		segment := html.EscapeString(string(runes[i:j]))
+		if marked[i] {
+			b.WriteString("<em>")
+			b.WriteString(segment)
+			b.WriteString("</em>")
+		} else {
+			b.WriteString(segment)
+		}
+		i = j
+	}
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+func runesEqual(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"chinese bigrams", "护肤品", []string{"护肤", "肤品"}},
+		{"single han", "我", []string{"我"}},
+		{"english lowercased", "Hello World", []string{"hello", "world"}},
+		{"mixed scripts", "夏天OOTD分享2024", []string{"夏天", "ootd", "分享", "2024"}},
+		{"punctuation splits runs", "美食，探店！", []string{"美食", "探店"}},
+		{"hashtag", "#旅行日记#", []string{"旅行", "行日", "日记"}},
+		{"japanese kana", "かわいい", []string{"かわ", "わい", "いい"}},
+		{"long word truncated", strings.Repeat("a", 40), []string{strings.Repeat("a", maxTermRunes)}},
+		{"keeps duplicates", "ok ok", []string{"ok", "ok"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tokens(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokens(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndexTerms(t *testing.T) {
+	got := IndexTerms("口红 口红试色")
+	want := map[string]int{
+		"口": 2, "红": 2, "口红": 2,
+		"试": 1, "色": 1, "红试": 1, "试色": 1,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("IndexTerms = %v, want %v", got, want)
+	}
+}
+
+func TestQueryTerms(t *testing.T) {
+	tests := []struct {
+		q    string
+		want []string
+	}{
+		{"口红试色", []string{"口红", "红试", "试色"}},
+		{"红", []string{"红"}},
+		{"Vlog vlog 日常", []string{"vlog", "日常"}},
+		{"  ！！ ", nil},
+	}
+	for _, tt := range tests {
+		if got := QueryTerms(tt.q); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("QueryTerms(%q) = %q, want %q", tt.q, got, tt.want)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		terms    []string
+		maxRunes int
+		want     string
+	}{
+		{"marks matches", "今天的口红试色", []string{"口红"}, 0, "今天的<em>口红</em>试色"},
+		{"case insensitive", "My OOTD today", []string{"ootd"}, 0, "My <em>OOTD</em> today"},
+		{"merges overlapping terms", "口红试色", []string{"口红", "红试"}, 0, "<em>口红试</em>色"},
+		{"escapes html", "<b>口红</b>", []string{"口红"}, 0, "&lt;b&gt;<em>口红</em>&lt;/b&gt;"},
+		{"no match", "abc", []string{"x"}, 0, "abc"},
+		{"snippet around match", "0123456789口红0123456789", []string{"口红"}, 8, "…89<em>口红</em>0123…"},
+		{"snippet at end", "0123456789口红", []string{"口红"}, 4, "…89<em>口红</em>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Highlight(tt.text, tt.terms, tt.maxRunes); got != tt.want {
+				t.Errorf("Highlight = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
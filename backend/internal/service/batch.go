@@ -0,0 +1,157 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/keenchase/edit-business/internal/repository"
+)
+
+// 批量写入模式
+const (
+	BatchModeAtomic     = "atomic"     // 全部成功才提交，任一条被拒绝或写入失败整批回滚
+	BatchModeBestEffort = "bestEffort" // 逐条提交，被拒绝的条目不影响其他条目
+)
+
+// 批量写入中单条记录的状态
+const (
+	BatchItemCreated  = repository.UpsertCreated
+	BatchItemUpdated  = repository.UpsertUpdated
+	BatchItemSkipped  = repository.UpsertSkipped // 已有完整记录，未覆盖
+	BatchItemRejected = "rejected"               // 校验不通过或写入失败，见 reason
+	BatchItemAborted  = "aborted"                // 原子模式下因其他条目失败而未写入
+)
+
+// ErrInvalidBatchMode 批量写入模式不合法
+var ErrInvalidBatchMode = errors.New("invalid batch mode, expected atomic or bestEffort")
+
+// BatchItemResult 批量写入中单条记录的结果，index 为请求数组中的下标
+type BatchItemResult struct {
+	Index  int    `json:"index"`
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// BatchResponse 批量写入响应
+// committed 为 false 表示原子模式下整批已回滚，没有任何记录被写入
+type BatchResponse struct {
+	Mode      string             `json:"mode"`
+	Committed bool               `json:"committed"`
+	Count     int                `json:"count"` // 已写入（新建、更新或已是完整记录）的条数
+	Results   []*BatchItemResult `json:"results"`
+	Summary   map[string]int     `json:"summary"`
+}
+
+// normalizeBatchMode 校验批量写入模式，空值为 bestEffort
+func normalizeBatchMode(mode string) (string, error) {
+	switch mode {
+	case "":
+		return BatchModeBestEffort, nil
+	case BatchModeAtomic, BatchModeBestEffort:
+		return mode, nil
+	}
+	return "", ErrInvalidBatchMode
+}
+
+// batchRun 一次批量写入的结果汇总：先记录校验拒绝的条目，再记录写入结果
+type batchRun struct {
+	mode    string
+	results []*BatchItemResult
+	pending []int // 通过校验、待写入的条目下标
+}
+
+func newBatchRun(mode string, n int) *batchRun {
+	results := make([]*BatchItemResult, n)
+	for i := range results {
+		results[i] = &BatchItemResult{Index: i}
+	}
+	return &batchRun{mode: mode, results: results}
+}
+
+// reject 标记校验不通过的条目
+func (b *batchRun) reject(i int, reason string) {
+	b.results[i].Status = BatchItemRejected
+	b.results[i].Reason = reason
+}
+
+// accept 标记通过校验、待写入的条目
+func (b *batchRun) accept(i int) {
+	b.pending = append(b.pending, i)
+}
+
+// limit 只保留 n 条新增的待写入条目，超出的新增条目标记为拒绝
+// existing 与 pending 一一对应，为 true 的条目更新已有记录、不占用额度；nil 表示全部为新增
+func (b *batchRun) limit(n int, existing []bool, reason string) {
+	kept := b.pending[:0]
+	for j, i := range b.pending {
+		if existing == nil || !existing[j] {
+			if n <= 0 {
+				b.reject(i, reason)
+				continue
+			}
+			n--
+		}
+		kept = append(kept, i)
+	}
+	b.pending = kept
+}
+
+// shouldWrite 原子模式下有条目被拒绝时整批不写入
+func (b *batchRun) shouldWrite() bool {
+	if len(b.pending) == 0 {
+		return false
+	}
+	return b.mode != BatchModeAtomic || len(b.pending) == len(b.results)
+}
+
+// apply 记录写入结果；err 为仓库层返回的错误，*repository.BatchItemError 表示原子模式整批回滚
+func (b *batchRun) apply(saved []*repository.BatchResult, err error) error {
+	var itemErr *repository.BatchItemError
+	if errors.As(err, &itemErr) {
+		b.reject(b.pending[itemErr.Index], itemErr.Err.Error())
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for j, res := range saved {
+		r := b.results[b.pending[j]]
+		if res.Err != nil {
+			r.Status = BatchItemRejected
+			r.Reason = res.Err.Error()
+			continue
+		}
+		r.ID = res.ID
+		r.Status = res.Outcome
+	}
+	return nil
+}
+
+// response 生成响应：原子模式下只要有条目被拒绝，其余条目均标记为 aborted
+func (b *batchRun) response() *BatchResponse {
+	resp := &BatchResponse{
+		Mode:      b.mode,
+		Committed: true,
+		Results:   b.results,
+		Summary:   make(map[string]int),
+	}
+	if b.mode == BatchModeAtomic {
+		for _, r := range b.results {
+			if r.Status == BatchItemRejected {
+				resp.Committed = false
+				break
+			}
+		}
+	}
+	for _, r := range b.results {
+		if !resp.Committed && r.Status != BatchItemRejected {
+			r.ID = ""
+			r.Status = BatchItemAborted
+		}
+		resp.Summary[r.Status]++
+		if resp.Committed && r.Status != BatchItemRejected {
+			resp.Count++
+		}
+	}
+	return resp
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/keenchase/edit-business/internal/repository"
+)
+
+func TestNormalizeBatchMode(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"", BatchModeBestEffort, false},
+		{"atomic", BatchModeAtomic, false},
+		{"bestEffort", BatchModeBestEffort, false},
+		{"Atomic", "", true},
+		{"all", "", true},
+	}
+	for _, tt := range tests {
+		got, err := normalizeBatchMode(tt.in)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidBatchMode) {
+				t.Errorf("normalizeBatchMode(%q) err = %v", tt.in, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("normalizeBatchMode(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+// item 期望的单条结果（status, id, reason）
+type item struct{ status, id, reason string }
+
+func TestBatchRun(t *testing.T) {
+	saved := func(results ...*repository.BatchResult) []*repository.BatchResult { return results }
+	ok := func(id, outcome string) *repository.BatchResult {
+		return &repository.BatchResult{ID: id, Outcome: outcome}
+	}
+
+	tests := []struct {
+		name   string
+		mode   string
+		n      int
+		reject map[int]string
+		limit  int    // 0 表示不限
+		exists []bool // 与通过校验的条目一一对应；非 nil 时按 limit 只限制新增条目（limit 可为 0）
+		write  bool
+		saved  []*repository.BatchResult
+		err    error
+
+		wantCommitted bool
+		wantCount     int
+		want          []item
+		wantSummary   map[string]int
+	}{
+		{
+			name:   "best effort mixes outcomes",
+			mode:   BatchModeBestEffort,
+			n:      4,
+			reject: map[int]string{1: "url is required"},
+			write:  true,
+			saved: saved(ok("a", BatchItemCreated), ok("c", BatchItemUpdated),
+				&repository.BatchResult{Err: errors.New("duplicate")}),
+			wantCommitted: true,
+			wantCount:     2,
+			want: []item{
+				{BatchItemCreated, "a", ""},
+				{BatchItemRejected, "", "url is required"},
+				{BatchItemUpdated, "c", ""},
+				{BatchItemRejected, "", "duplicate"},
+			},
+			wantSummary: map[string]int{BatchItemCreated: 1, BatchItemUpdated: 1, BatchItemRejected: 2},
+		},
+		{
+			name:          "best effort all rejected writes nothing",
+			mode:          BatchModeBestEffort,
+			n:             2,
+			reject:        map[int]string{0: "bad", 1: "bad"},
+			write:         false,
+			wantCommitted: true,
+			want:          []item{{BatchItemRejected, "", "bad"}, {BatchItemRejected, "", "bad"}},
+			wantSummary:   map[string]int{BatchItemRejected: 2},
+		},
+		{
+			name:          "atomic validation failure aborts the rest",
+			mode:          BatchModeAtomic,
+			n:             3,
+			reject:        map[int]string{2: "bad"},
+			write:         false,
+			wantCommitted: false,
+			want:          []item{{BatchItemAborted, "", ""}, {BatchItemAborted, "", ""}, {BatchItemRejected, "", "bad"}},
+			wantSummary:   map[string]int{BatchItemAborted: 2, BatchItemRejected: 1},
+		},
+		{
+			name:          "atomic write failure rolls back",
+			mode:          BatchModeAtomic,
+			n:             3,
+			write:         true,
+			err:           &repository.BatchItemError{Index: 1, Err: errors.New("constraint")},
+			wantCommitted: false,
+			want:          []item{{BatchItemAborted, "", ""}, {BatchItemRejected, "", "constraint"}, {BatchItemAborted, "", ""}},
+			wantSummary:   map[string]int{BatchItemAborted: 2, BatchItemRejected: 1},
+		},
+		{
+			name:          "atomic success",
+			mode:          BatchModeAtomic,
+			n:             2,
+			write:         true,
+			saved:         saved(ok("a", BatchItemCreated), ok("b", BatchItemSkipped)),
+			wantCommitted: true,
+			wantCount:     2,
+			want:          []item{{BatchItemCreated, "a", ""}, {BatchItemSkipped, "b", ""}},
+			wantSummary:   map[string]int{BatchItemCreated: 1, BatchItemSkipped: 1},
+		},
+		{
+			name:          "limit rejects overflow",
+			mode:          BatchModeBestEffort,
+			n:             3,
+			limit:         1,
+			write:         true,
+			saved:         saved(ok("a", BatchItemCreated)),
+			wantCommitted: true,
+			wantCount:     1,
+			want:          []item{{BatchItemCreated, "a", ""}, {BatchItemRejected, "", "quota"}, {BatchItemRejected, "", "quota"}},
+			wantSummary:   map[string]int{BatchItemCreated: 1, BatchItemRejected: 2},
+		},
+		{
+			name:          "limit in atomic mode blocks the write",
+			mode:          BatchModeAtomic,
+			n:             2,
+			limit:         1,
+			write:         false,
+			wantCommitted: false,
+			want:          []item{{BatchItemAborted, "", ""}, {BatchItemRejected, "", "quota"}},
+			wantSummary:   map[string]int{BatchItemAborted: 1, BatchItemRejected: 1},
+		},
+		{
+			name:          "updates at the daily limit still succeed",
+			mode:          BatchModeBestEffort,
+			n:             3,
+			exists:        []bool{true, false, true},
+			write:         true,
+			saved:         saved(ok("a", BatchItemUpdated), ok("c", BatchItemUpdated)),
+			wantCommitted: true,
+			wantCount:     2,
+			want:          []item{{BatchItemUpdated, "a", ""}, {BatchItemRejected, "", "quota"}, {BatchItemUpdated, "c", ""}},
+			wantSummary:   map[string]int{BatchItemUpdated: 2, BatchItemRejected: 1},
+		},
+		{
+			name:          "only new items use the remaining quota",
+			mode:          BatchModeBestEffort,
+			n:             4,
+			limit:         1,
+			exists:        []bool{false, true, false, true},
+			write:         true,
+			saved:         saved(ok("a", BatchItemCreated), ok("b", BatchItemUpdated), ok("d", BatchItemUpdated)),
+			wantCommitted: true,
+			wantCount:     3,
+			want: []item{
+				{BatchItemCreated, "a", ""},
+				{BatchItemUpdated, "b", ""},
+				{BatchItemRejected, "", "quota"},
+				{BatchItemUpdated, "d", ""},
+			},
+			wantSummary: map[string]int{BatchItemCreated: 1, BatchItemUpdated: 2, BatchItemRejected: 1},
+		},
+		{
+			name:          "atomic updates at the daily limit commit",
+			mode:          BatchModeAtomic,
+			n:             2,
+			exists:        []bool{true, true},
+			write:         true,
+			saved:         saved(ok("a", BatchItemUpdated), ok("b", BatchItemSkipped)),
+			wantCommitted: true,
+			wantCount:     2,
+			want:          []item{{BatchItemUpdated, "a", ""}, {BatchItemSkipped, "b", ""}},
+			wantSummary:   map[string]int{BatchItemUpdated: 1, BatchItemSkipped: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBatchRun(tt.mode, tt.n)
+			for i := 0; i < tt.n; i++ {
+				if reason, ok := tt.reject[i]; ok {
+					b.reject(i, reason)
+				} else {
+					b.accept(i)
+				}
+			}
+			if tt.limit > 0 || tt.exists != nil {
+				b.limit(tt.limit, tt.exists, "quota")
+			}
+			if got := b.shouldWrite(); got != tt.write {
+				t.Fatalf("shouldWrite = %v, want %v", got, tt.write)
+			}
+			if tt.write {
+				if err := b.apply(tt.saved, tt.err); err != nil {
+					t.Fatalf("apply: %v", err)
+				}
+			}
+
+			resp := b.response()
+			if resp.Mode != tt.mode || resp.Committed != tt.wantCommitted || resp.Count != tt.wantCount {
+				t.Errorf("mode/committed/count = %s/%v/%d, want %s/%v/%d",
+					resp.Mode, resp.Committed, resp.Count, tt.mode, tt.wantCommitted, tt.wantCount)
+			}
+			got := make([]item, len(resp.Results))
+			for i, r := range resp.Results {
+				if r.Index != i {
+					t.Errorf("results[%d].Index = %d", i, r.Index)
+				}
+				got[i] = item{r.Status, r.ID, r.Reason}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("results = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(resp.Summary, tt.wantSummary) {
+				t.Errorf("summary = %v, want %v", resp.Summary, tt.wantSummary)
+			}
+		})
+	}
+}
+
+func TestBatchRunApplyPassesThroughErrors(t *testing.T) {
+	b := newBatchRun(BatchModeBestEffort, 1)
+	b.accept(0)
+	dbErr := errors.New("connection reset")
+	if err := b.apply(nil, dbErr); err != dbErr {
+		t.Errorf("apply err = %v, want %v", err, dbErr)
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"time"
+
+	"github.com/keenchase/edit-business/internal/model"
+	"github.com/keenchase/edit-business/internal/repository"
+)
+
+// 博主粉丝趋势
+const (
+	TrendGrowing   = "growing"
+	TrendStagnant  = "stagnant"
+	TrendDeclining = "declining"
+	TrendUnknown   = "unknown" // 快照不足或跨度太短
+)
+
+const (
+	// trendMinSpan 判断趋势所需的最短快照跨度
+	trendMinSpan = 7 * 24 * time.Hour
+	// trendMonthlyRate 折算为 30 天的粉丝增长率超过该比例视为增长，低于其相反数视为下滑
+	trendMonthlyRate = 0.01
+)
+
+// BloggerHistoryResponse 博主粉丝数据历史响应
+type BloggerHistoryResponse struct {
+	BloggerID string                         `json:"bloggerId"`
+	Snapshots []*model.BloggerMetricSnapshot `json:"snapshots"`
+	Growth    *repository.BloggerGrowth      `json:"growth"`
+	Trend     string                         `json:"trend"` // growing / stagnant / declining / unknown，按最近 30 天的粉丝变化判断
+}
+
+// GetHistory 获取博主每次采集时的粉丝数据快照（校验归属）
+func (s *BloggerService) GetHistory(authCenterUserID, id string, from, to time.Time) (*BloggerHistoryResponse, error) {
+	blogger, err := s.GetByID(authCenterUserID, id)
+	if err != nil {
+		return nil, ErrBloggerNotFound
+	}
+
+	snapshots, err := s.metricRepo.ListByBloggerID(blogger.UserID, blogger.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	growth, err := s.metricRepo.GetGrowth(blogger.UserID, []string{blogger.ID}, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BloggerHistoryResponse{
+		BloggerID: blogger.ID,
+		Snapshots: snapshots,
+		Growth:    growth[blogger.ID],
+		Trend:     followerTrend(growth[blogger.ID], now),
+	}, nil
+}
+
+// followerTrend 根据 30 天窗口的粉丝增量判断趋势，按基准快照到现在的实际跨度折算为 30 天的增长率
+func followerTrend(growth *repository.BloggerGrowth, now time.Time) string {
+	if growth == nil || growth.Delta30d == nil {
+		return TrendUnknown
+	}
+	delta := growth.Delta30d
+	span := now.Sub(delta.Since)
+	if span < trendMinSpan {
+		return TrendUnknown
+	}
+
+	rate := delta.FollowersRate
+	if rate == 0 && delta.Followers != 0 {
+		// 基准粉丝数为 0 时无法计算增长率，只看方向
+		rate = float64(delta.Followers)
+	}
+	monthly := rate * float64(30*24*time.Hour) / float64(span)
+	switch {
+	case monthly >= trendMonthlyRate:
+		return TrendGrowing
+	case monthly <= -trendMonthlyRate:
+		return TrendDeclining
+	default:
+		return TrendStagnant
+	}
+}
@@ -1,10 +1,15 @@
 package service
 
 import (
+	"encoding/json"
 	"errors"
+	"io"
 
+	"github.com/keenchase/edit-business/internal/export"
 	"github.com/keenchase/edit-business/internal/model"
 	"github.com/keenchase/edit-business/internal/repository"
+
+	"gorm.io/gorm"
 )
 
 var ErrBloggerNotFound = errors.New("blogger not found")
@@ -12,77 +17,82 @@ var ErrBloggerNotFound = errors.New("blogger not found")
 // BloggerService 博主服务
 type BloggerService struct {
 	bloggerRepo      *repository.BloggerRepository
+	metricRepo       *repository.BloggerMetricRepository
 	settingsService  *UserSettingsService
+	noteRepo         *repository.NoteRepository
 }
 
 // NewBloggerService 创建博主服务实例
-func NewBloggerService(bloggerRepo *repository.BloggerRepository, settingsService *UserSettingsService) *BloggerService {
+func NewBloggerService(bloggerRepo *repository.BloggerRepository, metricRepo *repository.BloggerMetricRepository, settingsService *UserSettingsService, noteRepo *repository.NoteRepository) *BloggerService {
 	return &BloggerService{
 		bloggerRepo:     bloggerRepo,
+		metricRepo:      metricRepo,
 		settingsService: settingsService,
+		noteRepo:        noteRepo,
 	}
 }
 
 // CreateBloggerRequest 创建博主请求
 type CreateBloggerRequest struct {
-	XhsID            string `json:"xhsId" binding:"required"`
-	BloggerName      string `json:"bloggerName"`
-	AvatarURL        string `json:"avatarUrl"`
-	Description      string `json:"description"`
-	FollowersCount   int32  `json:"followersCount"`
-	BloggerURL       string `json:"bloggerUrl"`
-	CaptureTimestamp int64  `json:"captureTimestamp" binding:"required"`
+	XhsID              string `json:"xhsId" binding:"required"`
+	BloggerName        string `json:"bloggerName"`
+	AvatarURL          string `json:"avatarUrl"`
+	Description        string `json:"description"`
+	FollowersCount     int32  `json:"followersCount"`
+	FollowingCount     int32  `json:"followingCount"`
+	LikesCollectsCount int32  `json:"likesCollectsCount"` // 获赞与收藏
+	IPLocation         string `json:"ipLocation"`
+	Verified           bool   `json:"verified"`
+	VerifiedLabel      string `json:"verifiedLabel"`
+	Category           string `json:"category"`
+	BloggerURL         string `json:"bloggerUrl"`
+	CaptureTimestamp   int64  `json:"captureTimestamp" binding:"required"`
 }
 
 // ListBloggersRequest 列表查询请求
 type ListBloggersRequest struct {
-	Page int `form:"page" binding:"min=1"`
-	Size int `form:"size" binding:"min=1,max=100"`
+	Page  int    `form:"page" binding:"min=1"`
+	Size  int    `form:"size" binding:"min=1,max=100"`
+	Sort  string `form:"sort"`  // 排序字段：followersCount / followingCount / likesCollectsCount / captureTimestamp / createdAt / updatedAt
+	Order string `form:"order"` // asc / desc，默认 desc
+	repository.BloggerFilter
+
+	// 游标分页：UseCursor 为 true 时忽略 Page，按 Cursor 继续翻页（空字符串表示第一页）
+	UseCursor bool   `form:"-"`
+	Cursor    string `form:"cursor"`
+	WithTotal bool   `form:"withTotal"` // 游标模式下是否统计总数
 }
 
 // ListBloggersResponse 列表查询响应
+// 游标模式下 total 仅在 withTotal=true 时返回，否则为 null
 type ListBloggersResponse struct {
 	Bloggers   []*model.Blogger `json:"bloggers"`
-	Total      int64            `json:"total"`
+	Total      *int64           `json:"total"`
 	Page       int              `json:"page"`
 	Size       int              `json:"size"`
 	TotalPages int              `json:"totalPages"`
+	NextCursor string           `json:"nextCursor,omitempty"`
+	HasMore    bool             `json:"hasMore"`
 }
 
-// Create 创建博主信息
-func (s *BloggerService) Create(authCenterUserID string, req *CreateBloggerRequest) (*model.Blogger, error) {
-	// Check if collection is enabled
-	enabled, err := s.settingsService.IsCollectionEnabled(authCenterUserID)
-	if err != nil {
-		return nil, err
-	}
-	if !enabled {
-		return nil, ErrCollectionDisabled
-	}
-
-	// Get user
-	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
-	if err != nil {
-		return nil, err
-	}
-
-	blogger := &model.Blogger{
-		UserID:           user.ID,
-		XhsID:            req.XhsID,
-		BloggerName:      req.BloggerName,
-		AvatarURL:        req.AvatarURL,
-		Description:      req.Description,
-		FollowersCount:   req.FollowersCount,
-		BloggerURL:       req.BloggerURL,
-		CaptureTimestamp: req.CaptureTimestamp,
-	}
+// CreateBloggerResponse 采集博主响应：博主字段 + 回收站中的同 xhs_id 博主（可提示用户恢复）
+type CreateBloggerResponse struct {
+	*model.Blogger
+	TrashedMatch *TrashedMatch `json:"trashedMatch,omitempty"`
+}
 
-	err = s.bloggerRepo.Create(blogger)
-	if err != nil {
-		return nil, err
+// withTrashedMatch 附加回收站中相同 xhs_id 的博主
+func (s *BloggerService) withTrashedMatch(blogger *model.Blogger) *CreateBloggerResponse {
+	resp := &CreateBloggerResponse{Blogger: blogger}
+	if trashed, err := s.bloggerRepo.GetTrashedByXhsID(blogger.UserID, blogger.XhsID); err == nil {
+		resp.TrashedMatch = newTrashedMatch(trashed.ID, trashed.DeletedAt)
 	}
+	return resp
+}
 
-	return blogger, nil
+// Create 采集博主信息；同一用户已采集过该 xhs_id 时更新原记录（与 UpsertByXhsID 相同）
+func (s *BloggerService) Create(authCenterUserID string, req *CreateBloggerRequest) (*CreateBloggerResponse, error) {
+	return s.UpsertByXhsID(authCenterUserID, req)
 }
 
 // GetByID 根据 ID 获取博主信息（校验归属）
@@ -103,6 +113,76 @@ func (s *BloggerService) GetByXhsID(authCenterUserID, xhsID string) (*model.Blog
 	return s.bloggerRepo.GetByUserIDAndXhsID(user.ID, xhsID)
 }
 
+// ListBloggerNotesRequest 博主笔记列表请求
+type ListBloggerNotesRequest struct {
+	Page  int    `form:"page"`
+	Size  int    `form:"size"`
+	Sort  string `form:"sort"`  // 同笔记列表：likes / collects / comments / publishDate / captureTimestamp / createdAt / updatedAt
+	Order string `form:"order"` // asc / desc，默认 desc
+}
+
+// BloggerNotesResponse 博主已采集的笔记 + 汇总数据
+type BloggerNotesResponse struct {
+	Blogger    *model.Blogger          `json:"blogger"`
+	Stats      *repository.AuthorStats `json:"stats"`
+	Notes      []*model.Note           `json:"notes"`
+	Total      int64                   `json:"total"`
+	Page       int                     `json:"page"`
+	Size       int                     `json:"size"`
+	TotalPages int                     `json:"totalPages"`
+}
+
+// ListNotes 获取博主已采集的笔记及汇总数据（校验归属，按笔记的 author_xhs_id 关联）
+func (s *BloggerService) ListNotes(authCenterUserID, id string, req *ListBloggerNotesRequest) (*BloggerNotesResponse, error) {
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+	blogger, err := s.bloggerRepo.GetByID(user.ID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBloggerNotFound
+		}
+		return nil, err
+	}
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Size < 1 || req.Size > 100 {
+		req.Size = 20
+	}
+	sort, err := repository.ParseNoteSort(req.Sort, req.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &repository.NoteFilter{AuthorXhsID: blogger.XhsID}
+	notes, total, err := s.noteRepo.List(user.ID, filter, sort, (req.Page-1)*req.Size, req.Size)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.noteRepo.GetAuthorStats(user.ID, blogger.XhsID)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / req.Size
+	if int(total)%req.Size > 0 {
+		totalPages++
+	}
+
+	return &BloggerNotesResponse{
+		Blogger:    blogger,
+		Stats:      stats,
+		Notes:      notes,
+		Total:      total,
+		Page:       req.Page,
+		Size:       req.Size,
+		TotalPages: totalPages,
+	}, nil
+}
+
 // List 获取博主列表（按用户隔离）
 func (s *BloggerService) List(authCenterUserID string, req *ListBloggersRequest) (*ListBloggersResponse, error) {
 	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
@@ -117,9 +197,18 @@ func (s *BloggerService) List(authCenterUserID string, req *ListBloggersRequest)
 		req.Size = 20
 	}
 
+	sort, err := repository.ParseBloggerSort(req.Sort, req.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.UseCursor {
+		return s.listByCursor(user.ID, req, sort)
+	}
+
 	offset := (req.Page - 1) * req.Size
 
-	bloggers, total, err := s.bloggerRepo.List(user.ID, offset, req.Size)
+	bloggers, total, err := s.bloggerRepo.List(user.ID, &req.BloggerFilter, sort, offset, req.Size)
 	if err != nil {
 		return nil, err
 	}
@@ -131,22 +220,67 @@ func (s *BloggerService) List(authCenterUserID string, req *ListBloggersRequest)
 
 	return &ListBloggersResponse{
 		Bloggers:   bloggers,
-		Total:      total,
+		Total:      &total,
 		Page:       req.Page,
 		Size:       req.Size,
 		TotalPages: totalPages,
+		HasMore:    req.Page < totalPages,
 	}, nil
 }
 
-// UpsertByXhsID 根据 xhs_id 插入或更新博主信息
-func (s *BloggerService) UpsertByXhsID(authCenterUserID string, req *CreateBloggerRequest) (*model.Blogger, error) {
-	// Check if collection is enabled
-	enabled, err := s.settingsService.IsCollectionEnabled(authCenterUserID)
+// listByCursor 游标分页（按排序字段 + id 定位）
+func (s *BloggerService) listByCursor(userID string, req *ListBloggersRequest, sort repository.Sort) (*ListBloggersResponse, error) {
+	bloggers, nextCursor, err := s.bloggerRepo.ListByCursor(userID, &req.BloggerFilter, sort, req.Cursor, req.Size)
 	if err != nil {
 		return nil, err
 	}
-	if !enabled {
-		return nil, ErrCollectionDisabled
+
+	resp := &ListBloggersResponse{
+		Bloggers:   bloggers,
+		Size:       req.Size,
+		NextCursor: nextCursor,
+		HasMore:    nextCursor != "",
+	}
+	if req.WithTotal {
+		total, err := s.bloggerRepo.Count(userID, &req.BloggerFilter)
+		if err != nil {
+			return nil, err
+		}
+		resp.Total = &total
+	}
+	return resp, nil
+}
+
+// ExportBloggers 导出博主（按用户隔离，筛选和排序与列表接口一致）
+// 先校验参数并返回写出函数，调用方设置好响应头后再执行，数据边读边写
+func (s *BloggerService) ExportBloggers(authCenterUserID string, req *ListBloggersRequest, format export.Format) (func(w io.Writer) error, error) {
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+	sort, err := repository.ParseBloggerSort(req.Sort, req.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(w io.Writer) error {
+		writer, err := export.NewBloggerWriter(format, w)
+		if err != nil {
+			return err
+		}
+		if err := s.bloggerRepo.Stream(user.ID, &req.BloggerFilter, sort, writer.Write); err != nil {
+			return err
+		}
+		return writer.Close()
+	}, nil
+}
+
+// UpsertByXhsID 根据 xhs_id 插入或更新博主信息（回收站中的博主不参与匹配）
+func (s *BloggerService) UpsertByXhsID(authCenterUserID string, req *CreateBloggerRequest) (*CreateBloggerResponse, error) {
+	// 检查采集开关和每日上限
+	usage, err := s.settingsService.CheckCollectionLimits(authCenterUserID, QuotaBloggers, 1)
+	if err != nil {
+		return nil, err
 	}
 
 	// Get user
@@ -155,74 +289,163 @@ func (s *BloggerService) UpsertByXhsID(authCenterUserID string, req *CreateBlogg
 		return nil, err
 	}
 
-	blogger := &model.Blogger{
-		UserID:           user.ID,
-		XhsID:            req.XhsID,
-		BloggerName:      req.BloggerName,
-		AvatarURL:        req.AvatarURL,
-		Description:      req.Description,
-		FollowersCount:   req.FollowersCount,
-		BloggerURL:       req.BloggerURL,
-		CaptureTimestamp: req.CaptureTimestamp,
+	// 每日额度只计新增的博主，额度用完后仍可更新已有博主
+	if usage.Remaining < 1 {
+		existing, err := s.bloggerRepo.MatchExisting(user.ID, []string{req.XhsID})
+		if err != nil {
+			return nil, err
+		}
+		if !existing[0] {
+			return nil, usage.exceeded()
+		}
 	}
 
+	blogger := newBloggerFromRequest(user.ID, req)
+
 	err = s.bloggerRepo.UpsertByXhsID(blogger)
 	if err != nil {
 		return nil, err
 	}
 
-	return blogger, nil
+	return s.withTrashedMatch(blogger), nil
 }
 
-// BatchCreate 批量创建博主信息（用于 Chrome 插件同步）
-func (s *BloggerService) BatchCreate(authCenterUserID string, reqs []*CreateBloggerRequest) error {
+// Quota 获取用户当前的采集额度（用于在采集接口的响应头中返回）
+func (s *BloggerService) Quota(authCenterUserID string) (*QuotaResponse, error) {
+	return s.settingsService.GetQuota(authCenterUserID)
+}
+
+// BatchCreate 批量按 xhs_id 创建或更新博主信息（用于 Chrome 插件同步），返回每条的写入结果
+// mode 语义同 NoteService.BatchCreate
+func (s *BloggerService) BatchCreate(authCenterUserID string, reqs []*CreateBloggerRequest, mode string) (*BatchResponse, error) {
+	mode, err := normalizeBatchMode(mode)
+	if err != nil {
+		return nil, err
+	}
+	run := newBatchRun(mode, len(reqs))
 	if len(reqs) == 0 {
-		return nil
+		return run.response(), nil
 	}
 
-	// Check if collection is enabled
-	enabled, err := s.settingsService.IsCollectionEnabled(authCenterUserID)
+	// 检查采集开关和单次、每日上限
+	usage, err := s.settingsService.CheckCollectionLimits(authCenterUserID, QuotaBloggers, len(reqs))
 	if err != nil {
-		return err
-	}
-	if !enabled {
-		return ErrCollectionDisabled
+		return nil, err
 	}
 
 	// Get user
 	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	bloggers := make([]*model.Blogger, len(reqs))
 	for i, req := range reqs {
-		bloggers[i] = &model.Blogger{
-			UserID:           user.ID,
-			XhsID:            req.XhsID,
-			BloggerName:      req.BloggerName,
-			AvatarURL:        req.AvatarURL,
-			Description:      req.Description,
-			FollowersCount:   req.FollowersCount,
-			BloggerURL:       req.BloggerURL,
-			CaptureTimestamp: req.CaptureTimestamp,
+		if reason := validateBatchBlogger(req); reason != "" {
+			run.reject(i, reason)
+			continue
+		}
+		run.accept(i)
+	}
+
+	// 每日额度只计新增的博主，更新已有博主不受限制
+	if len(run.pending) > usage.Remaining {
+		xhsIDs := make([]string, len(run.pending))
+		for j, i := range run.pending {
+			xhsIDs[j] = reqs[i].XhsID
+		}
+		existing, err := s.bloggerRepo.MatchExisting(user.ID, xhsIDs)
+		if err != nil {
+			return nil, err
+		}
+		run.limit(usage.Remaining, existing, "daily limit exceeded")
+	}
+
+	if run.shouldWrite() {
+		bloggers := make([]*model.Blogger, len(run.pending))
+		for j, i := range run.pending {
+			bloggers[j] = newBloggerFromRequest(user.ID, reqs[i])
+		}
+		if err := run.apply(s.bloggerRepo.UpsertBatch(bloggers, mode == BatchModeAtomic)); err != nil {
+			return nil, err
 		}
 	}
 
-	return s.bloggerRepo.BatchCreate(bloggers)
+	return run.response(), nil
+}
+
+// validateBatchBlogger 校验批量接口中的单条博主，返回拒绝原因
+func validateBatchBlogger(req *CreateBloggerRequest) string {
+	if req == nil {
+		return "empty item"
+	}
+	if req.XhsID == "" {
+		return "xhsId: required"
+	}
+	if req.CaptureTimestamp <= 0 {
+		return "captureTimestamp: required"
+	}
+	return ""
+}
+
+// newBloggerFromRequest 把创建请求转为博主模型
+func newBloggerFromRequest(userID string, req *CreateBloggerRequest) *model.Blogger {
+	return &model.Blogger{
+		UserID:             userID,
+		XhsID:              req.XhsID,
+		BloggerName:        req.BloggerName,
+		AvatarURL:          req.AvatarURL,
+		Description:        req.Description,
+		FollowersCount:     req.FollowersCount,
+		FollowingCount:     req.FollowingCount,
+		LikesCollectsCount: req.LikesCollectsCount,
+		IPLocation:         req.IPLocation,
+		Verified:           req.Verified,
+		VerifiedLabel:      req.VerifiedLabel,
+		Category:           req.Category,
+		BloggerURL:         req.BloggerURL,
+		CaptureTimestamp:   req.CaptureTimestamp,
+	}
+}
+
+// bloggerPatchFields 博主可编辑字段（API 字段名 -> 列），xhsId、粉丝数等主页数据只能由采集写入
+var bloggerPatchFields = map[string]patchField{
+	"bloggerName": stringPatchField("blogger_name", 100),
+	"avatarUrl":   stringPatchField("avatar_url", 500),
+	"description": stringPatchField("description", 100000),
+	"bloggerUrl":  stringPatchField("blogger_url", 500),
+	"category":    stringPatchField("category", 50),
 }
 
-// Update 更新博主信息（校验归属）
-func (s *BloggerService) Update(authCenterUserID string, blogger *model.Blogger) error {
+// Patch 部分更新博主信息（JSON Merge Patch，校验归属）
+// ifMatch 为客户端持有的版本号，与当前版本不一致时返回 repository.ErrVersionConflict；
+// 为 nil 时不做校验（客户端显式发送 If-Match: *；缺失 If-Match 的请求已在 handler 拒绝）
+func (s *BloggerService) Patch(authCenterUserID, id string, patch map[string]json.RawMessage, ifMatch *int64) (*model.Blogger, error) {
 	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	blogger, err := s.bloggerRepo.GetByID(user.ID, id)
+	if err != nil {
+		return nil, ErrBloggerNotFound
 	}
-	existing, err := s.bloggerRepo.GetByID(user.ID, blogger.ID)
-	if err != nil || existing == nil {
-		return ErrBloggerNotFound
+
+	updates, err := buildPatch(patch, bloggerPatchFields)
+	if err != nil {
+		return nil, err
+	}
+
+	version := blogger.Version
+	if ifMatch != nil {
+		if *ifMatch != blogger.Version {
+			return nil, repository.ErrVersionConflict
+		}
+		version = *ifMatch
 	}
-	return s.bloggerRepo.Update(blogger)
+	if len(updates) == 0 {
+		return blogger, nil
+	}
+
+	return s.bloggerRepo.Patch(user.ID, blogger.ID, version, updates)
 }
 
 // Delete 删除博主信息（校验归属）
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/keenchase/edit-business/internal/model"
+	"github.com/keenchase/edit-business/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCollectionNotFound  = errors.New("collection not found")
+	ErrCollectionNameTaken = errors.New("collection name already exists")
+	ErrInvalidCollection   = errors.New("invalid collection")
+)
+
+// maxCollectionNoteIDs 单次加入或排序的笔记 ID 上限
+const maxCollectionNoteIDs = 1000
+
+// CollectionService 收藏夹服务
+type CollectionService struct {
+	collectionRepo  *repository.CollectionRepository
+	noteRepo        *repository.NoteRepository
+	settingsService *UserSettingsService
+}
+
+// NewCollectionService 创建收藏夹服务实例
+func NewCollectionService(collectionRepo *repository.CollectionRepository, noteRepo *repository.NoteRepository, settingsService *UserSettingsService) *CollectionService {
+	return &CollectionService{
+		collectionRepo:  collectionRepo,
+		noteRepo:        noteRepo,
+		settingsService: settingsService,
+	}
+}
+
+// CreateCollectionRequest 创建收藏夹请求
+type CreateCollectionRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+// CollectionNoteIDsRequest 加入收藏夹或调整顺序的笔记 ID 列表
+type CollectionNoteIDsRequest struct {
+	NoteIDs []string `json:"noteIds" binding:"required"`
+}
+
+// AddCollectionNotesResponse 加入收藏夹结果
+type AddCollectionNotesResponse struct {
+	Added    int64    `json:"added"`    // 新加入的笔记数（已在收藏夹中的不计）
+	NotFound []string `json:"notFound"` // 不存在或不属于当前用户的笔记 ID
+}
+
+// ListCollectionNotesResponse 收藏夹笔记列表响应（按收藏夹内顺序）
+type ListCollectionNotesResponse struct {
+	Collection *model.Collection `json:"collection"`
+	Notes      []*model.Note     `json:"notes"`
+	Total      int64             `json:"total"`
+	Page       int               `json:"page"`
+	Size       int               `json:"size"`
+	TotalPages int               `json:"totalPages"`
+}
+
+// collectionPatchFields 收藏夹可编辑字段
+var collectionPatchFields = map[string]patchField{
+	"name":        stringPatchField("name", 100),
+	"description": stringPatchField("description", 2000),
+}
+
+// List 获取收藏夹列表及笔记数（按用户隔离）
+func (s *CollectionService) List(authCenterUserID string) ([]*repository.CollectionWithCount, error) {
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+	return s.collectionRepo.ListByUserID(user.ID)
+}
+
+// Create 创建收藏夹，同一用户下名称不能重复
+func (s *CollectionService) Create(authCenterUserID string, req *CreateCollectionRequest) (*model.Collection, error) {
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := normalizeCollectionName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if utf8.RuneCountInString(req.Description) > 2000 {
+		return nil, fmt.Errorf("%w: description must be at most 2000 characters", ErrInvalidCollection)
+	}
+	if err := s.checkNameAvailable(user.ID, name, ""); err != nil {
+		return nil, err
+	}
+
+	collection := &model.Collection{
+		UserID:      user.ID,
+		Name:        name,
+		Description: req.Description,
+	}
+	if err := s.collectionRepo.Create(collection); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// Get 获取收藏夹详情及笔记数（校验归属）
+func (s *CollectionService) Get(authCenterUserID, id string) (*repository.CollectionWithCount, error) {
+	_, collection, err := s.ownedCollection(authCenterUserID, id)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.collectionRepo.CountNotes(collection.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &repository.CollectionWithCount{Collection: *collection, NoteCount: count}, nil
+}
+
+// Patch 部分更新收藏夹（JSON Merge Patch，校验归属）
+func (s *CollectionService) Patch(authCenterUserID, id string, patch map[string]json.RawMessage) (*model.Collection, error) {
+	user, collection, err := s.ownedCollection(authCenterUserID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates, err := buildPatch(patch, collectionPatchFields)
+	if err != nil {
+		return nil, err
+	}
+	if raw, ok := updates["name"]; ok {
+		name, err := normalizeCollectionName(raw.(string))
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkNameAvailable(user.ID, name, collection.ID); err != nil {
+			return nil, err
+		}
+		updates["name"] = name
+	}
+	if len(updates) == 0 {
+		return collection, nil
+	}
+
+	return s.collectionRepo.Patch(user.ID, collection.ID, updates)
+}
+
+// Delete 删除收藏夹（校验归属），收藏夹中的笔记不受影响
+func (s *CollectionService) Delete(authCenterUserID, id string) error {
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return err
+	}
+	if err := s.collectionRepo.Delete(user.ID, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCollectionNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ListNotes 按收藏夹内顺序分页获取笔记（校验归属）
+func (s *CollectionService) ListNotes(authCenterUserID, id string, page, size int) (*ListCollectionNotesResponse, error) {
+	user, collection, err := s.ownedCollection(authCenterUserID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 100 {
+		size = 20
+	}
+
+	notes, total, err := s.collectionRepo.ListNotes(user.ID, collection.ID, (page-1)*size, size)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / size
+	if int(total)%size > 0 {
+		totalPages++
+	}
+
+	return &ListCollectionNotesResponse{
+		Collection: collection,
+		Notes:      notes,
+		Total:      total,
+		Page:       page,
+		Size:       size,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// AddNotes 把笔记加入收藏夹末尾（校验收藏夹和笔记归属），不存在或不属于当前用户的笔记在结果中列出
+func (s *CollectionService) AddNotes(authCenterUserID, id string, noteIDs []string) (*AddCollectionNotesResponse, error) {
+	user, collection, err := s.ownedCollection(authCenterUserID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := normalizeCollectionNoteIDs(noteIDs)
+	if err != nil {
+		return nil, err
+	}
+	owned, err := s.noteRepo.ExistingIDs(user.ID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &AddCollectionNotesResponse{NotFound: []string{}}
+	found := make(map[string]bool, len(owned))
+	for _, noteID := range owned {
+		found[noteID] = true
+	}
+	valid := make([]string, 0, len(owned))
+	for _, noteID := range ids {
+		if found[noteID] {
+			valid = append(valid, noteID)
+		} else {
+			resp.NotFound = append(resp.NotFound, noteID)
+		}
+	}
+
+	if resp.Added, err = s.collectionRepo.AddNotes(collection.ID, valid); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// RemoveNote 把笔记移出收藏夹（校验归属），笔记本身不受影响
+func (s *CollectionService) RemoveNote(authCenterUserID, id, noteID string) error {
+	_, collection, err := s.ownedCollection(authCenterUserID, id)
+	if err != nil {
+		return err
+	}
+	removed, err := s.collectionRepo.RemoveNotes(collection.ID, []string{noteID})
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return repository.ErrNoteNotInCollection
+	}
+	return nil
+}
+
+// Reorder 调整收藏夹内笔记顺序（校验归属）：noteIDs 依次排在最前，其余笔记保持原有相对顺序
+func (s *CollectionService) Reorder(authCenterUserID, id string, noteIDs []string) error {
+	_, collection, err := s.ownedCollection(authCenterUserID, id)
+	if err != nil {
+		return err
+	}
+	ids, err := normalizeCollectionNoteIDs(noteIDs)
+	if err != nil {
+		return err
+	}
+	return s.collectionRepo.Reorder(collection.ID, ids)
+}
+
+// ownedCollection 获取当前用户的收藏夹，不存在或不属于当前用户时返回 ErrCollectionNotFound
+func (s *CollectionService) ownedCollection(authCenterUserID, id string) (*model.User, *model.Collection, error) {
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	collection, err := findCollection(s.collectionRepo, user.ID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, collection, nil
+}
+
+// checkNameAvailable 校验收藏夹名称未被当前用户的其他收藏夹使用
+func (s *CollectionService) checkNameAvailable(userID, name, selfID string) error {
+	existing, err := s.collectionRepo.GetByUserIDAndName(userID, name)
+	if err == nil && existing.ID != selfID {
+		return ErrCollectionNameTaken
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// findCollection 获取用户的收藏夹（笔记采集、批量操作与收藏夹接口共用）
+func findCollection(repo *repository.CollectionRepository, userID, id string) (*model.Collection, error) {
+	collection, err := repo.GetByUserIDAndID(userID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCollectionNotFound
+		}
+		return nil, err
+	}
+	return collection, nil
+}
+
+func normalizeCollectionName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: name is required", ErrInvalidCollection)
+	}
+	if utf8.RuneCountInString(name) > 100 {
+		return "", fmt.Errorf("%w: name must be at most 100 characters", ErrInvalidCollection)
+	}
+	return name, nil
+}
+
+// normalizeCollectionNoteIDs 去除空项与重复项，并限制数量
+func normalizeCollectionNoteIDs(noteIDs []string) ([]string, error) {
+	seen := make(map[string]bool, len(noteIDs))
+	ids := make([]string, 0, len(noteIDs))
+	for _, id := range noteIDs {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: noteIds is empty", ErrInvalidCollection)
+	}
+	if len(ids) > maxCollectionNoteIDs {
+		return nil, fmt.Errorf("%w: at most %d noteIds", ErrInvalidCollection, maxCollectionNoteIDs)
+	}
+	return ids, nil
+}
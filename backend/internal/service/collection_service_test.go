@@ -0,0 +1,120 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/keenchase/edit-business/internal/dbtest"
+	"github.com/keenchase/edit-business/internal/repository"
+)
+
+func TestCollectionMembershipAndOrder(t *testing.T) {
+	s := newTestServices(t)
+	collections := NewCollectionService(repository.NewCollectionRepository(s.db), repository.NewNoteRepository(s.db), s.settings)
+	dbtest.CreateUser(t, s.db, "user-a", nil)
+	dbtest.CreateUser(t, s.db, "user-b", nil)
+
+	capture := func(userID, xhsNoteID string) string {
+		note, err := s.notes.Create(userID, testNoteRequest(xhsNoteID, 1))
+		if err != nil {
+			t.Fatalf("capture %s: %v", xhsNoteID, err)
+		}
+		return note.ID
+	}
+	a := capture("user-a", "65a1b2c3d4e5f60718293a01")
+	b := capture("user-a", "65a1b2c3d4e5f60718293a02")
+	c := capture("user-a", "65a1b2c3d4e5f60718293a03")
+	other := capture("user-b", "65a1b2c3d4e5f60718293a04")
+
+	collection, err := collections.Create("user-a", &CreateCollectionRequest{Name: "picks"})
+	if err != nil {
+		t.Fatalf("create collection: %v", err)
+	}
+	if _, err := collections.Create("user-a", &CreateCollectionRequest{Name: " picks "}); !errors.Is(err, ErrCollectionNameTaken) {
+		t.Errorf("duplicate name err = %v, want ErrCollectionNameTaken", err)
+	}
+
+	order := func(userID string) []string {
+		t.Helper()
+		resp, err := collections.ListNotes(userID, collection.ID, 1, 100)
+		if err != nil {
+			t.Fatalf("list notes: %v", err)
+		}
+		ids := make([]string, len(resp.Notes))
+		for i, note := range resp.Notes {
+			ids[i] = note.ID
+		}
+		if resp.Total != int64(len(ids)) {
+			t.Errorf("total = %d, want %d", resp.Total, len(ids))
+		}
+		return ids
+	}
+
+	// 其他用户的笔记不能加入，重复的笔记只加入一次
+	added, err := collections.AddNotes("user-a", collection.ID, []string{a, b, other, a})
+	if err != nil {
+		t.Fatalf("add notes: %v", err)
+	}
+	if added.Added != 2 || !reflect.DeepEqual(added.NotFound, []string{other}) {
+		t.Errorf("add = %+v, want 2 added and %s not found", added, other)
+	}
+
+	// 已在收藏夹中的笔记保持原位置，新笔记追加到末尾
+	added, err = collections.AddNotes("user-a", collection.ID, []string{c, a})
+	if err != nil {
+		t.Fatalf("add notes again: %v", err)
+	}
+	if added.Added != 1 {
+		t.Errorf("added = %d, want 1", added.Added)
+	}
+	if got, want := order("user-a"), []string{a, b, c}; !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+
+	// 指定的笔记排在最前，其余保持原有相对顺序
+	if err := collections.Reorder("user-a", collection.ID, []string{c}); err != nil {
+		t.Fatalf("reorder: %v", err)
+	}
+	if got, want := order("user-a"), []string{c, a, b}; !reflect.DeepEqual(got, want) {
+		t.Errorf("order after reorder = %v, want %v", got, want)
+	}
+	if err := collections.Reorder("user-a", collection.ID, []string{b, other}); !errors.Is(err, repository.ErrNoteNotInCollection) {
+		t.Errorf("reorder with a foreign note err = %v, want ErrNoteNotInCollection", err)
+	}
+	if got, want := order("user-a"), []string{c, a, b}; !reflect.DeepEqual(got, want) {
+		t.Errorf("failed reorder changed the order to %v", got)
+	}
+
+	if err := collections.RemoveNote("user-a", collection.ID, a); err != nil {
+		t.Fatalf("remove note: %v", err)
+	}
+	if err := collections.RemoveNote("user-a", collection.ID, a); !errors.Is(err, repository.ErrNoteNotInCollection) {
+		t.Errorf("remove twice err = %v, want ErrNoteNotInCollection", err)
+	}
+	if _, err := s.notes.GetByID("user-a", a); err != nil {
+		t.Errorf("removed note was deleted: %v", err)
+	}
+
+	// 采集时指定收藏夹的笔记追加到末尾，回收站中的笔记不列出
+	req := testNoteRequest("65a1b2c3d4e5f60718293a05", 1)
+	req.CollectionID = collection.ID
+	d, err := s.notes.Create("user-a", req)
+	if err != nil {
+		t.Fatalf("capture into collection: %v", err)
+	}
+	if err := s.notes.Delete("user-a", b); err != nil {
+		t.Fatalf("delete note: %v", err)
+	}
+	if got, want := order("user-a"), []string{c, d.ID}; !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+
+	// 其他用户看不到该收藏夹
+	if _, err := collections.ListNotes("user-b", collection.ID, 1, 20); !errors.Is(err, ErrCollectionNotFound) {
+		t.Errorf("list by another user err = %v, want ErrCollectionNotFound", err)
+	}
+	if _, err := collections.AddNotes("user-b", collection.ID, []string{other}); !errors.Is(err, ErrCollectionNotFound) {
+		t.Errorf("add by another user err = %v, want ErrCollectionNotFound", err)
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/keenchase/edit-business/internal/analyzer"
+	"github.com/keenchase/edit-business/internal/repository"
+)
+
+// 内容词项统计返回条数
+const (
+	defaultContentStatsLimit = 50
+	maxContentStatsLimit     = 200
+)
+
+var ErrInvalidStatsQuery = errors.New("invalid stats query")
+
+// ContentStatsRequest 话题/关键词统计请求；时间范围为毫秒时间戳，作用于 dateField
+type ContentStatsRequest struct {
+	Kind      string `form:"kind"`      // 仅关键词接口：keyword（默认）/ mention / emoji
+	DateField string `form:"dateField"` // captureTimestamp（默认）/ publishDate
+	From      *int64 `form:"from"`
+	To        *int64 `form:"to"`
+	Rank      string `form:"rank"`     // frequency（默认）/ engagement
+	MinNotes  int    `form:"minNotes"` // 至少出现在多少篇笔记中，默认 1
+	Limit     int    `form:"limit"`    // 默认 50，最大 200
+}
+
+// ContentStatsResponse 话题/关键词统计响应
+type ContentStatsResponse struct {
+	Kind       string                        `json:"kind"`
+	Rank       string                        `json:"rank"`
+	DateField  string                        `json:"dateField"`
+	From       *int64                        `json:"from,omitempty"`
+	To         *int64                        `json:"to,omitempty"`
+	TotalNotes int64                         `json:"totalNotes"` // 时间范围内的笔记总数
+	Terms      []*repository.ContentTermStat `json:"terms"`
+}
+
+// ContentTermStats 统计当前用户笔记中的话题、关键词、提及或表情
+func (s *StatsService) ContentTermStats(authCenterUserID string, req *ContentStatsRequest) (*ContentStatsResponse, error) {
+	q, err := contentTermQuery(req)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	terms, total, err := s.noteRepo.ContentTermStats(user.ID, q)
+	if err != nil {
+		return nil, err
+	}
+	if terms == nil {
+		terms = []*repository.ContentTermStat{}
+	}
+	return &ContentStatsResponse{
+		Kind:       q.Kind,
+		Rank:       q.Rank,
+		DateField:  q.DateField,
+		From:       q.From,
+		To:         q.To,
+		TotalNotes: total,
+		Terms:      terms,
+	}, nil
+}
+
+// contentTermQuery 校验请求并填充默认值
+func contentTermQuery(req *ContentStatsRequest) (*repository.ContentTermQuery, error) {
+	q := &repository.ContentTermQuery{
+		Kind:      req.Kind,
+		DateField: req.DateField,
+		From:      req.From,
+		To:        req.To,
+		MinNotes:  req.MinNotes,
+		Rank:      req.Rank,
+		Limit:     req.Limit,
+	}
+	if q.Kind == "" {
+		q.Kind = analyzer.KindKeyword
+	}
+	if !slices.Contains(analyzer.Kinds, q.Kind) {
+		return nil, fmt.Errorf("%w: kind must be one of %v", ErrInvalidStatsQuery, analyzer.Kinds)
+	}
+	if q.DateField == "" {
+		q.DateField = "captureTimestamp"
+	}
+	if q.DateField != "captureTimestamp" && q.DateField != "publishDate" {
+		return nil, fmt.Errorf("%w: dateField must be captureTimestamp or publishDate", ErrInvalidStatsQuery)
+	}
+	if q.Rank == "" {
+		q.Rank = repository.ContentRankFrequency
+	}
+	if q.Rank != repository.ContentRankFrequency && q.Rank != repository.ContentRankEngagement {
+		return nil, fmt.Errorf("%w: rank must be frequency or engagement", ErrInvalidStatsQuery)
+	}
+	if q.From != nil && q.To != nil && *q.From > *q.To {
+		return nil, fmt.Errorf("%w: from must not be after to", ErrInvalidStatsQuery)
+	}
+	if q.MinNotes < 1 {
+		q.MinNotes = 1
+	}
+	if q.Limit <= 0 {
+		q.Limit = defaultContentStatsLimit
+	}
+	if q.Limit > maxContentStatsLimit {
+		q.Limit = maxContentStatsLimit
+	}
+	return q, nil
+}
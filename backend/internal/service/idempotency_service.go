@@ -0,0 +1,120 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/keenchase/edit-business/internal/model"
+	"github.com/keenchase/edit-business/internal/repository"
+)
+
+var (
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was already used for a different request")
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still in progress")
+)
+
+// IdempotencyService 插件同步请求的幂等处理：同一用户在有效期内重复提交同一个幂等键时，
+// 返回首次请求的响应而不是重新执行
+type IdempotencyService struct {
+	repo            *repository.IdempotencyRepository
+	settingsService *UserSettingsService
+	ttl             time.Duration
+}
+
+// NewIdempotencyService 创建幂等服务实例，ttl 为幂等键的有效期
+func NewIdempotencyService(repo *repository.IdempotencyRepository, settingsService *UserSettingsService, ttl time.Duration) *IdempotencyService {
+	return &IdempotencyService{repo: repo, settingsService: settingsService, ttl: ttl}
+}
+
+// IdempotentRequest 一次携带幂等键的请求
+type IdempotentRequest struct {
+	AuthCenterUserID string
+	Key              string
+	RequestHash      string // 请求方法、路径和请求体的摘要
+}
+
+// StoredResponse 首次请求记录的响应
+type StoredResponse struct {
+	StatusCode int
+	Header     map[string]string // 需要重放的响应头
+	Body       []byte
+}
+
+// Begin 占用幂等键：首次请求返回 (nil, nil)，调用方处理完成后调用 Complete 或 Release；
+// 重复请求返回记录的响应；同一个键对应不同请求返回 ErrIdempotencyKeyReused，
+// 首次请求尚未完成返回 ErrIdempotencyKeyInProgress
+func (s *IdempotencyService) Begin(req *IdempotentRequest) (*StoredResponse, error) {
+	user, err := s.settingsService.GetUserByAuthCenterUserID(req.AuthCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	existing, err := s.repo.Reserve(&model.IdempotencyKey{
+		UserID:      user.ID,
+		Key:         req.Key,
+		RequestHash: req.RequestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, nil
+	}
+
+	if existing.RequestHash != req.RequestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if existing.StatusCode == 0 {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+	header := make(map[string]string, len(existing.ResponseHeaders))
+	for k, v := range existing.ResponseHeaders {
+		if s, ok := v.(string); ok {
+			header[k] = s
+		}
+	}
+	return &StoredResponse{StatusCode: existing.StatusCode, Header: header, Body: existing.ResponseBody}, nil
+}
+
+// Complete 记录首次请求的响应，供重复请求重放
+func (s *IdempotencyService) Complete(req *IdempotentRequest, resp *StoredResponse) error {
+	user, err := s.settingsService.GetUserByAuthCenterUserID(req.AuthCenterUserID)
+	if err != nil {
+		return err
+	}
+	header := make(model.JSONB, len(resp.Header))
+	for k, v := range resp.Header {
+		header[k] = v
+	}
+	return s.repo.Complete(user.ID, req.Key, resp.StatusCode, resp.Body, header)
+}
+
+// Release 首次请求未能完成（服务端错误或 panic）时释放幂等键，客户端可以用同一个键重试
+func (s *IdempotencyService) Release(req *IdempotentRequest) error {
+	user, err := s.settingsService.GetUserByAuthCenterUserID(req.AuthCenterUserID)
+	if err != nil {
+		return err
+	}
+	return s.repo.Release(user.ID, req.Key)
+}
+
+// StartCleaner 启动后台清理任务，每隔 interval 删除过期的幂等键
+func (s *IdempotencyService) StartCleaner(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			n, err := s.repo.DeleteExpired(time.Now())
+			if err != nil {
+				log.Printf("[Idempotency] cleanup failed: %v", err)
+			} else if n > 0 {
+				log.Printf("[Idempotency] deleted %d expired keys", n)
+			}
+			<-ticker.C
+		}
+	}()
+}
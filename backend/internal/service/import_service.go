@@ -0,0 +1,353 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/keenchase/edit-business/internal/importer"
+	"github.com/keenchase/edit-business/internal/model"
+	"github.com/keenchase/edit-business/internal/repository"
+)
+
+// importPreviewRows 预览返回的行数
+const importPreviewRows = 20
+
+// 导入行状态
+const (
+	ImportRowValid    = "valid"    // 预览：校验通过，将会导入
+	ImportRowImported = "imported" // 已写入
+	ImportRowInvalid  = "invalid"  // 校验失败
+	ImportRowSkipped  = "skipped"  // 新增记录超出每日上限，未写入
+	ImportRowFailed   = "failed"   // 写入数据库失败
+)
+
+// ImportService 笔记/博主文件导入服务
+// 有效行逐条走与插件采集相同的 Upsert 逻辑，并受用户的采集开关、单批上限和每日上限约束
+type ImportService struct {
+	noteRepo        *repository.NoteRepository
+	bloggerRepo     *repository.BloggerRepository
+	settingsService *UserSettingsService
+}
+
+// NewImportService 创建导入服务实例
+func NewImportService(noteRepo *repository.NoteRepository, bloggerRepo *repository.BloggerRepository, settingsService *UserSettingsService) *ImportService {
+	return &ImportService{
+		noteRepo:        noteRepo,
+		bloggerRepo:     bloggerRepo,
+		settingsService: settingsService,
+	}
+}
+
+// ImportRequest 导入请求
+type ImportRequest struct {
+	Format  importer.Format
+	Data    []byte
+	Mapping map[string]string // 文件列名 -> 目标字段，未指定的列自动匹配
+	DryRun  bool              // 仅校验并预览，不写入
+}
+
+// ImportRowResult 单行导入结果（行号从 1 开始，不含表头）
+type ImportRowResult struct {
+	Row    int      `json:"row"`
+	Status string   `json:"status"`
+	ID     string   `json:"id,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// ImportResponse 导入响应
+// rows 只列出未成功的行（invalid / skipped / failed），preview 仅在 dryRun 时返回
+type ImportResponse struct {
+	DryRun          bool               `json:"dryRun"`
+	Columns         []string           `json:"columns"`
+	Mapping         importer.Mapping   `json:"mapping"`
+	UnmappedColumns []string           `json:"unmappedColumns"`
+	Total           int                `json:"total"`
+	Valid           int                `json:"valid"`
+	Invalid         int                `json:"invalid"`
+	Imported        int                `json:"imported"`
+	Skipped         int                `json:"skipped"`
+	Failed          int                `json:"failed"`
+	DailyRemaining  int                `json:"dailyRemaining"`
+	Preview         []interface{}      `json:"preview,omitempty"`
+	Rows            []*ImportRowResult `json:"rows"`
+}
+
+// importPlan 导入的公共流程：解析文件、映射列、逐行校验、按每日剩余额度写入
+type importPlan struct {
+	fields   []importer.Field
+	validate func(rec importer.Record) (interface{}, []string)
+	save     func(v interface{}) (string, error)
+	exists   func(v interface{}) (bool, error) // 是否会更新已有记录（不占用每日额度）
+	quota    string                            // 每日额度分类（QuotaNotes / QuotaBloggers）
+}
+
+// ImportNotes 导入笔记
+func (s *ImportService) ImportNotes(authCenterUserID string, req *ImportRequest) (*ImportResponse, error) {
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.settingsService.GetOrCreateSettings(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UnixMilli()
+	loc := settings.Location()
+
+	return s.run(user.ID, req, &importPlan{
+		fields: importer.NoteFields,
+		validate: func(rec importer.Record) (interface{}, []string) {
+			return noteRequestFromRecord(rec, now, loc)
+		},
+		save: func(v interface{}) (string, error) {
+			note, err := s.noteRepo.Upsert(newNoteFromRequest(user.ID, v.(*CreateNoteRequest)))
+			if err != nil {
+				return "", err
+			}
+			return note.ID, nil
+		},
+		exists: func(v interface{}) (bool, error) {
+			note := newNoteFromRequest(user.ID, v.(*CreateNoteRequest))
+			existing, err := s.noteRepo.MatchExisting(user.ID, []*model.Note{note})
+			if err != nil {
+				return false, err
+			}
+			return existing[0], nil
+		},
+		quota: QuotaNotes,
+	})
+}
+
+// ImportBloggers 导入博主
+func (s *ImportService) ImportBloggers(authCenterUserID string, req *ImportRequest) (*ImportResponse, error) {
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.settingsService.GetOrCreateSettings(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UnixMilli()
+	loc := settings.Location()
+
+	return s.run(user.ID, req, &importPlan{
+		fields: importer.BloggerFields,
+		validate: func(rec importer.Record) (interface{}, []string) {
+			return bloggerRequestFromRecord(rec, now, loc)
+		},
+		save: func(v interface{}) (string, error) {
+			blogger := newBloggerFromRequest(user.ID, v.(*CreateBloggerRequest))
+			if err := s.bloggerRepo.UpsertImported(blogger); err != nil {
+				return "", err
+			}
+			return blogger.ID, nil
+		},
+		exists: func(v interface{}) (bool, error) {
+			existing, err := s.bloggerRepo.MatchExisting(user.ID, []string{v.(*CreateBloggerRequest).XhsID})
+			if err != nil {
+				return false, err
+			}
+			return existing[0], nil
+		},
+		quota: QuotaBloggers,
+	})
+}
+
+func (s *ImportService) run(userID string, req *ImportRequest, plan *importPlan) (*ImportResponse, error) {
+	table, err := importer.ReadTable(req.Format, req.Data)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := importer.BuildMapping(table.Columns, plan.fields, req.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	// 与插件采集相同的限制：采集开关、单批上限（按文件数据行数计）和每日额度
+	// 每日额度按用户时区的自然日计算，只计新增的记录，超出部分的新增行标记为 skipped
+	usage, err := s.settingsService.CheckUserCollectionLimits(userID, plan.quota, len(table.Rows))
+	if err != nil {
+		return nil, err
+	}
+	remaining := usage.Remaining
+	// 行数不超过剩余额度时无需逐行判断是否为已有记录
+	checkExisting := len(table.Rows) > remaining
+	created := 0 // 占用额度的新增行数
+
+	resp := &ImportResponse{
+		DryRun:          req.DryRun,
+		Columns:         table.Columns,
+		Mapping:         mapping,
+		UnmappedColumns: mapping.Unmapped(table.Columns),
+		Total:           len(table.Rows),
+		DailyRemaining:  remaining,
+		Rows:            []*ImportRowResult{},
+	}
+
+	for i, row := range table.Rows {
+		result := &ImportRowResult{Row: i + 1}
+		v, errs := plan.validate(mapping.Apply(table.Columns, row))
+
+		isNew := true
+		if len(errs) == 0 && checkExisting {
+			existing, err := plan.exists(v)
+			if err != nil {
+				return nil, err
+			}
+			isNew = !existing
+		}
+
+		switch {
+		case len(errs) > 0:
+			result.Status = ImportRowInvalid
+			result.Errors = errs
+			resp.Invalid++
+		case isNew && created >= remaining:
+			resp.Valid++
+			result.Status = ImportRowSkipped
+			result.Errors = []string{"超出每日采集上限"}
+			resp.Skipped++
+		case req.DryRun:
+			resp.Valid++
+			result.Status = ImportRowValid
+			if isNew {
+				created++
+			}
+			if len(resp.Preview) < importPreviewRows {
+				resp.Preview = append(resp.Preview, v)
+			}
+		default:
+			resp.Valid++
+			id, err := plan.save(v)
+			if err != nil {
+				log.Printf("[Import] row %d failed: %v", result.Row, err)
+				result.Status = ImportRowFailed
+				result.Errors = []string{"写入失败"}
+				resp.Failed++
+				break
+			}
+			result.Status = ImportRowImported
+			result.ID = id
+			resp.Imported++
+			if isNew {
+				created++
+			}
+		}
+
+		if result.Status != ImportRowValid && result.Status != ImportRowImported {
+			resp.Rows = append(resp.Rows, result)
+		}
+	}
+
+	if !req.DryRun {
+		resp.DailyRemaining = remaining - created
+	}
+	return resp, nil
+}
+
+// noteRequestFromRecord 把导入行转为创建笔记请求，返回逐字段的校验错误
+// 不带时区的日期按用户时区 loc 解释；未提供采集时间时使用导入时间
+func noteRequestFromRecord(rec importer.Record, now int64, loc *time.Location) (interface{}, []string) {
+	var errs []string
+	req := &CreateNoteRequest{
+		URL:           rec["url"],
+		Title:         rec["title"],
+		Author:        rec["author"],
+		AuthorXhsID:   rec["authorXhsId"],
+		Content:       rec["content"],
+		Tags:          importer.SplitTags(rec["tags"]),
+		ImageURLs:     importer.SplitList(rec["imageUrls"]),
+		NoteType:      rec["noteType"],
+		CoverImageURL: rec["coverImageUrl"],
+		Source:        rec["source"],
+	}
+	if msg := validateURL(req.URL, true); msg != "" {
+		errs = append(errs, "url: "+msg)
+	}
+	if v := rec["videoUrl"]; v != "" {
+		req.VideoURL = &v
+	}
+
+	for _, c := range []struct {
+		field string
+		dst   *int32
+	}{{"likes", &req.Likes}, {"collects", &req.Collects}, {"comments", &req.Comments}} {
+		n, err := importer.ParseCount(rec[c.field])
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", c.field, err))
+		}
+		*c.dst = n
+	}
+
+	var err error
+	if req.PublishDate, err = importer.ParseTimestamp(rec["publishDate"], loc); err != nil {
+		errs = append(errs, "publishDate: "+err.Error())
+	}
+	if req.CaptureTimestamp, err = importer.ParseTimestamp(rec["captureTimestamp"], loc); err != nil {
+		errs = append(errs, "captureTimestamp: "+err.Error())
+	}
+	if req.CaptureTimestamp == 0 {
+		req.CaptureTimestamp = now
+	}
+	return req, errs
+}
+
+// bloggerRequestFromRecord 把导入行转为创建博主请求，日期解释同 noteRequestFromRecord
+func bloggerRequestFromRecord(rec importer.Record, now int64, loc *time.Location) (interface{}, []string) {
+	var errs []string
+	req := &CreateBloggerRequest{
+		XhsID:         rec["xhsId"],
+		BloggerName:   rec["bloggerName"],
+		AvatarURL:     rec["avatarUrl"],
+		Description:   rec["description"],
+		BloggerURL:    rec["bloggerUrl"],
+		IPLocation:    rec["ipLocation"],
+		VerifiedLabel: rec["verifiedLabel"],
+		Category:      rec["category"],
+	}
+	if req.XhsID == "" {
+		errs = append(errs, "xhsId: required")
+	}
+	if msg := validateURL(req.BloggerURL, false); msg != "" {
+		errs = append(errs, "bloggerUrl: "+msg)
+	}
+
+	var err error
+	if req.FollowersCount, err = importer.ParseCount(rec["followersCount"]); err != nil {
+		errs = append(errs, "followersCount: "+err.Error())
+	}
+	if req.FollowingCount, err = importer.ParseCount(rec["followingCount"]); err != nil {
+		errs = append(errs, "followingCount: "+err.Error())
+	}
+	if req.LikesCollectsCount, err = importer.ParseCount(rec["likesCollectsCount"]); err != nil {
+		errs = append(errs, "likesCollectsCount: "+err.Error())
+	}
+	if req.Verified, err = importer.ParseBool(rec["verified"]); err != nil {
+		errs = append(errs, "verified: "+err.Error())
+	}
+	if req.CaptureTimestamp, err = importer.ParseTimestamp(rec["captureTimestamp"], loc); err != nil {
+		errs = append(errs, "captureTimestamp: "+err.Error())
+	}
+	if req.CaptureTimestamp == 0 {
+		req.CaptureTimestamp = now
+	}
+	return req, errs
+}
+
+// validateURL 校验 http(s) 链接，返回错误描述
+func validateURL(raw string, required bool) string {
+	if raw == "" {
+		if required {
+			return "required"
+		}
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "invalid url"
+	}
+	return ""
+}
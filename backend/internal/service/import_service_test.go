@@ -0,0 +1,73 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keenchase/edit-business/internal/importer"
+)
+
+func TestImportRecordsUseUserTimezone(t *testing.T) {
+	const now = int64(1700000000000)
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+
+	rec := importer.Record{
+		"url":              "https://www.xiaohongshu.com/explore/65a1b2c3d4e5f60718293a4b",
+		"publishDate":      "2024-03-01 09:30",
+		"captureTimestamp": "2024-03-02",
+	}
+	tests := []struct {
+		name        string
+		loc         *time.Location
+		wantPublish int64
+		wantCapture int64
+	}{
+		{"Asia/Shanghai", shanghai,
+			time.Date(2024, 3, 1, 9, 30, 0, 0, shanghai).UnixMilli(),
+			time.Date(2024, 3, 2, 0, 0, 0, 0, shanghai).UnixMilli()},
+		{"America/New_York", newYork,
+			time.Date(2024, 3, 1, 9, 30, 0, 0, newYork).UnixMilli(),
+			time.Date(2024, 3, 2, 0, 0, 0, 0, newYork).UnixMilli()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, errs := noteRequestFromRecord(rec, now, tt.loc)
+			if len(errs) > 0 {
+				t.Fatalf("errors: %v", errs)
+			}
+			note := v.(*CreateNoteRequest)
+			if note.PublishDate != tt.wantPublish || note.CaptureTimestamp != tt.wantCapture {
+				t.Errorf("publishDate/captureTimestamp = %d/%d, want %d/%d",
+					note.PublishDate, note.CaptureTimestamp, tt.wantPublish, tt.wantCapture)
+			}
+
+			v, errs = bloggerRequestFromRecord(importer.Record{"xhsId": "5f0e1d2c", "captureTimestamp": "2024-03-02"}, now, tt.loc)
+			if len(errs) > 0 {
+				t.Fatalf("errors: %v", errs)
+			}
+			if got := v.(*CreateBloggerRequest).CaptureTimestamp; got != tt.wantCapture {
+				t.Errorf("blogger captureTimestamp = %d, want %d", got, tt.wantCapture)
+			}
+		})
+	}
+}
+
+func TestImportRecordDefaultsCaptureTimestamp(t *testing.T) {
+	const now = int64(1700000000000)
+	rec := importer.Record{"url": "https://www.xiaohongshu.com/explore/65a1b2c3d4e5f60718293a4b", "publishDate": "1709256600000"}
+	v, errs := noteRequestFromRecord(rec, now, time.UTC)
+	if len(errs) > 0 {
+		t.Fatalf("errors: %v", errs)
+	}
+	note := v.(*CreateNoteRequest)
+	if note.CaptureTimestamp != now || note.PublishDate != 1709256600000 {
+		t.Errorf("captureTimestamp/publishDate = %d/%d", note.CaptureTimestamp, note.PublishDate)
+	}
+}
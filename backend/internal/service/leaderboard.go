@@ -0,0 +1,168 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/keenchase/edit-business/internal/repository"
+)
+
+// 排行榜分页大小
+const (
+	defaultLeaderboardSize = 20
+	maxLeaderboardSize     = 100
+)
+
+// LeaderboardRequest 排行榜请求；时间范围为毫秒时间戳，作用于 dateField
+type LeaderboardRequest struct {
+	DateField string `form:"dateField"` // captureTimestamp（默认）/ publishDate
+	From      *int64 `form:"from"`
+	To        *int64 `form:"to"`
+	Sort      string `form:"sort"`     // notes（默认）/ likes / engagement / avgEngagement
+	MinNotes  int    `form:"minNotes"` // 至少包含多少篇笔记，默认 1
+	Tag       string `form:"tag"`      // 仅标签组合：只返回包含该标签的组合
+	Page      int    `form:"page"`
+	Size      int    `form:"size"` // 默认 20，最大 100
+}
+
+// LeaderboardResponse 作者/标签/笔记类型排行榜响应
+type LeaderboardResponse struct {
+	Dimension  string                         `json:"dimension"`
+	Sort       string                         `json:"sort"`
+	DateField  string                         `json:"dateField"`
+	From       *int64                         `json:"from,omitempty"`
+	To         *int64                         `json:"to,omitempty"`
+	TotalNotes int64                          `json:"totalNotes"` // 时间范围内的笔记总数
+	Items      []*repository.LeaderboardEntry `json:"items"`
+	Total      int64                          `json:"total"` // 满足条件的分组总数
+	Page       int                            `json:"page"`
+	Size       int                            `json:"size"`
+	TotalPages int                            `json:"totalPages"`
+}
+
+// TagPairsResponse 标签共现组合响应
+type TagPairsResponse struct {
+	Tag        string                     `json:"tag,omitempty"`
+	Sort       string                     `json:"sort"`
+	DateField  string                     `json:"dateField"`
+	From       *int64                     `json:"from,omitempty"`
+	To         *int64                     `json:"to,omitempty"`
+	Pairs      []*repository.TagPairEntry `json:"pairs"`
+	Total      int64                      `json:"total"`
+	Page       int                        `json:"page"`
+	Size       int                        `json:"size"`
+	TotalPages int                        `json:"totalPages"`
+}
+
+// Leaderboard 按作者、标签或笔记类型统计当前用户的笔记排行
+func (s *StatsService) Leaderboard(authCenterUserID, dimension string, req *LeaderboardRequest) (*LeaderboardResponse, error) {
+	q, err := leaderboardQuery(req)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, total, totalNotes, err := s.noteRepo.Leaderboard(user.ID, dimension, q)
+	if err != nil {
+		return nil, err
+	}
+	return &LeaderboardResponse{
+		Dimension:  dimension,
+		Sort:       q.Sort,
+		DateField:  q.DateField,
+		From:       q.From,
+		To:         q.To,
+		TotalNotes: totalNotes,
+		Items:      items,
+		Total:      total,
+		Page:       req.Page,
+		Size:       req.Size,
+		TotalPages: pageCount(total, req.Size),
+	}, nil
+}
+
+// TagPairs 统计当前用户笔记中经常一起出现的标签组合
+func (s *StatsService) TagPairs(authCenterUserID string, req *LeaderboardRequest) (*TagPairsResponse, error) {
+	q, err := leaderboardQuery(req)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs, total, err := s.noteRepo.TagPairs(user.ID, q)
+	if err != nil {
+		return nil, err
+	}
+	return &TagPairsResponse{
+		Tag:        q.Tag,
+		Sort:       q.Sort,
+		DateField:  q.DateField,
+		From:       q.From,
+		To:         q.To,
+		Pairs:      pairs,
+		Total:      total,
+		Page:       req.Page,
+		Size:       req.Size,
+		TotalPages: pageCount(total, req.Size),
+	}, nil
+}
+
+// leaderboardQuery 校验请求并填充默认值（会修正 req 中的分页参数）
+func leaderboardQuery(req *LeaderboardRequest) (*repository.LeaderboardQuery, error) {
+	if req.DateField == "" {
+		req.DateField = "captureTimestamp"
+	}
+	if req.DateField != "captureTimestamp" && req.DateField != "publishDate" {
+		return nil, fmt.Errorf("%w: dateField must be captureTimestamp or publishDate", ErrInvalidStatsQuery)
+	}
+	if req.From != nil && req.To != nil && *req.From > *req.To {
+		return nil, fmt.Errorf("%w: from must not be after to", ErrInvalidStatsQuery)
+	}
+	if req.Sort == "" {
+		req.Sort = repository.LeaderboardSortNotes
+	}
+	switch req.Sort {
+	case repository.LeaderboardSortNotes, repository.LeaderboardSortLikes,
+		repository.LeaderboardSortEngagement, repository.LeaderboardSortAvgEngagement:
+	default:
+		return nil, fmt.Errorf("%w: sort must be notes, likes, engagement or avgEngagement", ErrInvalidStatsQuery)
+	}
+	if req.MinNotes < 1 {
+		req.MinNotes = 1
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Size <= 0 {
+		req.Size = defaultLeaderboardSize
+	}
+	if req.Size > maxLeaderboardSize {
+		req.Size = maxLeaderboardSize
+	}
+
+	return &repository.LeaderboardQuery{
+		DateField: req.DateField,
+		From:      req.From,
+		To:        req.To,
+		Sort:      req.Sort,
+		MinNotes:  req.MinNotes,
+		Tag:       strings.ToLower(strings.Trim(req.Tag, "# ")),
+		Offset:    (req.Page - 1) * req.Size,
+		Limit:     req.Size,
+	}, nil
+}
+
+// pageCount 总页数
+func pageCount(total int64, size int) int {
+	pages := int(total) / size
+	if int(total)%size > 0 {
+		pages++
+	}
+	return pages
+}
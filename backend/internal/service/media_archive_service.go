@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/keenchase/edit-business/internal/archiver"
+	"github.com/keenchase/edit-business/internal/model"
+	"github.com/keenchase/edit-business/internal/repository"
+)
+
+const (
+	// mediaClaimLease 领取的媒体在该时间内未处理完会被重新领取（进程中途退出等情况）
+	mediaClaimLease = 30 * time.Minute
+	// mediaMaxRetryDelay 重试间隔上限
+	mediaMaxRetryDelay = 24 * time.Hour
+)
+
+// MediaArchiveService 后台归档笔记引用的图片和视频：定期领取待归档媒体，下载到自有存储，
+// 并把归档链接写回笔记；临时失败按指数退避重试，超过次数或不可恢复的错误不再尝试
+type MediaArchiveService struct {
+	mediaRepo   *repository.NoteMediaRepository
+	archiver    *archiver.Archiver
+	batchSize   int
+	maxAttempts int
+	retryDelay  time.Duration
+}
+
+// NewMediaArchiveService 创建媒体归档服务实例
+func NewMediaArchiveService(mediaRepo *repository.NoteMediaRepository, arch *archiver.Archiver, batchSize, maxAttempts int, retryDelay time.Duration) *MediaArchiveService {
+	return &MediaArchiveService{
+		mediaRepo:   mediaRepo,
+		archiver:    arch,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+		retryDelay:  retryDelay,
+	}
+}
+
+// ArchiveResult 一轮归档的结果
+type ArchiveResult struct {
+	Archived int
+	Retrying int
+	Failed   int
+}
+
+// RunOnce 处理一批到期的媒体
+func (s *MediaArchiveService) RunOnce(ctx context.Context, now time.Time) (*ArchiveResult, error) {
+	media, err := s.mediaRepo.ClaimDue(now, s.batchSize, mediaClaimLease)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ArchiveResult{}
+	for _, m := range media {
+		kind := archiver.KindImage
+		if m.Kind == model.NoteMediaVideo {
+			kind = archiver.KindVideo
+		}
+
+		archived, err := s.archiver.Archive(ctx, m.SourceURL, kind, "notes/"+m.UserID)
+		if err == nil {
+			err = s.mediaRepo.MarkArchived(m, archivedMedia(archived), time.Now())
+			if err != nil {
+				return result, err
+			}
+			result.Archived++
+			continue
+		}
+
+		var retryAt *time.Time
+		if !archiver.IsPermanent(err) && m.Attempts+1 < s.maxAttempts {
+			t := time.Now().Add(s.backoff(m.Attempts))
+			retryAt = &t
+			result.Retrying++
+		} else {
+			result.Failed++
+		}
+		log.Printf("[Media] failed to archive %s for note %s (attempt %d): %v", m.SourceURL, m.NoteID, m.Attempts+1, err)
+		if err := s.mediaRepo.MarkFailed(m, err.Error(), retryAt, time.Now()); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// archivedMedia 归档结果转为仓库层记录（哈希按位存为 bigint）
+func archivedMedia(r *archiver.Result) *repository.ArchivedMedia {
+	archived := &repository.ArchivedMedia{URL: r.URL, ContentType: r.ContentType, Size: r.Size}
+	if r.Thumbnail != nil {
+		dhash, ahash := int64(r.Thumbnail.DHash), int64(r.Thumbnail.AHash)
+		archived.ThumbnailURL, archived.DHash, archived.AHash = r.Thumbnail.URL, &dhash, &ahash
+	}
+	return archived
+}
+
+// backoff 第 attempts 次失败后的重试间隔：retryDelay * 2^attempts，不超过 mediaMaxRetryDelay
+func (s *MediaArchiveService) backoff(attempts int) time.Duration {
+	delay := s.retryDelay
+	for i := 0; i < attempts && delay < mediaMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > mediaMaxRetryDelay {
+		delay = mediaMaxRetryDelay
+	}
+	return delay
+}
+
+// StartArchiver 启动后台归档任务：每隔 interval 处理一批，一批处理满时立即继续下一批
+func (s *MediaArchiveService) StartArchiver(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			result, err := s.RunOnce(context.Background(), time.Now())
+			if err != nil {
+				log.Printf("[Media] archive run failed: %v", err)
+			} else if result.Archived+result.Retrying+result.Failed > 0 {
+				log.Printf("[Media] archived %d, retrying %d, failed %d", result.Archived, result.Retrying, result.Failed)
+				if result.Archived+result.Retrying+result.Failed >= s.batchSize {
+					continue
+				}
+			}
+			<-ticker.C
+		}
+	}()
+}
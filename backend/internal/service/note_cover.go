@@ -0,0 +1,137 @@
+package service
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/keenchase/edit-business/internal/model"
+	"github.com/keenchase/edit-business/internal/phash"
+	"github.com/keenchase/edit-business/internal/repository"
+)
+
+// 封面相似度阈值：dHash 和 aHash 的汉明距离都不超过阈值视为同一张封面
+const (
+	DefaultCoverDistance = 10
+	MaxCoverDistance     = 20
+	maxSimilarCovers     = 50
+)
+
+var ErrInvalidCoverDistance = errors.New("maxDistance must be between 0 and 20")
+
+// SimilarCover 封面相似的笔记
+type SimilarCover struct {
+	Note          *model.Note `json:"note"`
+	Distance      int         `json:"distance"`      // dHash 汉明距离
+	AHashDistance int         `json:"ahashDistance"` // aHash 汉明距离
+}
+
+// SimilarCoversResponse 相似封面查询响应；封面尚未归档或无法解码时 coverHashed 为 false
+type SimilarCoversResponse struct {
+	NoteID      string          `json:"noteId"`
+	CoverHashed bool            `json:"coverHashed"`
+	MaxDistance int             `json:"maxDistance"`
+	Similar     []*SimilarCover `json:"similar"`
+}
+
+// coverMatch 封面哈希比较结果
+type coverMatch struct {
+	id            string
+	distance      int
+	ahashDistance int
+}
+
+// matchCovers 在 hashes 中查找与 (dhash, ahash) 相似的笔记（不含 selfID），按距离升序
+func matchCovers(hashes []*repository.CoverHash, selfID string, dhash, ahash int64, maxDistance int) []coverMatch {
+	var matches []coverMatch
+	for _, h := range hashes {
+		if h.ID == selfID {
+			continue
+		}
+		d := phash.Distance(uint64(h.CoverDHash), uint64(dhash))
+		a := phash.Distance(uint64(h.CoverAHash), uint64(ahash))
+		if d <= maxDistance && a <= maxDistance {
+			matches = append(matches, coverMatch{id: h.ID, distance: d, ahashDistance: a})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].ahashDistance < matches[j].ahashDistance
+	})
+	return matches
+}
+
+// SimilarCovers 查找与笔记封面相似的其他笔记（校验归属），最多返回 50 条
+func (s *NoteService) SimilarCovers(authCenterUserID, id string, maxDistance int) (*SimilarCoversResponse, error) {
+	if maxDistance < 0 || maxDistance > MaxCoverDistance {
+		return nil, ErrInvalidCoverDistance
+	}
+	note, err := s.GetByID(authCenterUserID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &SimilarCoversResponse{NoteID: note.ID, MaxDistance: maxDistance, Similar: []*SimilarCover{}}
+	if note.CoverDHash == nil || note.CoverAHash == nil {
+		return resp, nil
+	}
+	resp.CoverHashed = true
+
+	hashes, err := s.noteRepo.ListCoverHashes(note.UserID)
+	if err != nil {
+		return nil, err
+	}
+	matches := matchCovers(hashes, note.ID, *note.CoverDHash, *note.CoverAHash, maxDistance)
+	if len(matches) > maxSimilarCovers {
+		matches = matches[:maxSimilarCovers]
+	}
+
+	ids := make([]string, len(matches))
+	for i, m := range matches {
+		ids[i] = m.id
+	}
+	notes, err := s.noteRepo.GetByIDs(note.UserID, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*model.Note, len(notes))
+	for _, n := range notes {
+		byID[n.ID] = n
+	}
+	for _, m := range matches {
+		if n, ok := byID[m.id]; ok {
+			resp.Similar = append(resp.Similar, &SimilarCover{Note: n, Distance: m.distance, AHashDistance: m.ahashDistance})
+		}
+	}
+	return resp, nil
+}
+
+// duplicateCoverCounts 列表中每条笔记有多少其他笔记的封面与之相似（按默认阈值）
+func (s *NoteService) duplicateCoverCounts(userID string, notes []*model.Note) (map[string]int, error) {
+	counts := make(map[string]int)
+	hashed := false
+	for _, n := range notes {
+		if n.CoverDHash != nil && n.CoverAHash != nil {
+			hashed = true
+			break
+		}
+	}
+	if !hashed {
+		return counts, nil
+	}
+
+	hashes, err := s.noteRepo.ListCoverHashes(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range notes {
+		if n.CoverDHash == nil || n.CoverAHash == nil {
+			continue
+		}
+		if matches := matchCovers(hashes, n.ID, *n.CoverDHash, *n.CoverAHash, DefaultCoverDistance); len(matches) > 0 {
+			counts[n.ID] = len(matches)
+		}
+	}
+	return counts, nil
+}
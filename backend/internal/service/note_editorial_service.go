@@ -0,0 +1,123 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/keenchase/edit-business/internal/model"
+	"github.com/keenchase/edit-business/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// NoteEditorialService 笔记编辑信息服务（流程状态、星级、私有备注）
+type NoteEditorialService struct {
+	editorialRepo   *repository.NoteEditorialRepository
+	noteRepo        *repository.NoteRepository
+	settingsService *UserSettingsService
+}
+
+// NewNoteEditorialService 创建笔记编辑信息服务实例
+func NewNoteEditorialService(editorialRepo *repository.NoteEditorialRepository, noteRepo *repository.NoteRepository, settingsService *UserSettingsService) *NoteEditorialService {
+	return &NoteEditorialService{
+		editorialRepo:   editorialRepo,
+		noteRepo:        noteRepo,
+		settingsService: settingsService,
+	}
+}
+
+// NoteEditorialResponse 编辑信息 + 状态变更记录
+type NoteEditorialResponse struct {
+	*model.NoteEditorial
+	History []*model.NoteStatusChange `json:"history"`
+}
+
+// ThroughputResponse 编辑流程处理量统计响应
+type ThroughputResponse struct {
+	Days   []*repository.StatusThroughput `json:"days"`   // 每天进入各状态的次数
+	Totals map[string]int64               `json:"totals"` // 时间范围内进入各状态的总次数
+}
+
+// noteEditorialPatchFields 编辑信息可编辑字段，null 恢复默认值
+var noteEditorialPatchFields = map[string]patchField{
+	"status":  enumPatchField("status", model.NoteStatuses, model.NoteStatusInbox),
+	"rating":  intPatchField("rating", 0, 5, 0),
+	"remarks": stringPatchField("remarks", 10000),
+}
+
+// Get 获取笔记的编辑信息和状态变更记录（校验归属）
+func (s *NoteEditorialService) Get(authCenterUserID, noteID string) (*NoteEditorialResponse, error) {
+	userID, err := s.ownedNote(authCenterUserID, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	editorial, err := s.editorialRepo.Get(userID, noteID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.editorialRepo.ListStatusChanges(userID, noteID)
+	if err != nil {
+		return nil, err
+	}
+	return &NoteEditorialResponse{NoteEditorial: editorial, History: history}, nil
+}
+
+// Patch 部分更新笔记的编辑信息（JSON Merge Patch，校验归属），状态变化时记录变更时间
+func (s *NoteEditorialService) Patch(authCenterUserID, noteID string, patch map[string]json.RawMessage) (*model.NoteEditorial, error) {
+	userID, err := s.ownedNote(authCenterUserID, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates, err := buildPatch(patch, noteEditorialPatchFields)
+	if err != nil {
+		return nil, err
+	}
+	if len(updates) == 0 {
+		return s.editorialRepo.Get(userID, noteID)
+	}
+	return s.editorialRepo.Update(userID, noteID, updates, time.Now())
+}
+
+// Throughput 按天统计进入各编辑状态的次数（按用户隔离）
+func (s *NoteEditorialService) Throughput(authCenterUserID string, from, to time.Time) (*ThroughputResponse, error) {
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := s.editorialRepo.Throughput(user.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]int64, len(model.NoteStatuses))
+	for _, status := range model.NoteStatuses {
+		totals[status] = 0
+	}
+	for _, d := range days {
+		totals[d.Status] += d.Count
+	}
+	return &ThroughputResponse{Days: days, Totals: totals}, nil
+}
+
+// ownedNote 校验笔记属于当前用户，返回用户 ID
+func (s *NoteEditorialService) ownedNote(authCenterUserID, noteID string) (string, error) {
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return "", err
+	}
+	note, err := s.noteRepo.GetByID(noteID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrNoteNotFound
+		}
+		return "", err
+	}
+	if note.UserID != user.ID {
+		return "", ErrNoteNotFound
+	}
+	return user.ID, nil
+}
@@ -1,10 +1,23 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
-
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/keenchase/edit-business/internal/export"
 	"github.com/keenchase/edit-business/internal/model"
 	"github.com/keenchase/edit-business/internal/repository"
+	"github.com/keenchase/edit-business/internal/search"
+	"github.com/keenchase/edit-business/internal/xhsurl"
+
+	"gorm.io/gorm"
 )
 
 var ErrNoteNotFound = errors.New("note not found")
@@ -12,14 +25,29 @@ var ErrNoteNotFound = errors.New("note not found")
 // NoteService 笔记服务
 type NoteService struct {
 	noteRepo         *repository.NoteRepository
+	metricRepo       *repository.NoteMetricRepository
 	settingsService  *UserSettingsService
+	collectionRepo   *repository.CollectionRepository
+	editorialRepo    *repository.NoteEditorialRepository
+	urlResolver      *xhsurl.Resolver
 }
 
+// 采集时解析 xhslink.com 短链：单条的超时，以及批量接口并发解析的并发数和总超时
+const (
+	shortLinkTimeout      = 3 * time.Second
+	shortLinkWorkers      = 8
+	batchShortLinkTimeout = 8 * time.Second
+)
+
 // NewNoteService 创建笔记服务实例
-func NewNoteService(noteRepo *repository.NoteRepository, settingsService *UserSettingsService) *NoteService {
+func NewNoteService(noteRepo *repository.NoteRepository, metricRepo *repository.NoteMetricRepository, settingsService *UserSettingsService, collectionRepo *repository.CollectionRepository, editorialRepo *repository.NoteEditorialRepository) *NoteService {
 	return &NoteService{
 		noteRepo:        noteRepo,
+		metricRepo:      metricRepo,
 		settingsService: settingsService,
+		collectionRepo:  collectionRepo,
+		editorialRepo:   editorialRepo,
+		urlResolver:     xhsurl.NewResolver(shortLinkTimeout),
 	}
 }
 
@@ -28,6 +56,7 @@ type CreateNoteRequest struct {
 	URL              string   `json:"url" binding:"required"`
 	Title            string   `json:"title"`
 	Author           string   `json:"author"`
+	AuthorXhsID      string   `json:"authorXhsId"` // 作者的小红书用户 ID，用于关联博主
 	Content          string   `json:"content"`
 	Tags             []string `json:"tags"`
 	ImageURLs        []string `json:"imageUrls"`
@@ -41,36 +70,61 @@ type CreateNoteRequest struct {
 	PublishDate      int64    `json:"publishDate"`
 	Source           string   `json:"source"` // 'single' or 'batch'
 	CaptureTimestamp int64    `json:"captureTimestamp" binding:"required"`
+	CollectionID     string   `json:"collectionId"` // 采集后直接加入该收藏夹（可选）
 }
 
 // ListNotesRequest 列表查询请求
+// 筛选条件可任意组合（source / author / tags / noteType / 数值与时间范围等）
 type ListNotesRequest struct {
-	Page   int      `form:"page" binding:"min=1"`
-	Size   int      `form:"size" binding:"min=1,max=100"`
-	Author string   `form:"author"`
-	Tags   []string `form:"tags"`
-	Source string   `form:"source"` // 'single' or 'batch' or empty for all
+	Page  int    `form:"page" binding:"min=1"`
+	Size  int    `form:"size" binding:"min=1,max=100"`
+	Sort  string `form:"sort"`  // 排序字段：likes / collects / comments / publishDate / captureTimestamp / createdAt / updatedAt
+	Order string `form:"order"` // asc / desc，默认 desc
+	repository.NoteFilter
+
+	// 游标分页：UseCursor 为 true 时忽略 Page，按 Cursor 继续翻页（空字符串表示第一页）
+	UseCursor bool   `form:"-"`
+	Cursor    string `form:"cursor"`
+	WithTotal bool   `form:"withTotal"` // 游标模式下是否统计总数
+}
+
+// NoteListItem 列表项：笔记字段 + 互动增长 + 编辑信息
+type NoteListItem struct {
+	*model.Note
+	Growth    *repository.NoteGrowth `json:"growth,omitempty"`
+	Editorial *model.NoteEditorial   `json:"editorial"`
+
+	// DuplicateCover 有其他笔记的封面与本条相似（不同账号转发的同图笔记），数量见 DuplicateCoverCount
+	DuplicateCover      bool `json:"duplicateCover"`
+	DuplicateCoverCount int  `json:"duplicateCoverCount,omitempty"`
 }
 
 // ListNotesResponse 列表查询响应
+// 游标模式下 total 仅在 withTotal=true 时返回，否则为 null
 type ListNotesResponse struct {
-	Notes      []*model.Note `json:"notes"`
-	Total      int64         `json:"total"`
-	Page       int           `json:"page"`
-	Size       int           `json:"size"`
-	TotalPages int           `json:"totalPages"`
+	Notes      []*NoteListItem `json:"notes"`
+	Total      *int64          `json:"total"`
+	Page       int             `json:"page"`
+	Size       int             `json:"size"`
+	TotalPages int             `json:"totalPages"`
+	NextCursor string          `json:"nextCursor,omitempty"`
+	HasMore    bool            `json:"hasMore"`
 }
 
-// Create 创建或更新笔记（智能 Upsert）
-func (s *NoteService) Create(authCenterUserID string, req *CreateNoteRequest) (*model.Note, error) {
-	// 检查用户是否开启了收藏功能
-	enabled, err := s.settingsService.IsCollectionEnabled(authCenterUserID)
+// CreateNoteResponse 采集笔记响应：笔记字段 + 回收站中的同链接笔记（可提示用户恢复）
+type CreateNoteResponse struct {
+	*model.Note
+	TrashedMatch *TrashedMatch `json:"trashedMatch,omitempty"`
+}
+
+// Create 创建或更新笔记（智能 Upsert，按链接中的笔记 ID 去重）
+// 回收站中的笔记不参与去重；如果回收站中有同一篇笔记，在响应中返回以便恢复
+func (s *NoteService) Create(authCenterUserID string, req *CreateNoteRequest) (*CreateNoteResponse, error) {
+	// 检查采集开关和每日上限
+	usage, err := s.settingsService.CheckCollectionLimits(authCenterUserID, QuotaNotes, 1)
 	if err != nil {
 		return nil, err
 	}
-	if !enabled {
-		return nil, ErrCollectionDisabled
-	}
 
 	// Get user ID from auth center user ID
 	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
@@ -78,53 +132,39 @@ func (s *NoteService) Create(authCenterUserID string, req *CreateNoteRequest) (*
 		return nil, err
 	}
 
-	// Determine source based on content presence
-	source := req.Source
-	if source == "" {
-		// Auto-detect source if not specified
-		if req.Content != "" {
-			source = "single"
-		} else {
-			source = "batch"
+	if req.CollectionID != "" {
+		if _, err := findCollection(s.collectionRepo, user.ID, req.CollectionID); err != nil {
+			return nil, err
 		}
 	}
 
-	// 处理图片：批量接口可能只传 image，单篇传 imageUrls
-	imageURLs := req.ImageURLs
-	if len(imageURLs) == 0 && req.Image != "" {
-		imageURLs = []string{req.Image}
-	}
-	coverImageURL := req.CoverImageURL
-	if coverImageURL == "" && len(imageURLs) > 0 {
-		coverImageURL = imageURLs[0]
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), shortLinkTimeout)
+	s.resolveShortLink(ctx, req)
+	cancel()
+	note := newNoteFromRequest(user.ID, req)
 
-	note := &model.Note{
-		UserID:           user.ID,
-		URL:              req.URL,
-		Title:            req.Title,
-		Author:           req.Author,
-		Content:          req.Content,
-		Tags:             req.Tags,
-		ImageURLs:        imageURLs,
-		VideoURL:         req.VideoURL,
-		NoteType:         req.NoteType,
-		CoverImageURL:    coverImageURL,
-		Likes:            req.Likes,
-		Collects:         req.Collects,
-		Comments:         req.Comments,
-		PublishDate:      req.PublishDate,
-		Source:           source,
-		CaptureTimestamp: req.CaptureTimestamp,
+	// 每日额度只计新增的笔记，额度用完后仍可重新采集已有笔记
+	if usage.Remaining < 1 {
+		existing, err := s.noteRepo.MatchExisting(user.ID, []*model.Note{note})
+		if err != nil {
+			return nil, err
+		}
+		if !existing[0] {
+			return nil, usage.exceeded()
+		}
 	}
 
 	// Use Upsert to create or update
-	result, err := s.noteRepo.Upsert(note)
+	result, err := s.noteRepo.UpsertToCollection(note, req.CollectionID)
 	if err != nil {
 		return nil, err
 	}
 
-	return result, nil
+	resp := &CreateNoteResponse{Note: result}
+	if trashed, err := s.noteRepo.GetTrashedByIdentity(user.ID, result.XhsNoteID, result.URL); err == nil {
+		resp.TrashedMatch = newTrashedMatch(trashed.ID, trashed.DeletedAt)
+	}
+	return resp, nil
 }
 
 // GetByID 根据 ID 获取笔记（校验归属，防止越权访问）
@@ -160,22 +200,26 @@ func (s *NoteService) List(authCenterUserID string, req *ListNotesRequest) (*Lis
 		req.Size = 20
 	}
 
-	offset := (req.Page - 1) * req.Size
+	if err := validateNoteFilter(&req.NoteFilter); err != nil {
+		return nil, err
+	}
+	sort, err := repository.ParseNoteSort(req.Sort, req.Order)
+	if err != nil {
+		return nil, err
+	}
 
-	var notes []*model.Note
-	var total int64
+	if req.UseCursor {
+		return s.listByCursor(userID, req, sort)
+	}
+
+	offset := (req.Page - 1) * req.Size
 
-	// 根据查询条件选择不同的查询方法（均按用户隔离）
-	if req.Source != "" {
-		notes, total, err = s.noteRepo.ListBySource(userID, req.Source, offset, req.Size)
-	} else if req.Author != "" {
-		notes, total, err = s.noteRepo.ListByAuthor(userID, req.Author, offset, req.Size)
-	} else if len(req.Tags) > 0 {
-		notes, total, err = s.noteRepo.ListByTags(userID, req.Tags, offset, req.Size)
-	} else {
-		notes, total, err = s.noteRepo.List(userID, offset, req.Size)
+	notes, total, err := s.noteRepo.List(userID, &req.NoteFilter, sort, offset, req.Size)
+	if err != nil {
+		return nil, err
 	}
 
+	items, err := s.listItems(userID, notes)
 	if err != nil {
 		return nil, err
 	}
@@ -186,7 +230,218 @@ func (s *NoteService) List(authCenterUserID string, req *ListNotesRequest) (*Lis
 	}
 
 	return &ListNotesResponse{
-		Notes:      notes,
+		Notes:      items,
+		Total:      &total,
+		Page:       req.Page,
+		Size:       req.Size,
+		TotalPages: totalPages,
+		HasMore:    req.Page < totalPages,
+	}, nil
+}
+
+// ErrInvalidNoteFilter 筛选条件的取值无效（列表、导出和批量操作共用）
+var ErrInvalidNoteFilter = errors.New("invalid note filter")
+
+// validateNoteFilter 校验筛选条件中的枚举取值，tagMode 为空时按 any 处理
+func validateNoteFilter(f *repository.NoteFilter) error {
+	for _, status := range f.Statuses {
+		if !model.IsValidNoteStatus(status) {
+			return fmt.Errorf("%w: invalid status %q", ErrInvalidNoteFilter, status)
+		}
+	}
+	if f.TagMode == "" {
+		f.TagMode = repository.TagModeAny
+	}
+	if f.TagMode != repository.TagModeAny && f.TagMode != repository.TagModeAll {
+		return fmt.Errorf("%w: invalid tagMode %q", ErrInvalidNoteFilter, f.TagMode)
+	}
+	return nil
+}
+
+// listByCursor 游标分页（按排序字段 + id 定位，同步过程中翻页不会错位）
+func (s *NoteService) listByCursor(userID string, req *ListNotesRequest, sort repository.Sort) (*ListNotesResponse, error) {
+	notes, nextCursor, err := s.noteRepo.ListByCursor(userID, &req.NoteFilter, sort, req.Cursor, req.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.listItems(userID, notes)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListNotesResponse{
+		Notes:      items,
+		Size:       req.Size,
+		NextCursor: nextCursor,
+		HasMore:    nextCursor != "",
+	}
+	if req.WithTotal {
+		total, err := s.noteRepo.Count(userID, &req.NoteFilter)
+		if err != nil {
+			return nil, err
+		}
+		resp.Total = &total
+	}
+	return resp, nil
+}
+
+// ExportNotes 导出笔记（按用户隔离，筛选/排序与列表接口一致）
+// 先校验参数并返回写出函数，调用方设置好响应头后再执行，数据边读边写
+func (s *NoteService) ExportNotes(authCenterUserID string, req *ListNotesRequest, format export.Format) (func(w io.Writer) error, error) {
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateNoteFilter(&req.NoteFilter); err != nil {
+		return nil, err
+	}
+	sort, err := repository.ParseNoteSort(req.Sort, req.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(w io.Writer) error {
+		writer, err := export.NewNoteWriter(format, w)
+		if err != nil {
+			return err
+		}
+		if err := s.noteRepo.Stream(user.ID, &req.NoteFilter, sort, writer.Write); err != nil {
+			return err
+		}
+		return writer.Close()
+	}, nil
+}
+
+// listItems 为列表中的笔记附加 24h/7d 互动增长、编辑信息和重复封面标记
+func (s *NoteService) listItems(userID string, notes []*model.Note) ([]*NoteListItem, error) {
+	ids := make([]string, len(notes))
+	for i, note := range notes {
+		ids[i] = note.ID
+	}
+	growth, err := s.metricRepo.GetGrowth(userID, ids, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	editorials, err := s.editorialRepo.GetByNoteIDs(userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	duplicates, err := s.duplicateCoverCounts(userID, notes)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*NoteListItem, len(notes))
+	for i, note := range notes {
+		items[i] = &NoteListItem{
+			Note:                note,
+			Growth:              growth[note.ID],
+			Editorial:           editorials[note.ID],
+			DuplicateCover:      duplicates[note.ID] > 0,
+			DuplicateCoverCount: duplicates[note.ID],
+		}
+	}
+	return items, nil
+}
+
+// NoteMetricsResponse 笔记互动时间序列响应
+type NoteMetricsResponse struct {
+	NoteID    string                      `json:"noteId"`
+	Snapshots []*model.NoteMetricSnapshot `json:"snapshots"`
+	Growth    *repository.NoteGrowth      `json:"growth"`
+}
+
+// GetMetrics 获取笔记的互动快照时间序列（校验归属）
+func (s *NoteService) GetMetrics(authCenterUserID, id string, from, to time.Time) (*NoteMetricsResponse, error) {
+	note, err := s.GetByID(authCenterUserID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := s.metricRepo.ListByNoteID(note.UserID, note.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	growth, err := s.metricRepo.GetGrowth(note.UserID, []string{note.ID}, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &NoteMetricsResponse{
+		NoteID:    note.ID,
+		Snapshots: snapshots,
+		Growth:    growth[note.ID],
+	}, nil
+}
+
+// SearchNotesRequest 全文检索请求
+type SearchNotesRequest struct {
+	Q    string `form:"q"`
+	Page int    `form:"page"`
+	Size int    `form:"size"`
+}
+
+// NoteSearchResult 检索结果项：笔记 + 相关度 + 高亮片段
+type NoteSearchResult struct {
+	Note           *model.Note `json:"note"`
+	Score          float64     `json:"score"`
+	TitleHighlight string      `json:"titleHighlight"`
+	Snippet        string      `json:"snippet"`
+}
+
+// SearchNotesResponse 全文检索响应
+type SearchNotesResponse struct {
+	Results    []*NoteSearchResult `json:"results"`
+	Total      int64               `json:"total"`
+	Page       int                 `json:"page"`
+	Size       int                 `json:"size"`
+	TotalPages int                 `json:"totalPages"`
+}
+
+// snippetRunes 正文高亮片段长度（字符数）
+const snippetRunes = 120
+
+// Search 全文检索标题和正文（按用户隔离，按相关度排序）
+func (s *NoteService) Search(authCenterUserID string, req *SearchNotesRequest) (*SearchNotesResponse, error) {
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Size < 1 {
+		req.Size = 20
+	}
+	if req.Size > 100 {
+		req.Size = 100
+	}
+
+	terms := search.QueryTerms(req.Q)
+	hits, total, err := s.noteRepo.Search(user.ID, terms, (req.Page-1)*req.Size, req.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*NoteSearchResult, len(hits))
+	for i, hit := range hits {
+		results[i] = &NoteSearchResult{
+			Note:           hit.Note,
+			Score:          hit.Score,
+			TitleHighlight: search.Highlight(hit.Note.Title, terms, 0),
+			Snippet:        search.Highlight(hit.Note.Content, terms, snippetRunes),
+		}
+	}
+
+	totalPages := int(total) / req.Size
+	if int(total)%req.Size > 0 {
+		totalPages++
+	}
+
+	return &SearchNotesResponse{
+		Results:    results,
 		Total:      total,
 		Page:       req.Page,
 		Size:       req.Size,
@@ -194,89 +449,246 @@ func (s *NoteService) List(authCenterUserID string, req *ListNotesRequest) (*Lis
 	}, nil
 }
 
-// BatchCreate 批量创建或更新笔记（用于 Chrome 插件同步）
-func (s *NoteService) BatchCreate(authCenterUserID string, reqs []*CreateNoteRequest) error {
+// Quota 获取用户当前的采集额度（用于在采集接口的响应头中返回）
+func (s *NoteService) Quota(authCenterUserID string) (*QuotaResponse, error) {
+	return s.settingsService.GetQuota(authCenterUserID)
+}
+
+// BatchCreate 批量创建或更新笔记（用于 Chrome 插件同步），返回每条的写入结果
+// mode 为 atomic 时任一条被拒绝整批不写入；为 bestEffort（默认）时逐条提交
+func (s *NoteService) BatchCreate(authCenterUserID string, reqs []*CreateNoteRequest, mode string) (*BatchResponse, error) {
+	mode, err := normalizeBatchMode(mode)
+	if err != nil {
+		return nil, err
+	}
+	run := newBatchRun(mode, len(reqs))
 	if len(reqs) == 0 {
-		return nil
+		return run.response(), nil
 	}
 
-	// 检查用户是否开启了收藏功能
-	enabled, err := s.settingsService.IsCollectionEnabled(authCenterUserID)
+	// 检查采集开关和单次、每日上限
+	usage, err := s.settingsService.CheckCollectionLimits(authCenterUserID, QuotaNotes, len(reqs))
 	if err != nil {
-		return err
-	}
-	if !enabled {
-		return ErrCollectionDisabled
+		return nil, err
 	}
 
 	// Get user ID from auth center user ID
 	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Process each note with Upsert logic
-	for _, req := range reqs {
-		// Determine source based on content presence
-		source := req.Source
-		if source == "" {
-			// Auto-detect source if not specified
-			if req.Content != "" {
-				source = "single"
-			} else {
-				source = "batch"
+	// 逐条校验，目标收藏夹只查询一次
+	collections := make(map[string]bool)
+	for i, req := range reqs {
+		if reason := validateBatchNote(req); reason != "" {
+			run.reject(i, reason)
+			continue
+		}
+		if req.CollectionID != "" {
+			found, checked := collections[req.CollectionID]
+			if !checked {
+				_, err := findCollection(s.collectionRepo, user.ID, req.CollectionID)
+				if err != nil && !errors.Is(err, ErrCollectionNotFound) {
+					return nil, err
+				}
+				found = err == nil
+				collections[req.CollectionID] = found
+			}
+			if !found {
+				run.reject(i, "collection not found")
+				continue
 			}
 		}
+		run.accept(i)
+	}
+
+	// 短链解析后才能按笔记 ID 判断是否为已有笔记
+	pending := make([]*CreateNoteRequest, len(run.pending))
+	for j, i := range run.pending {
+		pending[j] = reqs[i]
+	}
+	s.resolveShortLinks(pending)
+	notes := make([]*model.Note, len(reqs)) // 按请求下标
+	for j, i := range run.pending {
+		notes[i] = newNoteFromRequest(user.ID, pending[j])
+	}
 
-		// 处理图片：批量接口可能只传 image
-		imageURLs := req.ImageURLs
-		if len(imageURLs) == 0 && req.Image != "" {
-			imageURLs = []string{req.Image}
+	// 每日额度只计新增的笔记，重复采集已有笔记不受限制
+	if len(run.pending) > usage.Remaining {
+		candidates := make([]*model.Note, len(run.pending))
+		for j, i := range run.pending {
+			candidates[j] = notes[i]
 		}
-		coverImageURL := req.CoverImageURL
-		if coverImageURL == "" && len(imageURLs) > 0 {
-			coverImageURL = imageURLs[0]
+		existing, err := s.noteRepo.MatchExisting(user.ID, candidates)
+		if err != nil {
+			return nil, err
 		}
+		run.limit(usage.Remaining, existing, "daily limit exceeded")
+	}
 
-		note := &model.Note{
-			UserID:           user.ID,
-			URL:              req.URL,
-			Title:            req.Title,
-			Author:           req.Author,
-			Content:          req.Content,
-			Tags:             req.Tags,
-			ImageURLs:        imageURLs,
-			VideoURL:         req.VideoURL,
-			NoteType:         req.NoteType,
-			CoverImageURL:    coverImageURL,
-			Likes:            req.Likes,
-			Collects:         req.Collects,
-			Comments:         req.Comments,
-			PublishDate:      req.PublishDate,
-			Source:           source,
-			CaptureTimestamp: req.CaptureTimestamp,
+	if run.shouldWrite() {
+		items := make([]*repository.NoteBatchItem, len(run.pending))
+		for j, i := range run.pending {
+			items[j] = &repository.NoteBatchItem{
+				Note:         notes[i],
+				CollectionID: reqs[i].CollectionID,
+			}
 		}
+		if err := run.apply(s.noteRepo.UpsertBatch(items, mode == BatchModeAtomic)); err != nil {
+			return nil, err
+		}
+	}
 
-		// Use Upsert for each note
-		_, err := s.noteRepo.Upsert(note)
-		if err != nil {
-			return err
+	return run.response(), nil
+}
+
+// validateBatchNote 校验批量接口中的单条笔记，返回拒绝原因
+func validateBatchNote(req *CreateNoteRequest) string {
+	if req == nil {
+		return "empty item"
+	}
+	if reason := validateURL(req.URL, true); reason != "" {
+		return "url: " + reason
+	}
+	if req.CaptureTimestamp <= 0 {
+		return "captureTimestamp: required"
+	}
+	return ""
+}
+
+// resolveShortLink 把 xhslink.com 短链替换为跳转后的笔记链接，以便按笔记 ID 去重
+// 解析失败或 ctx 超时时保留短链（按链接去重）
+func (s *NoteService) resolveShortLink(ctx context.Context, req *CreateNoteRequest) {
+	if !xhsurl.IsShortLink(req.URL) {
+		return
+	}
+	resolved, err := s.urlResolver.Resolve(ctx, req.URL)
+	if err != nil {
+		log.Printf("[Note] failed to resolve short link %s: %v", req.URL, err)
+		return
+	}
+	req.URL = resolved
+}
+
+// resolveShortLinks 批量解析短链：最多 shortLinkWorkers 条并发，整批不超过 batchShortLinkTimeout，
+// 避免一批短链逐条解析时长时间占用请求；超时未解析完的保留短链
+func (s *NoteService) resolveShortLinks(reqs []*CreateNoteRequest) {
+	var short []*CreateNoteRequest
+	for _, req := range reqs {
+		if xhsurl.IsShortLink(req.URL) {
+			short = append(short, req)
 		}
 	}
+	if len(short) == 0 {
+		return
+	}
 
-	return nil
+	ctx, cancel := context.WithTimeout(context.Background(), batchShortLinkTimeout)
+	defer cancel()
+
+	jobs := make(chan *CreateNoteRequest)
+	var wg sync.WaitGroup
+	for w := 0; w < shortLinkWorkers && w < len(short); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for req := range jobs {
+				s.resolveShortLink(ctx, req)
+			}
+		}()
+	}
+	for _, req := range short {
+		jobs <- req
+	}
+	close(jobs)
+	wg.Wait()
 }
 
-// Update 更新笔记（校验归属）
-func (s *NoteService) Update(authCenterUserID string, note *model.Note) error {
-	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+// newNoteFromRequest 把创建请求转为笔记模型（插件单条、批量与文件导入共用）
+func newNoteFromRequest(userID string, req *CreateNoteRequest) *model.Note {
+	// Determine source based on content presence
+	source := req.Source
+	if source == "" {
+		// Auto-detect source if not specified
+		if req.Content != "" {
+			source = "single"
+		} else {
+			source = "batch"
+		}
+	}
+
+	// 处理图片：批量接口可能只传 image，单篇传 imageUrls
+	imageURLs := req.ImageURLs
+	if len(imageURLs) == 0 && req.Image != "" {
+		imageURLs = []string{req.Image}
+	}
+	coverImageURL := req.CoverImageURL
+	if coverImageURL == "" && len(imageURLs) > 0 {
+		coverImageURL = imageURLs[0]
+	}
+
+	return &model.Note{
+		UserID:           userID,
+		URL:              req.URL,
+		Title:            req.Title,
+		Author:           req.Author,
+		AuthorXhsID:      req.AuthorXhsID,
+		Content:          req.Content,
+		Tags:             req.Tags,
+		ImageURLs:        imageURLs,
+		VideoURL:         req.VideoURL,
+		NoteType:         req.NoteType,
+		CoverImageURL:    coverImageURL,
+		Likes:            req.Likes,
+		Collects:         req.Collects,
+		Comments:         req.Comments,
+		PublishDate:      req.PublishDate,
+		Source:           source,
+		CaptureTimestamp: req.CaptureTimestamp,
+	}
+}
+
+// notePatchFields 笔记可编辑字段（API 字段名 -> 列），互动数据、链接和采集信息只能由采集写入
+// 封面、图片和视频链接同样只能由采集写入：归档副本和封面感知哈希依赖这些链接，手动修改会使其失效
+var notePatchFields = map[string]patchField{
+	"title":       stringPatchField("title", 500),
+	"author":      stringPatchField("author", 100),
+	"authorXhsId": stringPatchField("author_xhs_id", 50),
+	"content":     stringPatchField("content", 100000),
+	"tags":        stringListPatchField("tags"),
+	"noteType":    stringPatchField("note_type", 20),
+}
+
+// Patch 部分更新笔记（JSON Merge Patch，校验归属）
+// ifMatch 为客户端持有的版本号，与当前版本不一致时返回 repository.ErrVersionConflict；
+// 为 nil 时不做校验（客户端显式发送 If-Match: *；缺失 If-Match 的请求已在 handler 拒绝）
+func (s *NoteService) Patch(authCenterUserID, id string, patch map[string]json.RawMessage, ifMatch *int64) (*model.Note, error) {
+	note, err := s.GetByID(authCenterUserID, id)
 	if err != nil {
-		return err
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoteNotFound
+		}
+		return nil, err
 	}
-	if note.UserID != user.ID {
-		return ErrNoteNotFound // 不属于当前用户
+
+	updates, err := buildPatch(patch, notePatchFields)
+	if err != nil {
+		return nil, err
+	}
+
+	version := note.Version
+	if ifMatch != nil {
+		if *ifMatch != note.Version {
+			return nil, repository.ErrVersionConflict
+		}
+		version = *ifMatch
 	}
-	return s.noteRepo.Update(note)
+	if len(updates) == 0 {
+		return note, nil
+	}
+
+	return s.noteRepo.Patch(note.UserID, note.ID, version, updates)
 }
 
 // Delete 删除笔记（校验归属）
@@ -287,3 +699,144 @@ func (s *NoteService) Delete(authCenterUserID, id string) error {
 	}
 	return s.noteRepo.Delete(user.ID, id)
 }
+
+// maxBulkNotes 单次批量操作最多处理的笔记数
+const maxBulkNotes = 1000
+
+var (
+	ErrInvalidBulk  = errors.New("invalid bulk request")
+	ErrBulkTooLarge = repository.ErrBulkTooLarge
+)
+
+// BulkNotesRequest 笔记批量操作请求，ids 与 filter 二选一
+type BulkNotesRequest struct {
+	IDs    []string               `json:"ids"`
+	Filter *repository.NoteFilter `json:"filter"`
+	Action string                 `json:"action"` // delete / addTags / removeTags / setField / moveToCollection
+	Tags   []string               `json:"tags"`   // addTags / removeTags 使用
+	Field  string                 `json:"field"`  // setField 使用，取值同 PUT /notes/:id 的可编辑字段
+	Value  json.RawMessage        `json:"value"`  // setField 使用，null 表示清空
+
+	CollectionID string `json:"collectionId"` // moveToCollection 使用：移出其他收藏夹并加入该收藏夹
+}
+
+// BulkNotesResponse 笔记批量操作响应
+type BulkNotesResponse struct {
+	Action  string                       `json:"action"`
+	Results []*repository.BulkItemResult `json:"results"`
+	Summary map[string]int               `json:"summary"` // 状态 -> 数量
+}
+
+// Bulk 批量操作笔记（同一事务，校验归属）
+// 按 ID 操作时不属于当前用户的笔记返回 notFound；按筛选条件操作时命中超过上限返回 ErrBulkTooLarge
+func (s *NoteService) Bulk(authCenterUserID string, req *BulkNotesRequest) (*BulkNotesResponse, error) {
+	sel, err := bulkSelection(req)
+	if err != nil {
+		return nil, err
+	}
+	action, err := bulkAction(req)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+	if action.Kind == repository.BulkMoveToCollection {
+		if _, err := findCollection(s.collectionRepo, user.ID, action.CollectionID); err != nil {
+			return nil, err
+		}
+	}
+
+	results, err := s.noteRepo.Bulk(user.ID, sel, action)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := make(map[string]int)
+	for _, r := range results {
+		summary[r.Status]++
+	}
+	return &BulkNotesResponse{Action: req.Action, Results: results, Summary: summary}, nil
+}
+
+// bulkSelection 校验并生成批量操作目标
+func bulkSelection(req *BulkNotesRequest) (repository.BulkSelection, error) {
+	sel := repository.BulkSelection{Limit: maxBulkNotes}
+	switch {
+	case len(req.IDs) > 0 && req.Filter != nil:
+		return sel, fmt.Errorf("%w: ids and filter are mutually exclusive", ErrInvalidBulk)
+	case req.Filter != nil:
+		if req.Filter.IsEmpty() {
+			return sel, fmt.Errorf("%w: filter must not be empty", ErrInvalidBulk)
+		}
+		if err := validateNoteFilter(req.Filter); err != nil {
+			return sel, fmt.Errorf("%w: %v", ErrInvalidBulk, err)
+		}
+		sel.Filter = req.Filter
+		return sel, nil
+	case len(req.IDs) == 0:
+		return sel, fmt.Errorf("%w: ids or filter is required", ErrInvalidBulk)
+	}
+
+	seen := make(map[string]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		sel.IDs = append(sel.IDs, id)
+	}
+	if len(sel.IDs) == 0 {
+		return sel, fmt.Errorf("%w: ids is empty", ErrInvalidBulk)
+	}
+	if len(sel.IDs) > maxBulkNotes {
+		return sel, ErrBulkTooLarge
+	}
+	return sel, nil
+}
+
+// bulkAction 校验并生成批量操作
+func bulkAction(req *BulkNotesRequest) (repository.BulkAction, error) {
+	action := repository.BulkAction{Kind: req.Action}
+	switch req.Action {
+	case repository.BulkDelete:
+		return action, nil
+
+	case repository.BulkAddTags, repository.BulkRemoveTags:
+		seen := make(map[string]bool, len(req.Tags))
+		for _, tag := range req.Tags {
+			tag = strings.TrimSpace(tag)
+			if tag == "" || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			action.Tags = append(action.Tags, tag)
+		}
+		if len(action.Tags) == 0 {
+			return action, fmt.Errorf("%w: tags is required", ErrInvalidBulk)
+		}
+		return action, nil
+
+	case repository.BulkSetField:
+		if len(req.Value) == 0 {
+			return action, fmt.Errorf("%w: value is required", ErrInvalidBulk)
+		}
+		updates, err := buildPatch(map[string]json.RawMessage{req.Field: req.Value}, notePatchFields)
+		if err != nil {
+			return action, fmt.Errorf("%w: %v", ErrInvalidBulk, err)
+		}
+		action.Updates = updates
+		return action, nil
+
+	case repository.BulkMoveToCollection:
+		action.CollectionID = strings.TrimSpace(req.CollectionID)
+		if action.CollectionID == "" {
+			return action, fmt.Errorf("%w: collectionId is required", ErrInvalidBulk)
+		}
+		return action, nil
+	}
+	return action, fmt.Errorf("%w: unknown action %q", ErrInvalidBulk, req.Action)
+}
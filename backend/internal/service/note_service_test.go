@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/keenchase/edit-business/internal/dbtest"
+	"github.com/keenchase/edit-business/internal/model"
+	"github.com/keenchase/edit-business/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// testServices 连接同一个测试 schema 的服务
+type testServices struct {
+	db       *gorm.DB
+	settings *UserSettingsService
+	notes    *NoteService
+	bloggers *BloggerService
+}
+
+func newTestServices(t *testing.T) *testServices {
+	db := dbtest.Open(t)
+	userRepo := repository.NewUserRepository(db)
+	noteRepo := repository.NewNoteRepository(db)
+	bloggerRepo := repository.NewBloggerRepository(db)
+	settings := NewUserSettingsService(repository.NewUserSettingsRepository(db), userRepo, noteRepo, bloggerRepo)
+	return &testServices{
+		db:       db,
+		settings: settings,
+		notes: NewNoteService(noteRepo, repository.NewNoteMetricRepository(db), settings,
+			repository.NewCollectionRepository(db), repository.NewNoteEditorialRepository(db)),
+		bloggers: NewBloggerService(bloggerRepo, repository.NewBloggerMetricRepository(db), settings, noteRepo),
+	}
+}
+
+func testNoteRequest(xhsNoteID string, likes int32) *CreateNoteRequest {
+	return &CreateNoteRequest{
+		URL:              "https://www.xiaohongshu.com/explore/" + xhsNoteID,
+		Title:            "note " + xhsNoteID,
+		Likes:            likes,
+		CaptureTimestamp: time.Now().UnixMilli(),
+	}
+}
+
+func TestNoteCreateAtDailyLimitStillUpdatesExistingNotes(t *testing.T) {
+	s := newTestServices(t)
+	dbtest.CreateUser(t, s.db, "user-quota", &model.UserSettings{
+		CollectionEnabled:    true,
+		CollectionDailyLimit: 1,
+		CollectionBatchLimit: 10,
+	})
+
+	const existingID, newID = "65a1b2c3d4e5f60718293a4b", "65a1b2c3d4e5f60718293a4c"
+	first, err := s.notes.Create("user-quota", testNoteRequest(existingID, 1))
+	if err != nil {
+		t.Fatalf("first capture: %v", err)
+	}
+
+	// 额度已用完：重新采集同一篇笔记仍然更新成功
+	again, err := s.notes.Create("user-quota", testNoteRequest(existingID, 2))
+	if err != nil {
+		t.Fatalf("re-capture at the limit: %v", err)
+	}
+	if again.ID != first.ID || again.Likes != 2 {
+		t.Errorf("re-capture = %s likes %d, want %s likes 2", again.ID, again.Likes, first.ID)
+	}
+
+	// 新笔记被拒绝
+	_, err = s.notes.Create("user-quota", testNoteRequest(newID, 1))
+	var quotaErr *QuotaError
+	if !errors.As(err, &quotaErr) || !errors.Is(err, ErrDailyLimitExceeded) || quotaErr.Limit != 1 {
+		t.Fatalf("new note at the limit err = %v, want daily limit exceeded", err)
+	}
+
+	// 批量：已有笔记更新，新笔记因额度被拒绝
+	resp, err := s.notes.BatchCreate("user-quota", []*CreateNoteRequest{
+		testNoteRequest(newID, 1),
+		testNoteRequest(existingID, 3),
+	}, BatchModeBestEffort)
+	if err != nil {
+		t.Fatalf("batch at the limit: %v", err)
+	}
+	if r := resp.Results[0]; r.Status != BatchItemRejected || r.Reason != "daily limit exceeded" {
+		t.Errorf("new note result = %+v, want rejected by daily limit", r)
+	}
+	if r := resp.Results[1]; r.Status != BatchItemUpdated || r.ID != first.ID {
+		t.Errorf("existing note result = %+v, want updated %s", r, first.ID)
+	}
+
+	quota, err := s.settings.GetQuota("user-quota")
+	if err != nil {
+		t.Fatalf("quota: %v", err)
+	}
+	if quota.Notes.Used != 1 || quota.Notes.Remaining != 0 {
+		t.Errorf("notes quota = %+v, want used 1 remaining 0", quota.Notes)
+	}
+}
+
+func TestBloggerUpsertAtDailyLimitStillUpdatesExistingBloggers(t *testing.T) {
+	s := newTestServices(t)
+	dbtest.CreateUser(t, s.db, "user-quota", &model.UserSettings{
+		CollectionEnabled:    true,
+		CollectionDailyLimit: 1,
+		CollectionBatchLimit: 10,
+	})
+
+	req := func(xhsID string, followers int32) *CreateBloggerRequest {
+		return &CreateBloggerRequest{XhsID: xhsID, BloggerName: "blogger " + xhsID, FollowersCount: followers,
+			CaptureTimestamp: time.Now().UnixMilli()}
+	}
+	if _, err := s.bloggers.UpsertByXhsID("user-quota", req("xhs-1", 10)); err != nil {
+		t.Fatalf("first capture: %v", err)
+	}
+	if _, err := s.bloggers.UpsertByXhsID("user-quota", req("xhs-1", 20)); err != nil {
+		t.Fatalf("re-capture at the limit: %v", err)
+	}
+	if _, err := s.bloggers.UpsertByXhsID("user-quota", req("xhs-2", 10)); !errors.Is(err, ErrDailyLimitExceeded) {
+		t.Fatalf("new blogger at the limit err = %v, want daily limit exceeded", err)
+	}
+
+	resp, err := s.bloggers.BatchCreate("user-quota", []*CreateBloggerRequest{req("xhs-1", 30), req("xhs-2", 10)}, BatchModeBestEffort)
+	if err != nil {
+		t.Fatalf("batch at the limit: %v", err)
+	}
+	if resp.Results[0].Status != BatchItemUpdated || resp.Results[1].Status != BatchItemRejected {
+		t.Errorf("results = %+v, %+v, want updated then rejected", resp.Results[0], resp.Results[1])
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lib/pq"
+)
+
+// ErrInvalidPatch 编辑请求包含不可编辑字段或取值非法
+var ErrInvalidPatch = errors.New("invalid patch")
+
+// patchField 可编辑字段：数据库列名 + 取值解析（raw 为 null 时表示清空）
+type patchField struct {
+	column string
+	decode func(raw json.RawMessage) (interface{}, error)
+}
+
+func isJSONNull(raw json.RawMessage) bool {
+	return strings.TrimSpace(string(raw)) == "null"
+}
+
+// stringPatchField 字符串字段，null 清空为空串
+func stringPatchField(column string, maxRunes int) patchField {
+	return patchField{column: column, decode: func(raw json.RawMessage) (interface{}, error) {
+		if isJSONNull(raw) {
+			return "", nil
+		}
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, errors.New("must be a string")
+		}
+		if utf8.RuneCountInString(s) > maxRunes {
+			return nil, fmt.Errorf("must be at most %d characters", maxRunes)
+		}
+		return s, nil
+	}}
+}
+
+// stringListPatchField 字符串数组字段，整体替换；去除空项与重复项，null 清空
+func stringListPatchField(column string) patchField {
+	return patchField{column: column, decode: func(raw json.RawMessage) (interface{}, error) {
+		list := pq.StringArray{}
+		if isJSONNull(raw) {
+			return list, nil
+		}
+		var items []string
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, errors.New("must be an array of strings")
+		}
+		seen := make(map[string]bool, len(items))
+		for _, item := range items {
+			item = strings.TrimSpace(item)
+			if item == "" || seen[item] {
+				continue
+			}
+			seen[item] = true
+			list = append(list, item)
+		}
+		return list, nil
+	}}
+}
+
+// buildPatch 按 JSON Merge Patch（RFC 7386）语义生成更新列
+// 出现的字段整体覆盖，值为 null 表示清空，未出现的字段保持不变；白名单以外的字段直接拒绝
+func buildPatch(patch map[string]json.RawMessage, fields map[string]patchField) (map[string]interface{}, error) {
+	updates := make(map[string]interface{}, len(patch))
+	for name, raw := range patch {
+		field, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: field %q is not editable", ErrInvalidPatch, name)
+		}
+		v, err := field.decode(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s %v", ErrInvalidPatch, name, err)
+		}
+		updates[field.column] = v
+	}
+	return updates, nil
+}
+
+// intPatchField 整数字段，取值范围 [min, max]，null 重置为 nullValue
+func intPatchField(column string, min, max, nullValue int) patchField {
+	return patchField{column: column, decode: func(raw json.RawMessage) (interface{}, error) {
+		if isJSONNull(raw) {
+			return nullValue, nil
+		}
+		var n int
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return nil, errors.New("must be an integer")
+		}
+		if n < min || n > max {
+			return nil, fmt.Errorf("must be between %d and %d", min, max)
+		}
+		return n, nil
+	}}
+}
+
+// enumPatchField 枚举字段，只接受 allowed 中的取值，null 重置为 nullValue
+func enumPatchField(column string, allowed []string, nullValue string) patchField {
+	return patchField{column: column, decode: func(raw json.RawMessage) (interface{}, error) {
+		if isJSONNull(raw) {
+			return nullValue, nil
+		}
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			for _, v := range allowed {
+				if v == s {
+					return s, nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("must be one of %s", strings.Join(allowed, ", "))
+	}}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+var testPatchFields = map[string]patchField{
+	"title":    stringPatchField("title", 5),
+	"tags":     stringListPatchField("tags"),
+	"priority": intPatchField("priority", 0, 3, 1),
+	"status":   enumPatchField("status", []string{"todo", "done"}, "todo"),
+}
+
+func TestBuildPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   string
+		want    map[string]interface{}
+		wantErr string
+	}{
+		{"empty patch", `{}`, map[string]interface{}{}, ""},
+		{"set string", `{"title":"标题"}`, map[string]interface{}{"title": "标题"}, ""},
+		{"null clears string", `{"title":null}`, map[string]interface{}{"title": ""}, ""},
+		{"string too long", `{"title":"123456"}`, nil, "title must be at most 5 characters"},
+		{"string wrong type", `{"title":1}`, nil, "title must be a string"},
+		{"list trims and dedupes", `{"tags":[" a ","b","a",""]}`, map[string]interface{}{"tags": pq.StringArray{"a", "b"}}, ""},
+		{"null clears list", `{"tags":null}`, map[string]interface{}{"tags": pq.StringArray{}}, ""},
+		{"list wrong type", `{"tags":"a,b"}`, nil, "tags must be an array of strings"},
+		{"int in range", `{"priority":3}`, map[string]interface{}{"priority": 3}, ""},
+		{"null resets int", `{"priority":null}`, map[string]interface{}{"priority": 1}, ""},
+		{"int out of range", `{"priority":4}`, nil, "priority must be between 0 and 3"},
+		{"int wrong type", `{"priority":1.5}`, nil, "priority must be an integer"},
+		{"enum value", `{"status":"done"}`, map[string]interface{}{"status": "done"}, ""},
+		{"null resets enum", `{"status":null}`, map[string]interface{}{"status": "todo"}, ""},
+		{"enum unknown", `{"status":"later"}`, nil, "status must be one of todo, done"},
+		{"not editable", `{"likes":10}`, nil, `field "likes" is not editable`},
+		{
+			"several fields",
+			`{"title":"新","tags":["x"],"status":"done"}`,
+			map[string]interface{}{"title": "新", "tags": pq.StringArray{"x"}, "status": "done"},
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch map[string]json.RawMessage
+			if err := json.Unmarshal([]byte(tt.patch), &patch); err != nil {
+				t.Fatal(err)
+			}
+			got, err := buildPatch(patch, testPatchFields)
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalidPatch) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want ErrInvalidPatch containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("updates = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotePatchFieldsRejectCaptureData(t *testing.T) {
+	for _, name := range []string{"url", "likes", "collects", "comments", "captureTimestamp", "version", "userId",
+		"coverImageUrl", "imageUrls", "videoUrl"} {
+		patch := map[string]json.RawMessage{name: json.RawMessage(`1`)}
+		if _, err := buildPatch(patch, notePatchFields); !errors.Is(err, ErrInvalidPatch) {
+			t.Errorf("%s: err = %v, want ErrInvalidPatch", name, err)
+		}
+	}
+}
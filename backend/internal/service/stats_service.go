@@ -51,7 +51,7 @@ func (s *StatsService) GetStats(userID string) (*StatsResponse, error) {
 		return nil, err
 	}
 
-	bloggerCount, err := s.bloggerRepo.Count(userID)
+	bloggerCount, err := s.bloggerRepo.Count(userID, nil)
 	if err != nil {
 		return nil, err
 	}
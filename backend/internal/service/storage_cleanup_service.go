@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/keenchase/edit-business/internal/repository"
+	"github.com/keenchase/edit-business/internal/storage"
+)
+
+const (
+	// storageCleanupBatchSize 每轮处理的待删除对象数
+	storageCleanupBatchSize = 100
+	// storageCleanupLease 领取的对象在该时间内未处理完会被重新领取
+	storageCleanupLease = 10 * time.Minute
+	// storageCleanupRetryDelay 首次失败后的重试间隔，之后每次翻倍，不超过 mediaMaxRetryDelay
+	storageCleanupRetryDelay = 5 * time.Minute
+)
+
+// StorageCleanupService 后台删除永久删除笔记后不再被引用的存储对象（归档媒体、缩略图和直传文件）
+// 删除失败时按指数退避一直重试，避免文件残留且仍可访问
+type StorageCleanupService struct {
+	deletionRepo *repository.StorageDeletionRepository
+	store        storage.Storage
+}
+
+// NewStorageCleanupService 创建存储对象清理服务实例
+func NewStorageCleanupService(deletionRepo *repository.StorageDeletionRepository, store storage.Storage) *StorageCleanupService {
+	return &StorageCleanupService{deletionRepo: deletionRepo, store: store}
+}
+
+// CleanupResult 一轮清理的结果
+type CleanupResult struct {
+	Deleted  int
+	Skipped  int // 不属于当前存储的地址（如切换过存储驱动），直接移出队列
+	Retrying int
+}
+
+// RunOnce 处理一批到期的待删除对象
+func (s *StorageCleanupService) RunOnce(ctx context.Context, now time.Time) (*CleanupResult, error) {
+	items, err := s.deletionRepo.ClaimDue(now, storageCleanupBatchSize, storageCleanupLease)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CleanupResult{}
+	for _, item := range items {
+		key, ok := storage.KeyFromURL(s.store, item.URL)
+		if !ok {
+			log.Printf("[Storage] skip deleting %s: not an object of the %s storage", item.URL, s.store.Driver())
+			if err := s.deletionRepo.Done(item.URL); err != nil {
+				return result, err
+			}
+			result.Skipped++
+			continue
+		}
+
+		if err := s.store.Delete(ctx, key); err != nil {
+			log.Printf("[Storage] failed to delete %s (attempt %d): %v", key, item.Attempts+1, err)
+			if err := s.deletionRepo.MarkFailed(item.URL, err.Error(), time.Now().Add(cleanupBackoff(item.Attempts))); err != nil {
+				return result, err
+			}
+			result.Retrying++
+			continue
+		}
+		if err := s.deletionRepo.Done(item.URL); err != nil {
+			return result, err
+		}
+		result.Deleted++
+	}
+	return result, nil
+}
+
+// cleanupBackoff 第 attempts 次失败后的重试间隔：storageCleanupRetryDelay * 2^attempts，不超过 mediaMaxRetryDelay
+func cleanupBackoff(attempts int) time.Duration {
+	delay := storageCleanupRetryDelay
+	for i := 0; i < attempts && delay < mediaMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > mediaMaxRetryDelay {
+		delay = mediaMaxRetryDelay
+	}
+	return delay
+}
+
+// StartCleaner 启动后台清理任务：每隔 interval 处理一批，一批处理满时立即继续下一批
+func (s *StorageCleanupService) StartCleaner(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			result, err := s.RunOnce(context.Background(), time.Now())
+			if err != nil {
+				log.Printf("[Storage] cleanup run failed: %v", err)
+			} else if n := result.Deleted + result.Skipped + result.Retrying; n > 0 {
+				log.Printf("[Storage] deleted %d, skipped %d, retrying %d", result.Deleted, result.Skipped, result.Retrying)
+				if n >= storageCleanupBatchSize {
+					continue
+				}
+			}
+			<-ticker.C
+		}
+	}()
+}
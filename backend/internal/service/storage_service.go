@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/keenchase/edit-business/internal/model"
+	"github.com/keenchase/edit-business/internal/repository"
+	"github.com/keenchase/edit-business/internal/storage"
+	"github.com/keenchase/edit-business/internal/xhsurl"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUploadKeyForbidden = errors.New("upload key is outside the user's prefix")
+	ErrUploadNotFound     = errors.New("uploaded object not found")
+	ErrUploadRejected     = errors.New("uploaded object violates upload policy")
+	ErrStorageDriver      = errors.New("operation not supported by the configured storage driver")
+)
+
+// 直传文件列表默认和最大返回条数
+const (
+	defaultUploadListLimit = 50
+	maxUploadListLimit     = 200
+)
+
+// UploadLimits 直传限制
+type UploadLimits struct {
+	MaxBytes  int64
+	MimeTypes []string
+	TokenTTL  time.Duration
+}
+
+// StorageService 对象存储直传：签发按用户隔离的上传凭证，记录上传完成的文件
+type StorageService struct {
+	store           storage.Storage
+	uploadRepo      *repository.UploadedObjectRepository
+	noteRepo        *repository.NoteRepository
+	settingsService *UserSettingsService
+	limits          UploadLimits
+}
+
+// NewStorageService 创建对象存储服务实例
+func NewStorageService(store storage.Storage, uploadRepo *repository.UploadedObjectRepository, noteRepo *repository.NoteRepository, settingsService *UserSettingsService, limits UploadLimits) *StorageService {
+	return &StorageService{
+		store:           store,
+		uploadRepo:      uploadRepo,
+		noteRepo:        noteRepo,
+		settingsService: settingsService,
+		limits:          limits,
+	}
+}
+
+// CompleteUploadRequest 上传完成回调请求；noteId、noteUrl 用于关联笔记（笔记尚未保存时按链接中的笔记 ID 关联）
+type CompleteUploadRequest struct {
+	Key     string `json:"key" binding:"required"`
+	NoteID  string `json:"noteId"`
+	NoteURL string `json:"noteUrl"`
+}
+
+// userKeyPrefix 用户的对象 key 前缀，直传凭证只允许写入该前缀下
+func userKeyPrefix(userID string) string {
+	return "uploads/" + userID + "/"
+}
+
+// policy 用户的直传策略
+func (s *StorageService) policy(userID string, now time.Time) *storage.UploadPolicy {
+	return &storage.UploadPolicy{
+		KeyPrefix: userKeyPrefix(userID),
+		MaxBytes:  s.limits.MaxBytes,
+		MimeTypes: s.limits.MimeTypes,
+		ExpiresAt: now.Add(s.limits.TokenTTL),
+	}
+}
+
+// UploadToken 签发直传凭证（采集开关关闭时拒绝）
+func (s *StorageService) UploadToken(authCenterUserID string) (*storage.UploadTicket, error) {
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+	enabled, err := s.settingsService.IsCollectionEnabled(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, ErrCollectionDisabled
+	}
+	return s.store.SignUpload(s.policy(user.ID, time.Now()))
+}
+
+// Driver 当前存储驱动
+func (s *StorageService) Driver() string {
+	return s.store.Driver()
+}
+
+// CompleteUpload 上传完成回调：校验 key 属于当前用户、对象确实存在且满足大小和类型限制，然后记录文件
+func (s *StorageService) CompleteUpload(ctx context.Context, authCenterUserID string, req *CompleteUploadRequest) (*model.UploadedObject, error) {
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := strings.TrimPrefix(req.Key, "/")
+	prefix := userKeyPrefix(user.ID)
+	if !strings.HasPrefix(key, prefix) || len(key) == len(prefix) || strings.Contains(key, "..") {
+		return nil, ErrUploadKeyForbidden
+	}
+
+	info, err := s.store.Stat(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrUploadNotFound
+		}
+		return nil, err
+	}
+	policy := s.policy(user.ID, time.Now())
+	if err := policy.Allows(key, info.Size, info.ContentType); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUploadRejected, err)
+	}
+
+	obj := &model.UploadedObject{
+		UserID:      user.ID,
+		Driver:      s.store.Driver(),
+		Key:         key,
+		URL:         s.store.URL(key),
+		Size:        info.Size,
+		ContentType: info.ContentType,
+	}
+	if err := s.attachNote(obj, req.NoteID, req.NoteURL); err != nil {
+		return nil, err
+	}
+	if err := s.uploadRepo.Record(obj); err != nil {
+		return nil, err
+	}
+	return s.uploadRepo.GetByKey(user.ID, key)
+}
+
+// attachNote 关联笔记：指定 noteId 时校验归属；只有 noteUrl 时按链接查找已保存的笔记，
+// 找不到则只记录小红书笔记 ID，笔记保存后按笔记 ID 查询即可取到
+func (s *StorageService) attachNote(obj *model.UploadedObject, noteID, noteURL string) error {
+	if noteID != "" {
+		note, err := s.noteRepo.GetByID(noteID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNoteNotFound
+			}
+			return err
+		}
+		if note.UserID != obj.UserID {
+			return ErrNoteNotFound
+		}
+		obj.NoteID = &note.ID
+		obj.XhsNoteID = note.XhsNoteID
+		return nil
+	}
+	if noteURL == "" {
+		return nil
+	}
+
+	obj.XhsNoteID = xhsurl.NoteID(noteURL)
+	note, err := s.noteRepo.GetByIdentity(obj.UserID, obj.XhsNoteID, noteURL)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	obj.NoteID = &note.ID
+	if note.XhsNoteID != "" {
+		obj.XhsNoteID = note.XhsNoteID
+	}
+	return nil
+}
+
+// ListUploads 获取当前用户上传的文件，noteID 非空时只返回该笔记的文件
+func (s *StorageService) ListUploads(authCenterUserID, noteID string, limit int) ([]*model.UploadedObject, error) {
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultUploadListLimit
+	}
+	if limit > maxUploadListLimit {
+		limit = maxUploadListLimit
+	}
+
+	xhsNoteID := ""
+	if noteID != "" {
+		note, err := s.noteRepo.GetByID(noteID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrNoteNotFound
+			}
+			return nil, err
+		}
+		if note.UserID != user.ID {
+			return nil, ErrNoteNotFound
+		}
+		xhsNoteID = note.XhsNoteID
+	}
+	return s.uploadRepo.ListByUser(user.ID, noteID, xhsNoteID, limit)
+}
+
+// LocalUpload 接收本地存储驱动的直传：校验凭证和策略后写入磁盘，返回对象地址
+func (s *StorageService) LocalUpload(ctx context.Context, token, key string, r io.Reader, size int64, contentType string) (string, error) {
+	local, ok := s.store.(*storage.Local)
+	if !ok {
+		return "", ErrStorageDriver
+	}
+	policy, err := local.VerifyUpload(token, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if err := policy.Allows(key, size, contentType); err != nil {
+		return "", err
+	}
+	return local.Put(ctx, key, io.LimitReader(r, size), size, contentType)
+}
+
+// UploadMaxBytes 单个直传文件的大小上限
+func (s *StorageService) UploadMaxBytes() int64 {
+	return s.limits.MaxBytes
+}
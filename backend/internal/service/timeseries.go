@@ -0,0 +1,199 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/keenchase/edit-business/internal/repository"
+)
+
+// 时间序列指标
+const (
+	MetricNotes    = "notes"    // 采集的笔记数
+	MetricBloggers = "bloggers" // 采集的博主数
+	MetricLikes    = "likes"    // 采集的笔记点赞总数
+	MetricCollects = "collects" // 采集的笔记收藏总数
+	MetricComments = "comments" // 采集的笔记评论总数
+)
+
+const (
+	// maxSeriesBuckets 单次查询最多返回的分桶数
+	maxSeriesBuckets = 400
+	// 未指定起始时间时默认返回的分桶数
+	defaultDayBuckets  = 30
+	defaultWeekBuckets = 12
+)
+
+// TimeseriesRequest 时间序列请求；from / to 为毫秒时间戳，按用户时区对齐到分桶边界，均包含在内
+type TimeseriesRequest struct {
+	Metric   string `form:"metric"`   // notes（默认）/ bloggers / likes / collects / comments
+	Interval string `form:"interval"` // day（默认）/ week
+	From     *int64 `form:"from"`
+	To       *int64 `form:"to"`
+}
+
+// TimeseriesBucket 一个分桶：Value 为所选指标，BySource / ByNoteType 为指标按来源、笔记类型的细分
+type TimeseriesBucket struct {
+	Start      time.Time        `json:"start"`           // 用户时区下的分桶起始时间
+	Label      string           `json:"label,omitempty"` // 分桶起始日期（YYYY-MM-DD），合计中为空
+	Value      int64            `json:"value"`
+	Count      int64            `json:"count"` // 采集数量
+	Likes      int64            `json:"likes"`
+	Collects   int64            `json:"collects"`
+	Comments   int64            `json:"comments"`
+	BySource   map[string]int64 `json:"bySource"`
+	ByNoteType map[string]int64 `json:"byNoteType"`
+}
+
+// TimeseriesResponse 时间序列响应（包含空分桶）
+type TimeseriesResponse struct {
+	Metric   string              `json:"metric"`
+	Interval string              `json:"interval"`
+	Timezone string              `json:"timezone"`
+	From     time.Time           `json:"from"` // 第一个分桶的起始时间
+	To       time.Time           `json:"to"`   // 最后一个分桶的结束时间（不含）
+	Buckets  []*TimeseriesBucket `json:"buckets"`
+	Total    *TimeseriesBucket   `json:"total"`
+}
+
+// Timeseries 按天/周统计当前用户的采集数量和互动量（按用户时区分桶）
+func (s *StatsService) Timeseries(authCenterUserID string, req *TimeseriesRequest) (*TimeseriesResponse, error) {
+	metric, interval := req.Metric, req.Interval
+	if metric == "" {
+		metric = MetricNotes
+	}
+	switch metric {
+	case MetricNotes, MetricBloggers, MetricLikes, MetricCollects, MetricComments:
+	default:
+		return nil, fmt.Errorf("%w: metric must be notes, bloggers, likes, collects or comments", ErrInvalidStatsQuery)
+	}
+	if interval == "" {
+		interval = repository.IntervalDay
+	}
+	if interval != repository.IntervalDay && interval != repository.IntervalWeek {
+		return nil, fmt.Errorf("%w: interval must be day or week", ErrInvalidStatsQuery)
+	}
+
+	settings, err := s.settingsService.GetOrCreateSettings(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+	loc := settings.Location()
+
+	from, to, err := seriesRange(req, interval, loc, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []*repository.SeriesRow
+	if metric == MetricBloggers {
+		rows, err = s.bloggerRepo.CaptureSeries(settings.UserID, interval, loc.String(), from, to)
+	} else {
+		rows, err = s.noteRepo.CaptureSeries(settings.UserID, interval, loc.String(), from, to)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &TimeseriesResponse{
+		Metric:   metric,
+		Interval: interval,
+		Timezone: loc.String(),
+		From:     from,
+		To:       to,
+		Total:    newTimeseriesBucket(from),
+	}
+	byLabel := make(map[string]*TimeseriesBucket)
+	for start := from; start.Before(to); start = nextBucket(start, interval) {
+		b := newTimeseriesBucket(start)
+		byLabel[b.Label] = b
+		resp.Buckets = append(resp.Buckets, b)
+	}
+	for _, row := range rows {
+		if b, ok := byLabel[row.Bucket]; ok {
+			b.add(row, metric)
+			resp.Total.add(row, metric)
+		}
+	}
+	resp.Total.Label = ""
+	return resp, nil
+}
+
+func newTimeseriesBucket(start time.Time) *TimeseriesBucket {
+	return &TimeseriesBucket{
+		Start:      start,
+		Label:      start.Format("2006-01-02"),
+		BySource:   map[string]int64{},
+		ByNoteType: map[string]int64{},
+	}
+}
+
+// add 累加一行统计（博主没有来源和笔记类型，不细分）
+func (b *TimeseriesBucket) add(row *repository.SeriesRow, metric string) {
+	value := row.Count
+	switch metric {
+	case MetricLikes:
+		value = row.Likes
+	case MetricCollects:
+		value = row.Collects
+	case MetricComments:
+		value = row.Comments
+	}
+	b.Value += value
+	b.Count += row.Count
+	b.Likes += row.Likes
+	b.Collects += row.Collects
+	b.Comments += row.Comments
+	if metric != MetricBloggers {
+		b.BySource[row.Source] += value
+		b.ByNoteType[row.NoteType] += value
+	}
+}
+
+// seriesRange 计算查询范围 [from, to)：对齐到用户时区的分桶边界，未指定时默认最近 30 天 / 12 周
+func seriesRange(req *TimeseriesRequest, interval string, loc *time.Location, now time.Time) (time.Time, time.Time, error) {
+	last := bucketStart(now, interval, loc)
+	if req.To != nil {
+		last = bucketStart(time.UnixMilli(*req.To), interval, loc)
+	}
+	to := nextBucket(last, interval)
+
+	var from time.Time
+	if req.From != nil {
+		from = bucketStart(time.UnixMilli(*req.From), interval, loc)
+	} else if interval == repository.IntervalWeek {
+		from = last.AddDate(0, 0, -7*(defaultWeekBuckets-1))
+	} else {
+		from = last.AddDate(0, 0, -(defaultDayBuckets - 1))
+	}
+
+	if from.After(last) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: from must not be after to", ErrInvalidStatsQuery)
+	}
+	buckets := int(to.Sub(from).Hours()/24) + 1
+	if interval == repository.IntervalWeek {
+		buckets /= 7
+	}
+	if buckets > maxSeriesBuckets {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: at most %d buckets per query", ErrInvalidStatsQuery, maxSeriesBuckets)
+	}
+	return from, to, nil
+}
+
+// bucketStart t 所在分桶在用户时区下的起始时间（周从周一开始）
+func bucketStart(t time.Time, interval string, loc *time.Location) time.Time {
+	local := t.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	if interval == repository.IntervalWeek {
+		start = start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+	}
+	return start
+}
+
+// nextBucket 下一个分桶的起始时间（按日历日计算，夏令时切换日同样正确）
+func nextBucket(start time.Time, interval string) time.Time {
+	if interval == repository.IntervalWeek {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 0, 1)
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/keenchase/edit-business/internal/model"
+	"github.com/keenchase/edit-business/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTrashItemNotFound = errors.New("trash item not found")
+	ErrInvalidTrashType  = errors.New("invalid trash item type")
+)
+
+// RestoreConflictError 恢复时已存在相同链接（笔记）或 xhs_id（博主）的记录
+type RestoreConflictError struct {
+	Type       string
+	ConflictID string
+}
+
+func (e *RestoreConflictError) Error() string {
+	return fmt.Sprintf("a %s with the same identity already exists: %s", e.Type, e.ConflictID)
+}
+
+// TrashedMatch 重新采集时发现回收站中有相同记录，提示用户可恢复
+type TrashedMatch struct {
+	ID          string    `json:"id"`
+	DeletedAt   time.Time `json:"deletedAt"`
+	RestorePath string    `json:"restorePath"`
+}
+
+func newTrashedMatch(id string, deletedAt gorm.DeletedAt) *TrashedMatch {
+	return &TrashedMatch{
+		ID:          id,
+		DeletedAt:   deletedAt.Time,
+		RestorePath: fmt.Sprintf("/api/v1/trash/%s/restore", id),
+	}
+}
+
+// TrashService 回收站服务
+type TrashService struct {
+	trashRepo       *repository.TrashRepository
+	noteRepo        *repository.NoteRepository
+	bloggerRepo     *repository.BloggerRepository
+	settingsService *UserSettingsService
+	retentionDays   int
+}
+
+// NewTrashService 创建回收站服务实例，retentionDays <= 0 表示不自动清理
+func NewTrashService(trashRepo *repository.TrashRepository, noteRepo *repository.NoteRepository, bloggerRepo *repository.BloggerRepository, settingsService *UserSettingsService, retentionDays int) *TrashService {
+	return &TrashService{
+		trashRepo:       trashRepo,
+		noteRepo:        noteRepo,
+		bloggerRepo:     bloggerRepo,
+		settingsService: settingsService,
+		retentionDays:   retentionDays,
+	}
+}
+
+// ListTrashRequest 回收站列表请求
+type ListTrashRequest struct {
+	Type string `form:"type"` // note / blogger，空表示全部
+	Page int    `form:"page"`
+	Size int    `form:"size"`
+}
+
+// TrashItem 回收站条目
+type TrashItem struct {
+	Type      string     `json:"type"`
+	ID        string     `json:"id"`
+	Title     string     `json:"title"`
+	URL       string     `json:"url"`
+	DeletedAt time.Time  `json:"deletedAt"`
+	PurgeAt   *time.Time `json:"purgeAt"` // 预计永久删除时间，未开启自动清理时为 null
+}
+
+// ListTrashResponse 回收站列表响应
+type ListTrashResponse struct {
+	Items         []*TrashItem `json:"items"`
+	Total         int64        `json:"total"`
+	Page          int          `json:"page"`
+	Size          int          `json:"size"`
+	TotalPages    int          `json:"totalPages"`
+	RetentionDays int          `json:"retentionDays"`
+}
+
+// RestoreResult 恢复结果
+type RestoreResult struct {
+	Type    string         `json:"type"`
+	Note    *model.Note    `json:"note,omitempty"`
+	Blogger *model.Blogger `json:"blogger,omitempty"`
+}
+
+// PurgeResult 永久删除结果
+type PurgeResult struct {
+	Notes    int64 `json:"notes"`
+	Bloggers int64 `json:"bloggers"`
+}
+
+func validTrashType(t string) bool {
+	return t == "" || t == repository.TrashTypeNote || t == repository.TrashTypeBlogger
+}
+
+// List 获取回收站列表（按用户隔离，按删除时间倒序）
+func (s *TrashService) List(authCenterUserID string, req *ListTrashRequest) (*ListTrashResponse, error) {
+	if !validTrashType(req.Type) {
+		return nil, ErrInvalidTrashType
+	}
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Size < 1 || req.Size > 100 {
+		req.Size = 20
+	}
+
+	entries, total, err := s.trashRepo.List(user.ID, req.Type, (req.Page-1)*req.Size, req.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*TrashItem, len(entries))
+	for i, e := range entries {
+		items[i] = &TrashItem{
+			Type:      e.Type,
+			ID:        e.ID,
+			Title:     e.Title,
+			URL:       e.URL,
+			DeletedAt: e.DeletedAt,
+		}
+		if s.retentionDays > 0 {
+			purgeAt := e.DeletedAt.AddDate(0, 0, s.retentionDays)
+			items[i].PurgeAt = &purgeAt
+		}
+	}
+
+	totalPages := int(total) / req.Size
+	if int(total)%req.Size > 0 {
+		totalPages++
+	}
+
+	return &ListTrashResponse{
+		Items:         items,
+		Total:         total,
+		Page:          req.Page,
+		Size:          req.Size,
+		TotalPages:    totalPages,
+		RetentionDays: s.retentionDays,
+	}, nil
+}
+
+// Restore 从回收站恢复笔记或博主（校验归属）
+// 已存在相同链接 / xhs_id 的记录时返回 RestoreConflictError；replace 为 true 时把该记录移入回收站后再恢复
+func (s *TrashService) Restore(authCenterUserID, id string, replace bool) (*RestoreResult, error) {
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if note, err := s.noteRepo.GetTrashed(user.ID, id); err == nil {
+		replaceID := ""
+		if live, err := s.noteRepo.GetByIdentity(user.ID, note.XhsNoteID, note.URL); err == nil {
+			if !replace {
+				return nil, &RestoreConflictError{Type: repository.TrashTypeNote, ConflictID: live.ID}
+			}
+			replaceID = live.ID
+		}
+		restored, err := s.noteRepo.Restore(user.ID, id, replaceID)
+		if err != nil {
+			return nil, err
+		}
+		return &RestoreResult{Type: repository.TrashTypeNote, Note: restored}, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	blogger, err := s.bloggerRepo.GetTrashed(user.ID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTrashItemNotFound
+		}
+		return nil, err
+	}
+	replaceID := ""
+	if live, err := s.bloggerRepo.GetByUserIDAndXhsID(user.ID, blogger.XhsID); err == nil {
+		if !replace {
+			return nil, &RestoreConflictError{Type: repository.TrashTypeBlogger, ConflictID: live.ID}
+		}
+		replaceID = live.ID
+	}
+	restored, err := s.bloggerRepo.Restore(user.ID, id, replaceID)
+	if err != nil {
+		return nil, err
+	}
+	return &RestoreResult{Type: repository.TrashTypeBlogger, Blogger: restored}, nil
+}
+
+// Purge 永久删除回收站中的一条记录（校验归属）
+func (s *TrashService) Purge(authCenterUserID, id string) error {
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return err
+	}
+	notes, bloggers, err := s.trashRepo.Purge(repository.TrashScope{UserID: user.ID, ID: id})
+	if err != nil {
+		return err
+	}
+	if notes+bloggers == 0 {
+		return ErrTrashItemNotFound
+	}
+	return nil
+}
+
+// Empty 清空回收站（按用户隔离），itemType 为空时清空笔记和博主
+func (s *TrashService) Empty(authCenterUserID, itemType string) (*PurgeResult, error) {
+	if !validTrashType(itemType) {
+		return nil, ErrInvalidTrashType
+	}
+	user, err := s.settingsService.GetUserByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+	notes, bloggers, err := s.trashRepo.Purge(repository.TrashScope{UserID: user.ID, ItemType: itemType})
+	if err != nil {
+		return nil, err
+	}
+	return &PurgeResult{Notes: notes, Bloggers: bloggers}, nil
+}
+
+// PurgeExpired 永久删除超过保留期的回收站记录（全部用户）
+func (s *TrashService) PurgeExpired(now time.Time) (*PurgeResult, error) {
+	if s.retentionDays <= 0 {
+		return &PurgeResult{}, nil
+	}
+	before := now.AddDate(0, 0, -s.retentionDays)
+	notes, bloggers, err := s.trashRepo.Purge(repository.TrashScope{Before: before})
+	if err != nil {
+		return nil, err
+	}
+	return &PurgeResult{Notes: notes, Bloggers: bloggers}, nil
+}
+
+// StartPurger 启动后台清理任务：启动时执行一次，之后每隔 interval 执行
+func (s *TrashService) StartPurger(interval time.Duration) {
+	if s.retentionDays <= 0 {
+		log.Printf("[Trash] auto purge disabled")
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			result, err := s.PurgeExpired(time.Now())
+			if err != nil {
+				log.Printf("[Trash] purge failed: %v", err)
+			} else if result.Notes+result.Bloggers > 0 {
+				log.Printf("[Trash] purged %d notes and %d bloggers older than %d days", result.Notes, result.Bloggers, s.retentionDays)
+			}
+			<-ticker.C
+		}
+	}()
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/keenchase/edit-business/internal/dbtest"
+	"github.com/keenchase/edit-business/internal/model"
+	"github.com/keenchase/edit-business/internal/repository"
+)
+
+func newTestTrashService(s *testServices) *TrashService {
+	return NewTrashService(repository.NewTrashRepository(s.db), repository.NewNoteRepository(s.db),
+		repository.NewBloggerRepository(s.db), s.settings, 30)
+}
+
+func TestTrashRestoreNoteConflict(t *testing.T) {
+	s := newTestServices(t)
+	trash := newTestTrashService(s)
+	dbtest.CreateUser(t, s.db, "user-trash", nil)
+
+	const xhsNoteID = "65a1b2c3d4e5f60718293a4b"
+	original, err := s.notes.Create("user-trash", testNoteRequest(xhsNoteID, 1))
+	if err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	if err := s.notes.Delete("user-trash", original.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	// 重新采集产生新记录，并提示回收站中的同一篇笔记
+	recaptured, err := s.notes.Create("user-trash", testNoteRequest(xhsNoteID, 2))
+	if err != nil {
+		t.Fatalf("re-capture: %v", err)
+	}
+	if recaptured.ID == original.ID || recaptured.TrashedMatch == nil || recaptured.TrashedMatch.ID != original.ID {
+		t.Fatalf("re-capture = %s trashedMatch %+v, want a new note pointing at %s", recaptured.ID, recaptured.TrashedMatch, original.ID)
+	}
+
+	_, err = trash.Restore("user-trash", original.ID, false)
+	var conflict *RestoreConflictError
+	if !errors.As(err, &conflict) || conflict.Type != repository.TrashTypeNote || conflict.ConflictID != recaptured.ID {
+		t.Fatalf("restore without replace err = %v, want conflict with %s", err, recaptured.ID)
+	}
+
+	result, err := trash.Restore("user-trash", original.ID, true)
+	if err != nil {
+		t.Fatalf("restore with replace: %v", err)
+	}
+	if result.Note == nil || result.Note.ID != original.ID || result.Note.DeletedAt.Valid {
+		t.Errorf("restored note = %+v, want %s out of the trash", result.Note, original.ID)
+	}
+	if _, err := s.notes.GetByID("user-trash", recaptured.ID); err == nil {
+		t.Errorf("replaced note %s is still live", recaptured.ID)
+	}
+	listed, err := trash.List("user-trash", &ListTrashRequest{Type: repository.TrashTypeNote})
+	if err != nil {
+		t.Fatalf("list trash: %v", err)
+	}
+	if listed.Total != 1 || listed.Items[0].ID != recaptured.ID {
+		t.Errorf("trash = %+v, want only the replaced note %s", listed.Items, recaptured.ID)
+	}
+}
+
+func TestTrashRestoreBloggerConflict(t *testing.T) {
+	s := newTestServices(t)
+	trash := newTestTrashService(s)
+	dbtest.CreateUser(t, s.db, "user-trash", nil)
+
+	req := &CreateBloggerRequest{XhsID: "xhs-1", BloggerName: "blogger", CaptureTimestamp: 1}
+	original, err := s.bloggers.UpsertByXhsID("user-trash", req)
+	if err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	if err := s.bloggers.Delete("user-trash", original.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	recaptured, err := s.bloggers.UpsertByXhsID("user-trash", req)
+	if err != nil {
+		t.Fatalf("re-capture: %v", err)
+	}
+
+	_, err = trash.Restore("user-trash", original.ID, false)
+	var conflict *RestoreConflictError
+	if !errors.As(err, &conflict) || conflict.Type != repository.TrashTypeBlogger || conflict.ConflictID != recaptured.ID {
+		t.Fatalf("restore without replace err = %v, want conflict with %s", err, recaptured.ID)
+	}
+	result, err := trash.Restore("user-trash", original.ID, true)
+	if err != nil {
+		t.Fatalf("restore with replace: %v", err)
+	}
+	if result.Blogger == nil || result.Blogger.ID != original.ID {
+		t.Errorf("restored blogger = %+v, want %s", result.Blogger, original.ID)
+	}
+
+	// 其他用户不能恢复
+	dbtest.CreateUser(t, s.db, "user-other", nil)
+	if _, err := trash.Restore("user-other", recaptured.ID, false); !errors.Is(err, ErrTrashItemNotFound) {
+		t.Errorf("restore by another user err = %v, want ErrTrashItemNotFound", err)
+	}
+}
+
+func TestTrashPurgeCascades(t *testing.T) {
+	s := newTestServices(t)
+	trash := newTestTrashService(s)
+	dbtest.CreateUser(t, s.db, "user-trash", nil)
+
+	purged, err := s.notes.Create("user-trash", testNoteRequest("65a1b2c3d4e5f60718293a01", 1))
+	if err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	kept, err := s.notes.Create("user-trash", testNoteRequest("65a1b2c3d4e5f60718293a02", 1))
+	if err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+
+	collection := &model.Collection{UserID: "user-trash", Name: "inbox"}
+	uploadNoteID := purged.ID
+	seed := []interface{}{
+		collection,
+		&model.NoteEditorial{NoteID: purged.ID, UserID: "user-trash", Status: model.NoteStatusInbox},
+		&model.NoteStatusChange{NoteID: purged.ID, UserID: "user-trash", FromStatus: model.NoteStatusInbox, ToStatus: model.NoteStatusShortlisted},
+		&model.NoteMedia{NoteID: purged.ID, SourceURL: "https://ci.xiaohongshu.com/a", UserID: "user-trash",
+			Kind: model.NoteMediaImage, Status: model.NoteMediaArchived, ArchivedURL: "https://cdn.example.com/media/a.jpg"},
+		&model.NoteMedia{NoteID: purged.ID, SourceURL: "https://ci.xiaohongshu.com/shared", UserID: "user-trash",
+			Kind: model.NoteMediaImage, Status: model.NoteMediaArchived, ArchivedURL: "https://cdn.example.com/media/shared.jpg"},
+		&model.NoteMedia{NoteID: kept.ID, SourceURL: "https://ci.xiaohongshu.com/shared", UserID: "user-trash",
+			Kind: model.NoteMediaImage, Status: model.NoteMediaArchived, ArchivedURL: "https://cdn.example.com/media/shared.jpg"},
+		&model.UploadedObject{UserID: "user-trash", NoteID: &uploadNoteID, Driver: "local", Key: "uploads/a.jpg",
+			URL: "https://cdn.example.com/uploads/a.jpg"},
+	}
+	for _, v := range seed {
+		if err := s.db.Create(v).Error; err != nil {
+			t.Fatalf("seed %T: %v", v, err)
+		}
+	}
+	if err := s.db.Create(&model.CollectionNote{CollectionID: collection.ID, NoteID: purged.ID}).Error; err != nil {
+		t.Fatalf("seed collection note: %v", err)
+	}
+
+	// 不在回收站中的笔记不能永久删除
+	if err := trash.Purge("user-trash", purged.ID); !errors.Is(err, ErrTrashItemNotFound) {
+		t.Fatalf("purge live note err = %v, want ErrTrashItemNotFound", err)
+	}
+	if err := s.notes.Delete("user-trash", purged.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := trash.Purge("user-trash", purged.ID); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+
+	count := func(v interface{}, query string, args ...interface{}) int64 {
+		var n int64
+		if err := s.db.Unscoped().Model(v).Where(query, args...).Count(&n).Error; err != nil {
+			t.Fatalf("count %T: %v", v, err)
+		}
+		return n
+	}
+	for _, v := range []interface{}{
+		&model.NoteMetricSnapshot{}, &model.NoteSearchTerm{}, &model.NoteContentTerm{}, &model.CollectionNote{},
+		&model.NoteEditorial{}, &model.NoteStatusChange{}, &model.NoteMedia{}, &model.UploadedObject{},
+	} {
+		if n := count(v, "note_id = ?", purged.ID); n != 0 {
+			t.Errorf("%T rows left for the purged note: %d", v, n)
+		}
+	}
+	if n := count(&model.Note{}, "id = ?", purged.ID); n != 0 {
+		t.Errorf("purged note still exists")
+	}
+
+	// 仍被其他笔记引用的归档文件不删除
+	var queued []string
+	if err := s.db.Model(&model.StorageDeletion{}).Order("url").Pluck("url", &queued).Error; err != nil {
+		t.Fatalf("storage deletions: %v", err)
+	}
+	want := []string{"https://cdn.example.com/media/a.jpg", "https://cdn.example.com/uploads/a.jpg"}
+	if len(queued) != len(want) || queued[0] != want[0] || queued[1] != want[1] {
+		t.Errorf("queued deletions = %v, want %v", queued, want)
+	}
+	if n := count(&model.NoteMedia{}, "note_id = ?", kept.ID); n != 1 {
+		t.Errorf("media of the kept note = %d, want 1", n)
+	}
+	if n := count(&model.NoteMetricSnapshot{}, "note_id = ?", kept.ID); n == 0 {
+		t.Error("snapshots of the kept note were purged")
+	}
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/keenchase/edit-business/internal/model"
 	"github.com/keenchase/edit-business/internal/repository"
@@ -11,21 +13,53 @@ var (
 	ErrCollectionDisabled    = errors.New("collection is disabled by user")
 	ErrDailyLimitExceeded    = errors.New("daily collection limit exceeded")
 	ErrBatchLimitExceeded   = errors.New("batch collection limit exceeded")
+	ErrInvalidTimezone      = errors.New("invalid timezone")
 )
 
+// 采集额度分类：笔记和博主分别按每日上限计数
+const (
+	QuotaNotes    = "notes"
+	QuotaBloggers = "bloggers"
+)
+
+// QuotaError 超出采集上限，Limit 为用户当前的上限值
+// errors.Is 可匹配 ErrBatchLimitExceeded / ErrDailyLimitExceeded
+type QuotaError struct {
+	Err   error
+	Limit int
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("%v (limit %d)", e.Err, e.Limit)
+}
+
+func (e *QuotaError) Unwrap() error {
+	return e.Err
+}
+
+// Message 面向用户的提示
+func (e *QuotaError) Message() string {
+	if errors.Is(e.Err, ErrBatchLimitExceeded) {
+		return fmt.Sprintf("单次采集超过上限（%d条）", e.Limit)
+	}
+	return fmt.Sprintf("今日采集数量已达上限（%d条）", e.Limit)
+}
+
 // UserSettingsService handles user settings business logic
 type UserSettingsService struct {
 	settingsRepo *repository.UserSettingsRepository
 	userRepo     *repository.UserRepository
 	noteRepo     *repository.NoteRepository
+	bloggerRepo  *repository.BloggerRepository
 }
 
 // NewUserSettingsService creates a new user settings service
-func NewUserSettingsService(settingsRepo *repository.UserSettingsRepository, userRepo *repository.UserRepository, noteRepo *repository.NoteRepository) *UserSettingsService {
+func NewUserSettingsService(settingsRepo *repository.UserSettingsRepository, userRepo *repository.UserRepository, noteRepo *repository.NoteRepository, bloggerRepo *repository.BloggerRepository) *UserSettingsService {
 	return &UserSettingsService{
 		settingsRepo: settingsRepo,
 		userRepo:     userRepo,
 		noteRepo:     noteRepo,
+		bloggerRepo:  bloggerRepo,
 	}
 }
 
@@ -88,40 +122,144 @@ func (s *UserSettingsService) ToggleCollectionEnabled(authCenterUserID string, e
 	return settings, nil
 }
 
-// CheckCollectionLimits checks if the user can collect data
-// Returns error if limits are exceeded
-func (s *UserSettingsService) CheckCollectionLimits(authCenterUserID string, batchSize int) error {
+// SetTimezone 设置用户时区（IANA 名称，如 Asia/Shanghai）
+func (s *UserSettingsService) SetTimezone(authCenterUserID, timezone string) (*model.UserSettings, error) {
+	if timezone == "" || timezone == "Local" {
+		return nil, ErrInvalidTimezone
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, ErrInvalidTimezone
+	}
+
+	user, err := s.userRepo.GetByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.settingsRepo.GetOrCreate(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.settingsRepo.UpdateTimezone(user.ID, timezone); err != nil {
+		return nil, err
+	}
+	settings.Timezone = timezone
+	return settings, nil
+}
+
+// CheckCollectionLimits 检查用户本次采集 batchSize 条 kind（QuotaNotes / QuotaBloggers）是否允许，
+// 返回今日额度；采集关闭返回 ErrCollectionDisabled，超出单批上限返回 *QuotaError
+// 每日额度只计新增的记录：剩余额度不足时仍可更新已有记录，由调用方确定新增条数后对超出部分返回 usage.exceeded()
+func (s *UserSettingsService) CheckCollectionLimits(authCenterUserID, kind string, batchSize int) (*QuotaUsage, error) {
 	user, err := s.userRepo.GetByAuthCenterUserID(authCenterUserID)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return s.CheckUserCollectionLimits(user.ID, kind, batchSize)
+}
 
-	settings, err := s.settingsRepo.GetByUserID(user.ID)
+// CheckUserCollectionLimits 同 CheckCollectionLimits，按内部用户 ID 检查（文件导入与插件采集共用）
+func (s *UserSettingsService) CheckUserCollectionLimits(userID, kind string, batchSize int) (*QuotaUsage, error) {
+	settings, err := s.settingsRepo.GetByUserID(userID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Check if collection is enabled
 	if !settings.CollectionEnabled {
-		return ErrCollectionDisabled
+		return nil, ErrCollectionDisabled
 	}
 
 	// Check batch limit
 	if batchSize > settings.CollectionBatchLimit {
-		return ErrBatchLimitExceeded
+		return nil, &QuotaError{Err: ErrBatchLimitExceeded, Limit: settings.CollectionBatchLimit}
 	}
 
-	// Check daily limit
-	dailyCount, err := s.noteRepo.CountByUserAndDate(user.ID, "")
+	return s.dailyUsage(userID, settings, kind, time.Now())
+}
+
+// QuotaUsage 某类数据的今日采集额度
+type QuotaUsage struct {
+	Limit     int `json:"limit"`
+	Used      int `json:"used"`
+	Remaining int `json:"remaining"`
+}
+
+// exceeded 新增记录超出今日剩余额度时返回的错误
+func (u *QuotaUsage) exceeded() error {
+	return &QuotaError{Err: ErrDailyLimitExceeded, Limit: u.Limit}
+}
+
+// QuotaResponse 采集额度
+// 每日额度按用户时区的自然日计算，resetAt 为下一次重置时间
+type QuotaResponse struct {
+	CollectionEnabled bool        `json:"collectionEnabled"`
+	BatchLimit        int         `json:"batchLimit"`
+	Timezone          string      `json:"timezone"`
+	ResetAt           time.Time   `json:"resetAt"`
+	Notes             *QuotaUsage `json:"notes"`
+	Bloggers          *QuotaUsage `json:"bloggers"`
+}
+
+// GetQuota 获取用户当前的采集额度
+func (s *UserSettingsService) GetQuota(authCenterUserID string) (*QuotaResponse, error) {
+	user, err := s.userRepo.GetByAuthCenterUserID(authCenterUserID)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.settingsRepo.GetByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	notes, err := s.dailyUsage(user.ID, settings, QuotaNotes, now)
+	if err != nil {
+		return nil, err
+	}
+	bloggers, err := s.dailyUsage(user.ID, settings, QuotaBloggers, now)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	_, end := dayWindow(now, settings.Location())
+
+	return &QuotaResponse{
+		CollectionEnabled: settings.CollectionEnabled,
+		BatchLimit:        settings.CollectionBatchLimit,
+		Timezone:          settings.Location().String(),
+		ResetAt:           end,
+		Notes:             notes,
+		Bloggers:          bloggers,
+	}, nil
+}
 
-	if int(dailyCount) >= settings.CollectionDailyLimit {
-		return ErrDailyLimitExceeded
+// dailyUsage 统计用户在其时区的今天新增的 kind 数量
+func (s *UserSettingsService) dailyUsage(userID string, settings *model.UserSettings, kind string, now time.Time) (*QuotaUsage, error) {
+	start, end := dayWindow(now, settings.Location())
+
+	var used int64
+	var err error
+	switch kind {
+	case QuotaBloggers:
+		used, err = s.bloggerRepo.CountCreatedBetween(userID, start, end)
+	default:
+		used, err = s.noteRepo.CountCreatedBetween(userID, start, end)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	remaining := settings.CollectionDailyLimit - int(used)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &QuotaUsage{Limit: settings.CollectionDailyLimit, Used: int(used), Remaining: remaining}, nil
+}
+
+// dayWindow now 所在自然日（loc 时区）的起止时间
+func dayWindow(now time.Time, loc *time.Location) (time.Time, time.Time) {
+	local := now.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 0, 1)
 }
 
 // UserSettingsResponse represents the user settings response
@@ -130,6 +268,7 @@ type UserSettingsResponse struct {
 	CollectionEnabled    bool   `json:"collectionEnabled"`
 	CollectionDailyLimit int    `json:"collectionDailyLimit"`
 	CollectionBatchLimit int    `json:"collectionBatchLimit"`
+	Timezone             string `json:"timezone"`
 }
 
 // ToResponse converts model to response
@@ -139,5 +278,6 @@ func (s *UserSettingsService) ToResponse(settings *model.UserSettings) UserSetti
 		CollectionEnabled:    settings.CollectionEnabled,
 		CollectionDailyLimit: settings.CollectionDailyLimit,
 		CollectionBatchLimit: settings.CollectionBatchLimit,
+		Timezone:             settings.Location().String(),
 	}
 }
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keenchase/edit-business/internal/dbtest"
+	"github.com/keenchase/edit-business/internal/model"
+)
+
+func TestDayWindow(t *testing.T) {
+	load := func(name string) *time.Location {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			t.Fatalf("load %s: %v", name, err)
+		}
+		return loc
+	}
+	utc := func(s string) time.Time {
+		v, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatalf("parse %s: %v", s, err)
+		}
+		return v
+	}
+
+	tests := []struct {
+		name      string
+		now       string
+		loc       *time.Location
+		wantStart string
+		wantEnd   string
+	}{
+		{"shanghai evening utc is next day", "2026-03-01T17:30:00Z", load("Asia/Shanghai"), "2026-03-01T16:00:00Z", "2026-03-02T16:00:00Z"},
+		{"new york same instant", "2026-03-01T17:30:00Z", load("America/New_York"), "2026-03-01T05:00:00Z", "2026-03-02T05:00:00Z"},
+		{"new york before local midnight", "2026-03-02T04:59:59Z", load("America/New_York"), "2026-03-01T05:00:00Z", "2026-03-02T05:00:00Z"},
+		{"dst start day is 23 hours", "2026-03-08T12:00:00Z", load("America/New_York"), "2026-03-08T05:00:00Z", "2026-03-09T04:00:00Z"},
+		{"utc", "2026-03-01T00:00:00Z", time.UTC, "2026-03-01T00:00:00Z", "2026-03-02T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := dayWindow(utc(tt.now), tt.loc)
+			if !start.Equal(utc(tt.wantStart)) || !end.Equal(utc(tt.wantEnd)) {
+				t.Errorf("dayWindow = [%s, %s), want [%s, %s)", start.UTC(), end.UTC(), tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestGetQuotaCountsTheUsersLocalDay(t *testing.T) {
+	s := newTestServices(t)
+	dbtest.CreateUser(t, s.db, "user-tz", &model.UserSettings{
+		CollectionEnabled:    true,
+		CollectionDailyLimit: 3,
+		CollectionBatchLimit: 10,
+		Timezone:             "America/New_York",
+	})
+	loc, _ := time.LoadLocation("America/New_York")
+	start, end := dayWindow(time.Now(), loc)
+
+	createNote := func(xhsNoteID string, createdAt time.Time) *model.Note {
+		note := &model.Note{
+			UserID:           "user-tz",
+			URL:              "https://www.xiaohongshu.com/explore/" + xhsNoteID,
+			XhsNoteID:        xhsNoteID,
+			CaptureTimestamp: createdAt.UnixMilli(),
+			CreatedAt:        createdAt,
+		}
+		if err := s.db.Create(note).Error; err != nil {
+			t.Fatalf("create note: %v", err)
+		}
+		return note
+	}
+	createNote("65a1b2c3d4e5f60718293a01", start.Add(-time.Minute)) // 用户时区的昨天
+	createNote("65a1b2c3d4e5f60718293a02", start)
+	trashed := createNote("65a1b2c3d4e5f60718293a03", start.Add(time.Minute))
+	if err := s.db.Delete(trashed).Error; err != nil {
+		t.Fatalf("trash note: %v", err)
+	}
+
+	quota, err := s.settings.GetQuota("user-tz")
+	if err != nil {
+		t.Fatalf("quota: %v", err)
+	}
+	// 移入回收站不返还额度
+	if quota.Notes.Used != 2 || quota.Notes.Remaining != 1 {
+		t.Errorf("notes quota = %+v, want used 2 remaining 1", quota.Notes)
+	}
+	if quota.Timezone != "America/New_York" || !quota.ResetAt.Equal(end) {
+		t.Errorf("timezone/resetAt = %s/%s, want America/New_York/%s", quota.Timezone, quota.ResetAt, end)
+	}
+
+	usage, err := s.settings.CheckCollectionLimits("user-tz", QuotaNotes, 1)
+	if err != nil || usage.Remaining != 1 {
+		t.Errorf("CheckCollectionLimits = %+v, %v, want remaining 1", usage, err)
+	}
+	if _, err := s.settings.CheckCollectionLimits("user-tz", QuotaNotes, 11); err == nil {
+		t.Error("CheckCollectionLimits over the batch limit succeeded")
+	}
+}
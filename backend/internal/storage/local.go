@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Local 本地磁盘存储：对象写入 dir 下的同名路径，URL 为 baseURL + "/" + key
+// （由服务自身以静态文件方式提供访问）；直传由服务的上传接口接收，凭证为 HMAC 签名的策略
+type Local struct {
+	dir       string
+	baseURL   string
+	uploadURL string
+	secret    []byte
+}
+
+// NewLocal 创建本地磁盘存储
+func NewLocal(dir, baseURL, uploadURL, secret string) *Local {
+	return &Local{
+		dir:       dir,
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		uploadURL: uploadURL,
+		secret:    []byte(secret),
+	}
+}
+
+// Driver 驱动名称
+func (s *Local) Driver() string {
+	return DriverLocal
+}
+
+// Dir 存储根目录
+func (s *Local) Dir() string {
+	return s.dir
+}
+
+// BaseURL 对象访问地址前缀
+func (s *Local) BaseURL() string {
+	return s.baseURL
+}
+
+// Put 写入对象：先写临时文件再重命名，避免读到写了一半的文件
+func (s *Local) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return s.URL(key), nil
+}
+
+// Stat 查询对象信息，类型按扩展名判断
+func (s *Local) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	contentType := mime.TypeByExtension(filepath.Ext(path))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return &ObjectInfo{Size: fi.Size(), ContentType: contentType}, nil
+}
+
+// Delete 删除对象文件
+func (s *Local) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// URL 对象的访问地址
+func (s *Local) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// SignUpload 生成直传凭证：base64(策略 JSON) + "." + base64(HMAC-SHA256)
+func (s *Local) SignUpload(policy *UploadPolicy) (*UploadTicket, error) {
+	payload, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return &UploadTicket{
+		Driver:    DriverLocal,
+		UploadURL: s.uploadURL,
+		Token:     encoded + "." + s.sign(encoded),
+		KeyPrefix: policy.KeyPrefix,
+		PublicURL: s.baseURL,
+		MaxBytes:  policy.MaxBytes,
+		MimeTypes: policy.MimeTypes,
+		ExpiresAt: policy.ExpiresAt,
+	}, nil
+}
+
+// VerifyUpload 校验直传凭证，返回其中的策略
+func (s *Local) VerifyUpload(token string, now time.Time) (*UploadPolicy, error) {
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(s.sign(encoded))) {
+		return nil, ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var policy UploadPolicy
+	if err := json.Unmarshal(payload, &policy); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if now.After(policy.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+	return &policy, nil
+}
+
+func (s *Local) sign(encoded string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// path 对象在磁盘上的路径，拒绝跳出根目录的 key
+func (s *Local) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" || strings.Contains(key, "..") {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
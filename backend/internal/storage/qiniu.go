@@ -0,0 +1,215 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// 七牛云默认接口地址
+const (
+	defaultQiniuUploadURL = "https://upload.qiniup.com"
+	defaultQiniuRSHost    = "https://rs.qiniuapi.com"
+	qiniuServerTokenTTL   = time.Hour
+	qiniuStatusNotFound   = 612
+)
+
+// Qiniu 七牛云存储（表单上传 + 资源管理接口）
+type Qiniu struct {
+	accessKey string
+	secretKey []byte
+	bucket    string
+	domain    string
+	uploadURL string
+	rsHost    string
+	client    *http.Client
+}
+
+// NewQiniu 创建七牛云存储，uploadURL、rsHost 为空时使用默认地址
+func NewQiniu(accessKey, secretKey, bucket, domain, uploadURL, rsHost string, client *http.Client) *Qiniu {
+	if uploadURL == "" {
+		uploadURL = defaultQiniuUploadURL
+	}
+	if rsHost == "" {
+		rsHost = defaultQiniuRSHost
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if !strings.Contains(domain, "://") {
+		domain = "https://" + domain
+	}
+	return &Qiniu{
+		accessKey: accessKey,
+		secretKey: []byte(secretKey),
+		bucket:    bucket,
+		domain:    strings.TrimRight(domain, "/"),
+		uploadURL: uploadURL,
+		rsHost:    strings.TrimRight(rsHost, "/"),
+		client:    client,
+	}
+}
+
+// Driver 驱动名称
+func (s *Qiniu) Driver() string {
+	return DriverQiniu
+}
+
+// qiniuPutPolicy 七牛云上传策略
+type qiniuPutPolicy struct {
+	Scope           string `json:"scope"`
+	Deadline        int64  `json:"deadline"`
+	IsPrefixalScope int    `json:"isPrefixalScope,omitempty"`
+	FsizeLimit      int64  `json:"fsizeLimit,omitempty"`
+	MimeLimit       string `json:"mimeLimit,omitempty"`
+}
+
+// uploadToken 生成上传凭证：accessKey:base64(HMAC-SHA1(encodedPolicy)):encodedPolicy
+func (s *Qiniu) uploadToken(policy *qiniuPutPolicy) (string, error) {
+	payload, err := json.Marshal(policy)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.URLEncoding.EncodeToString(payload)
+	return s.accessKey + ":" + s.sign([]byte(encoded)) + ":" + encoded, nil
+}
+
+func (s *Qiniu) sign(data []byte) string {
+	mac := hmac.New(sha1.New, s.secretKey)
+	mac.Write(data)
+	return base64.URLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SignUpload 生成直传凭证：scope 为 bucket:keyPrefix 并开启前缀匹配，只能写入该前缀下的对象
+func (s *Qiniu) SignUpload(policy *UploadPolicy) (*UploadTicket, error) {
+	token, err := s.uploadToken(&qiniuPutPolicy{
+		Scope:           s.bucket + ":" + policy.KeyPrefix,
+		Deadline:        policy.ExpiresAt.Unix(),
+		IsPrefixalScope: 1,
+		FsizeLimit:      policy.MaxBytes,
+		MimeLimit:       strings.Join(policy.MimeTypes, ";"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &UploadTicket{
+		Driver:    DriverQiniu,
+		UploadURL: s.uploadURL,
+		Token:     token,
+		KeyPrefix: policy.KeyPrefix,
+		PublicURL: s.domain,
+		MaxBytes:  policy.MaxBytes,
+		MimeTypes: policy.MimeTypes,
+		ExpiresAt: policy.ExpiresAt,
+	}, nil
+}
+
+// Put 服务端表单上传，scope 为 bucket:key 允许覆盖同名对象
+func (s *Qiniu) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error) {
+	token, err := s.uploadToken(&qiniuPutPolicy{
+		Scope:    s.bucket + ":" + key,
+		Deadline: time.Now().Add(qiniuServerTokenTTL).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	form.WriteField("token", token)
+	form.WriteField("key", key)
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, key))
+	header.Set("Content-Type", contentType)
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return "", err
+	}
+	if err := form.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.uploadURL, &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("qiniu upload failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return s.URL(key), nil
+}
+
+// Stat 通过资源管理接口查询对象信息
+func (s *Qiniu) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	path := "/stat/" + base64.URLEncoding.EncodeToString([]byte(s.bucket+":"+key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.rsHost+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	// 管理凭证：QBox accessKey:base64(HMAC-SHA1(path + "\n"))
+	req.Header.Set("Authorization", "QBox "+s.accessKey+":"+s.sign([]byte(path+"\n")))
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == qiniuStatusNotFound || resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("qiniu stat failed: status %d", resp.StatusCode)
+	}
+	var out struct {
+		Fsize    int64  `json:"fsize"`
+		MimeType string `json:"mimeType"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	return &ObjectInfo{Size: out.Fsize, ContentType: out.MimeType}, nil
+}
+
+// Delete 通过资源管理接口删除对象
+func (s *Qiniu) Delete(ctx context.Context, key string) error {
+	path := "/delete/" + base64.URLEncoding.EncodeToString([]byte(s.bucket+":"+key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.rsHost+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "QBox "+s.accessKey+":"+s.sign([]byte(path+"\n")))
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == qiniuStatusNotFound || resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return fmt.Errorf("qiniu delete failed: status %d", resp.StatusCode)
+}
+
+// URL 对象的 CDN 地址
+func (s *Qiniu) URL(key string) string {
+	return s.domain + "/" + key
+}
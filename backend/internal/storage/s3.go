@@ -0,0 +1,283 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3DefaultRegion   = "us-east-1"
+)
+
+// S3 S3 兼容存储（AWS S3、MinIO、Cloudflare R2 等），请求使用 Signature V4 签名
+type S3 struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	publicURL string
+	pathStyle bool
+	client    *http.Client
+}
+
+// NewS3 创建 S3 兼容存储；publicURL 为空时使用桶地址作为对象访问前缀
+func NewS3(endpoint, region, bucket, accessKey, secretKey, publicURL string, pathStyle bool, client *http.Client) (*S3, error) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	u, err := url.Parse(strings.TrimRight(endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("storage: invalid s3 endpoint: %w", err)
+	}
+	if region == "" {
+		region = s3DefaultRegion
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	s := &S3{
+		endpoint:  u,
+		region:    region,
+		bucket:    bucket,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		pathStyle: pathStyle,
+		client:    client,
+	}
+	if publicURL == "" {
+		publicURL = s.bucketURL()
+	}
+	s.publicURL = strings.TrimRight(publicURL, "/")
+	return s, nil
+}
+
+// Driver 驱动名称
+func (s *S3) Driver() string {
+	return DriverS3
+}
+
+// bucketURL 桶地址：路径风格为 endpoint/bucket，否则为 bucket.endpoint
+func (s *S3) bucketURL() string {
+	if s.pathStyle {
+		return s.endpoint.Scheme + "://" + s.endpoint.Host + "/" + s.bucket
+	}
+	return s.endpoint.Scheme + "://" + s.bucket + "." + s.endpoint.Host
+}
+
+// objectURL 对象的请求地址
+func (s *S3) objectURL(key string) string {
+	return s.bucketURL() + "/" + encodeS3Path(key)
+}
+
+// URL 对象的公开访问地址
+func (s *S3) URL(key string) string {
+	return s.publicURL + "/" + encodeS3Path(key)
+}
+
+// SignUpload 生成浏览器表单直传（POST Policy）凭证，key 前缀、大小、类型由策略条件限制
+func (s *S3) SignUpload(policy *UploadPolicy) (*UploadTicket, error) {
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	credential := s.accessKey + "/" + s.credentialScope(now)
+
+	conditions := []interface{}{
+		map[string]string{"bucket": s.bucket},
+		[]interface{}{"starts-with", "$key", policy.KeyPrefix},
+		[]interface{}{"starts-with", "$Content-Type", mimeConditionPrefix(policy.MimeTypes)},
+		map[string]string{"x-amz-algorithm": s3Algorithm},
+		map[string]string{"x-amz-credential": credential},
+		map[string]string{"x-amz-date": amzDate},
+	}
+	if policy.MaxBytes > 0 {
+		conditions = append(conditions, []interface{}{"content-length-range", 0, policy.MaxBytes})
+	}
+	doc, err := json.Marshal(map[string]interface{}{
+		"expiration": policy.ExpiresAt.UTC().Format("2006-01-02T15:04:05.000Z"),
+		"conditions": conditions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	encoded := base64.StdEncoding.EncodeToString(doc)
+	signature := hex.EncodeToString(hmacSHA256(s.signingKey(now), encoded))
+
+	return &UploadTicket{
+		Driver:    DriverS3,
+		UploadURL: s.bucketURL(),
+		Fields: map[string]string{
+			"policy":           encoded,
+			"x-amz-algorithm":  s3Algorithm,
+			"x-amz-credential": credential,
+			"x-amz-date":       amzDate,
+			"x-amz-signature":  signature,
+		},
+		KeyPrefix: policy.KeyPrefix,
+		PublicURL: s.publicURL,
+		MaxBytes:  policy.MaxBytes,
+		MimeTypes: policy.MimeTypes,
+		ExpiresAt: policy.ExpiresAt,
+	}, nil
+}
+
+// Put 写入对象（PUT Object）
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), r)
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	s.signRequest(req, time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("s3 put failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return s.URL(key), nil
+}
+
+// Stat 查询对象信息（HEAD Object）
+func (s *S3) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, s.objectURL(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	s.signRequest(req, time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("s3 head failed: status %d", resp.StatusCode)
+	}
+	return &ObjectInfo{Size: resp.ContentLength, ContentType: resp.Header.Get("Content-Type")}, nil
+}
+
+// Delete 删除对象（DELETE Object）
+func (s *S3) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+	s.signRequest(req, time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("s3 delete failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+}
+
+// signRequest 为请求添加 Signature V4 认证头，请求体不参与签名
+func (s *S3) signRequest(req *http.Request, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", s3UnsignedPayload)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(req.Header.Get(name))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		s3UnsignedPayload,
+	}, "\n")
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	scope := s.credentialScope(now)
+	stringToSign := s3Algorithm + "\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+	signature := hex.EncodeToString(hmacSHA256(s.signingKey(now), stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.accessKey, scope, signedHeaders, signature))
+}
+
+func (s *S3) credentialScope(t time.Time) string {
+	return t.Format("20060102") + "/" + s.region + "/s3/aws4_request"
+}
+
+func (s *S3) signingKey(t time.Time) []byte {
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), t.Format("20060102"))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	return hmacSHA256(key, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// encodeS3Path 按 RFC 3986 编码对象 key，保留路径分隔符
+func encodeS3Path(key string) string {
+	segments := strings.Split(key, "/")
+	for i, seg := range segments {
+		segments[i] = strings.ReplaceAll(url.PathEscape(seg), "+", "%2B")
+	}
+	return strings.Join(segments, "/")
+}
+
+// mimeConditionPrefix POST Policy 只能表达前缀匹配：取所有允许类型的公共前缀
+// （如 image/* 得到 image/），精确校验在上传完成回调中进行
+func mimeConditionPrefix(patterns []string) string {
+	if len(patterns) == 0 {
+		return ""
+	}
+	prefix := strings.TrimSuffix(patterns[0], "*")
+	for _, p := range patterns[1:] {
+		p = strings.TrimSuffix(p, "*")
+		for !strings.HasPrefix(p, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,169 @@
+// Package storage 对象存储：服务端写入（媒体归档）和客户端直传（插件上传图片）
+// 支持七牛云、S3 兼容存储和本地磁盘三种驱动
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// 存储驱动
+const (
+	DriverLocal = "local"
+	DriverQiniu = "qiniu"
+	DriverS3    = "s3"
+)
+
+var (
+	ErrNotFound      = errors.New("object not found")
+	ErrInvalidToken  = errors.New("invalid or expired upload token")
+	ErrPolicyViolate = errors.New("upload violates policy")
+)
+
+// Storage 对象存储
+type Storage interface {
+	// Driver 驱动名称
+	Driver() string
+	// Put 写入对象并返回可公开访问的 URL
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error)
+	// Stat 查询对象的大小和类型，不存在时返回 ErrNotFound
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// Delete 删除对象，对象不存在时不返回错误
+	Delete(ctx context.Context, key string) error
+	// URL 对象的公开访问地址
+	URL(key string) string
+	// SignUpload 生成客户端直传凭证，只允许写入 policy.KeyPrefix 下、满足大小和类型限制的对象
+	SignUpload(policy *UploadPolicy) (*UploadTicket, error)
+}
+
+// ObjectInfo 对象信息
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
+// UploadPolicy 直传策略
+type UploadPolicy struct {
+	KeyPrefix string    // 对象 key 必须以此开头（以 / 结尾）
+	MaxBytes  int64     // 单个文件大小上限
+	MimeTypes []string  // 允许的类型，支持 image/* 形式
+	ExpiresAt time.Time // 凭证过期时间
+}
+
+// Allows 检查对象是否满足策略
+func (p *UploadPolicy) Allows(key string, size int64, contentType string) error {
+	if !strings.HasPrefix(key, p.KeyPrefix) || len(key) == len(p.KeyPrefix) || strings.Contains(key, "..") {
+		return fmt.Errorf("%w: key must start with %s", ErrPolicyViolate, p.KeyPrefix)
+	}
+	if p.MaxBytes > 0 && size > p.MaxBytes {
+		return fmt.Errorf("%w: size %d exceeds %d bytes", ErrPolicyViolate, size, p.MaxBytes)
+	}
+	if !MatchMime(p.MimeTypes, contentType) {
+		return fmt.Errorf("%w: content type %q not allowed", ErrPolicyViolate, contentType)
+	}
+	return nil
+}
+
+// UploadTicket 直传凭证：客户端按驱动把文件以 multipart 表单 POST 到 UploadURL
+// - qiniu：表单字段 token、key、file（七牛云 JS SDK 可直接使用 Token）
+// - s3：表单字段为 Fields 加上 key、Content-Type、file
+// - local：表单字段 token、key、file
+type UploadTicket struct {
+	Driver    string            `json:"driver"`
+	UploadURL string            `json:"uploadUrl"`
+	Token     string            `json:"token,omitempty"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	KeyPrefix string            `json:"keyPrefix"`
+	PublicURL string            `json:"publicUrl"` // 对象地址为 publicUrl + "/" + key
+	MaxBytes  int64             `json:"maxBytes"`
+	MimeTypes []string          `json:"mimeTypes"`
+	ExpiresAt time.Time         `json:"expiresAt"`
+}
+
+// KeyFromURL 从 s.URL(key) 生成的地址反推对象 key；不是该存储的地址时返回 false
+func KeyFromURL(s Storage, objectURL string) (string, bool) {
+	prefix := s.URL("")
+	if !strings.HasPrefix(objectURL, prefix) || len(objectURL) == len(prefix) {
+		return "", false
+	}
+	key, err := url.PathUnescape(objectURL[len(prefix):])
+	if err != nil || strings.Contains(key, "..") {
+		return "", false
+	}
+	return key, true
+}
+
+// MatchMime 类型是否在允许列表中（支持 image/* 形式；列表为空表示不限制）
+func MatchMime(patterns []string, contentType string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	contentType = strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+	for _, p := range patterns {
+		p = strings.ToLower(p)
+		if p == contentType || (strings.HasSuffix(p, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(p, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// Config 存储配置
+type Config struct {
+	Driver string
+
+	// local
+	LocalDir       string
+	LocalBaseURL   string
+	LocalUploadURL string // 本地直传接口地址
+	SigningSecret  string // 本地直传凭证的签名密钥
+
+	// qiniu
+	QiniuAccessKey string
+	QiniuSecretKey string
+	QiniuBucket    string
+	QiniuDomain    string
+	QiniuUploadURL string
+	QiniuRSHost    string
+
+	// s3
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3PublicURL string
+	S3PathStyle bool
+}
+
+// New 按配置创建存储驱动
+func New(cfg Config, client *http.Client) (Storage, error) {
+	switch cfg.Driver {
+	case DriverLocal:
+		if cfg.SigningSecret == "" {
+			return nil, errors.New("storage: signing secret is required for local driver")
+		}
+		return NewLocal(cfg.LocalDir, cfg.LocalBaseURL, cfg.LocalUploadURL, cfg.SigningSecret), nil
+	case DriverQiniu:
+		if cfg.QiniuAccessKey == "" || cfg.QiniuSecretKey == "" || cfg.QiniuBucket == "" || cfg.QiniuDomain == "" {
+			return nil, errors.New("storage: qiniu access key, secret key, bucket and domain are required")
+		}
+		return NewQiniu(cfg.QiniuAccessKey, cfg.QiniuSecretKey, cfg.QiniuBucket, cfg.QiniuDomain, cfg.QiniuUploadURL, cfg.QiniuRSHost, client), nil
+	case DriverS3:
+		if cfg.S3Endpoint == "" || cfg.S3Bucket == "" || cfg.S3AccessKey == "" || cfg.S3SecretKey == "" {
+			return nil, errors.New("storage: s3 endpoint, bucket, access key and secret key are required")
+		}
+		s3, err := NewS3(cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket, cfg.S3AccessKey, cfg.S3SecretKey, cfg.S3PublicURL, cfg.S3PathStyle, client)
+		if err != nil {
+			return nil, err
+		}
+		return s3, nil
+	}
+	return nil, fmt.Errorf("storage: unknown driver %q", cfg.Driver)
+}
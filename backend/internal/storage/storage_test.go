@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestKeyFromURL(t *testing.T) {
+	s := NewLocal(t.TempDir(), "https://cdn.test/media/", "/upload", "secret")
+	tests := []struct {
+		url    string
+		want   string
+		wantOK bool
+	}{
+		{"https://cdn.test/media/notes/u1/abc.jpg", "notes/u1/abc.jpg", true},
+		{"https://cdn.test/media/uploads/u1/%E5%B0%81%E9%9D%A2.png", "uploads/u1/封面.png", true},
+		{"https://cdn.test/media/", "", false},
+		{"https://cdn.test/other/a.jpg", "", false},
+		{"https://sns-img.xhscdn.com/a.jpg", "", false},
+		{"https://cdn.test/media/notes/../../etc/passwd", "", false},
+		{"https://cdn.test/media/notes/%2e%2e/x", "", false},
+		{"https://cdn.test/media/bad%zz", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := KeyFromURL(s, tt.url)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("KeyFromURL(%q) = %q, %v, want %q, %v", tt.url, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestLocalDelete(t *testing.T) {
+	ctx := context.Background()
+	s := NewLocal(t.TempDir(), "https://cdn.test/media", "/upload", "secret")
+	if _, err := s.Put(ctx, "notes/u1/a.txt", strings.NewReader("hi"), 2, "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(ctx, "notes/u1/a.txt"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Stat(ctx, "notes/u1/a.txt"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Stat after delete err = %v, want ErrNotFound", err)
+	}
+	// 重复删除视为成功，队列重试时不会卡住
+	if err := s.Delete(ctx, "notes/u1/a.txt"); err != nil {
+		t.Errorf("second Delete: %v", err)
+	}
+	if err := s.Delete(ctx, "../outside"); err == nil {
+		t.Error("Delete should reject keys outside the root")
+	}
+}
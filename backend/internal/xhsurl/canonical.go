@@ -0,0 +1,72 @@
+// Package xhsurl 小红书笔记链接规范化
+// 同一篇笔记的链接可能带有易变参数（xsec_token / xsec_source 等）、使用 /explore/ 或
+// /discovery/item/ 等不同路径，或是 xhslink.com 短链；笔记去重以链接中的笔记 ID 为准
+package xhsurl
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// noteIDPattern 笔记 ID：24 位十六进制
+var noteIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{24}$`)
+
+// IsShortLink 是否为 xhslink.com 短链（需要请求后才能得到笔记 ID）
+func IsShortLink(raw string) bool {
+	u, err := parse(raw)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	return host == "xhslink.com" || strings.HasSuffix(host, ".xhslink.com")
+}
+
+// NoteID 从笔记链接中提取笔记 ID（小写），无法识别时返回空串
+// 支持 /explore/{id}、/discovery/item/{id}、/user/profile/{userId}/{id}、
+// xhsdiscover://item/{id} 以及 noteId 查询参数
+func NoteID(raw string) string {
+	u, err := parse(raw)
+	if err != nil {
+		return ""
+	}
+
+	if u.Scheme == "xhsdiscover" {
+		// xhsdiscover://item/{id}：host 为 item，路径为 /{id}
+		if u.Host == "item" {
+			return matchID(strings.Trim(u.Path, "/"))
+		}
+		return ""
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host != "xiaohongshu.com" && !strings.HasSuffix(host, ".xiaohongshu.com") {
+		return ""
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	switch {
+	case len(segments) >= 2 && segments[0] == "explore":
+		return matchID(segments[1])
+	case len(segments) >= 3 && segments[0] == "discovery" && segments[1] == "item":
+		return matchID(segments[2])
+	case len(segments) >= 4 && segments[0] == "user" && segments[1] == "profile":
+		return matchID(segments[3])
+	}
+	return matchID(u.Query().Get("noteId"))
+}
+
+func parse(raw string) (*url.URL, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	return url.Parse(raw)
+}
+
+func matchID(s string) string {
+	if noteIDPattern.MatchString(s) {
+		return strings.ToLower(s)
+	}
+	return ""
+}
@@ -0,0 +1,58 @@
+package xhsurl
+
+import "testing"
+
+const testID = "65a1b2c3d4e5f60718293a4b"
+
+func TestNoteID(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"explore", "https://www.xiaohongshu.com/explore/" + testID, testID},
+		{"explore with volatile params", "https://www.xiaohongshu.com/explore/" + testID + "?xsec_token=abc&xsec_source=pc_feed", testID},
+		{"discovery item", "https://www.xiaohongshu.com/discovery/item/" + testID + "?app_platform=ios", testID},
+		{"user profile", "https://www.xiaohongshu.com/user/profile/5f0e1d2c3b4a59687766554a/" + testID, testID},
+		{"noteId query", "https://edith.xiaohongshu.com/page?noteId=" + testID, testID},
+		{"uppercase id lowercased", "https://www.xiaohongshu.com/explore/65A1B2C3D4E5F60718293A4B", testID},
+		{"no scheme", "www.xiaohongshu.com/explore/" + testID, testID},
+		{"surrounding whitespace", "  https://xiaohongshu.com/explore/" + testID + "\n", testID},
+		{"app deep link", "xhsdiscover://item/" + testID, testID},
+		{"app deep link other host", "xhsdiscover://user/" + testID, ""},
+		{"other site", "https://example.com/explore/" + testID, ""},
+		{"lookalike host", "https://notxiaohongshu.com/explore/" + testID, ""},
+		{"short id", "https://www.xiaohongshu.com/explore/65a1b2c3", ""},
+		{"non-hex id", "https://www.xiaohongshu.com/explore/zza1b2c3d4e5f60718293a4b", ""},
+		{"profile without note", "https://www.xiaohongshu.com/user/profile/" + testID, ""},
+		{"short link", "https://xhslink.com/a/AbCdEf", ""},
+		{"empty", "", ""},
+		{"unparseable", "https://www.xiaohongshu.com/%zz", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NoteID(tt.raw); got != tt.want {
+				t.Errorf("NoteID(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsShortLink(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want bool
+	}{
+		{"http://xhslink.com/a/AbCdEf", true},
+		{"xhslink.com/AbCdEf", true},
+		{"https://www.XHSLINK.com/a/AbCdEf", true},
+		{"https://fakexhslink.com/a/AbCdEf", false},
+		{"https://www.xiaohongshu.com/explore/" + testID, false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsShortLink(tt.raw); got != tt.want {
+			t.Errorf("IsShortLink(%q) = %v, want %v", tt.raw, got, tt.want)
+		}
+	}
+}
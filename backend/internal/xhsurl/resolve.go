@@ -0,0 +1,60 @@
+package xhsurl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// maxRedirects 解析短链时最多跟随的跳转次数
+const maxRedirects = 5
+
+// ErrUnresolved 短链没有跳转到可识别的笔记链接
+var ErrUnresolved = errors.New("short link did not resolve to a note")
+
+// Resolver 解析 xhslink.com 短链：逐跳读取 Location，直到得到可识别笔记 ID 的链接
+type Resolver struct {
+	client *http.Client
+}
+
+// NewResolver 创建短链解析器，timeout 为单次解析的总超时
+func NewResolver(timeout time.Duration) *Resolver {
+	return &Resolver{client: &http.Client{
+		Timeout: timeout,
+		// 不自动跟随跳转，只读取 Location，避免下载笔记页面
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+// Resolve 返回短链跳转后的笔记链接；raw 不是短链时原样返回
+func (r *Resolver) Resolve(ctx context.Context, raw string) (string, error) {
+	if !IsShortLink(raw) {
+		return raw, nil
+	}
+
+	current := raw
+	for i := 0; i < maxRedirects; i++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, current, nil)
+		if err != nil {
+			return "", err
+		}
+		resp, err := r.client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		resp.Body.Close()
+
+		location, err := resp.Location()
+		if err != nil {
+			return "", ErrUnresolved
+		}
+		current = location.String()
+		if NoteID(current) != "" {
+			return current, nil
+		}
+	}
+	return "", ErrUnresolved
+}
@@ -0,0 +1,2 @@
+-- Drop note metric snapshots table
+DROP TABLE IF EXISTS note_metric_snapshots;
@@ -0,0 +1,24 @@
+-- Create note metric snapshots table
+-- 每次插件采集笔记时记录一次点赞/收藏/评论数，用于计算互动增长
+CREATE TABLE IF NOT EXISTS note_metric_snapshots (
+    id VARCHAR(255) PRIMARY KEY,
+    note_id VARCHAR(255) NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    likes INTEGER NOT NULL DEFAULT 0,
+    collects INTEGER NOT NULL DEFAULT 0,
+    comments INTEGER NOT NULL DEFAULT 0,
+    capture_timestamp BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for time series and growth queries
+CREATE INDEX IF NOT EXISTS idx_note_metric_snapshots_note_created ON note_metric_snapshots(note_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_note_metric_snapshots_user_id ON note_metric_snapshots(user_id);
+
+-- Seed one snapshot per existing note so growth has a baseline
+INSERT INTO note_metric_snapshots (id, note_id, user_id, likes, collects, comments, capture_timestamp, created_at)
+SELECT gen_random_uuid()::TEXT, id, user_id, likes, collects, comments, capture_timestamp, updated_at
+FROM notes;
+
+-- Add comment
+COMMENT ON TABLE note_metric_snapshots IS 'Per-capture engagement snapshots of notes';
@@ -0,0 +1,2 @@
+-- Drop note search terms table
+DROP TABLE IF EXISTS note_search_terms;
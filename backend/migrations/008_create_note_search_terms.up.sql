@@ -0,0 +1,17 @@
+-- Create note search terms table (inverted index for full-text search)
+-- 中文按单字 + 二元组切分，由应用层维护，不依赖 zhparser 等扩展
+-- 历史数据回填：go run ./scripts/reindex_note_search
+CREATE TABLE IF NOT EXISTS note_search_terms (
+    note_id VARCHAR(255) NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    term VARCHAR(64) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    title_tf INTEGER NOT NULL DEFAULT 0,
+    content_tf INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (note_id, term)
+);
+
+-- Create index for term lookups scoped to a user
+CREATE INDEX IF NOT EXISTS idx_note_search_terms_user_term ON note_search_terms(user_id, term);
+
+-- Add comment
+COMMENT ON TABLE note_search_terms IS 'Inverted index of note title/content terms (CJK bigrams + words)';
@@ -0,0 +1,3 @@
+-- Drop version column from notes and bloggers
+ALTER TABLE bloggers DROP COLUMN IF EXISTS version;
+ALTER TABLE notes DROP COLUMN IF EXISTS version;
@@ -0,0 +1,8 @@
+-- Add version column to notes and bloggers
+-- 每次写入递增，编辑接口以 ETag / If-Match 做乐观并发控制
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE bloggers ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+-- Add comment
+COMMENT ON COLUMN notes.version IS 'Optimistic concurrency version, incremented on every write';
+COMMENT ON COLUMN bloggers.version IS 'Optimistic concurrency version, incremented on every write';
@@ -0,0 +1,12 @@
+-- Drop soft delete from notes and bloggers
+-- 回收站中的记录会被永久删除
+DELETE FROM bloggers WHERE deleted_at IS NOT NULL;
+DELETE FROM notes WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_bloggers_xhs_id_live;
+ALTER TABLE bloggers ADD CONSTRAINT bloggers_xhs_id_key UNIQUE (xhs_id);
+
+DROP INDEX IF EXISTS idx_bloggers_deleted_at;
+DROP INDEX IF EXISTS idx_notes_deleted_at;
+ALTER TABLE bloggers DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE notes DROP COLUMN IF EXISTS deleted_at;
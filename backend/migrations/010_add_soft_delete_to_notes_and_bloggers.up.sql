@@ -0,0 +1,15 @@
+-- Add soft delete (trash bin) to notes and bloggers
+-- 删除操作只写入 deleted_at，回收站保留期满后由后台任务永久删除
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE bloggers ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_notes_deleted_at ON notes(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_bloggers_deleted_at ON bloggers(deleted_at);
+
+-- xhs_id 只在未删除的记录中唯一，回收站中的博主不阻止重新采集
+ALTER TABLE bloggers DROP CONSTRAINT IF EXISTS bloggers_xhs_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bloggers_xhs_id_live ON bloggers(xhs_id) WHERE deleted_at IS NULL;
+
+-- Add comment
+COMMENT ON COLUMN notes.deleted_at IS 'Soft delete time; non-null rows are in the trash bin';
+COMMENT ON COLUMN bloggers.deleted_at IS 'Soft delete time; non-null rows are in the trash bin';
@@ -0,0 +1,3 @@
+-- Drop collection tables
+DROP TABLE IF EXISTS collection_notes;
+DROP TABLE IF EXISTS collections;
@@ -0,0 +1,29 @@
+-- Create collections table (user-defined boards for organizing notes)
+CREATE TABLE IF NOT EXISTS collections (
+    id VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- 同一用户下收藏夹名称唯一
+CREATE UNIQUE INDEX IF NOT EXISTS idx_collections_user_name ON collections(user_id, name);
+
+-- Create collection membership table (many-to-many between collections and notes)
+CREATE TABLE IF NOT EXISTS collection_notes (
+    collection_id VARCHAR(255) NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    note_id VARCHAR(255) NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    added_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (collection_id, note_id)
+);
+
+-- Create indexes for ordered listing and reverse lookup
+CREATE INDEX IF NOT EXISTS idx_collection_notes_position ON collection_notes(collection_id, position);
+CREATE INDEX IF NOT EXISTS idx_collection_notes_note_id ON collection_notes(note_id);
+
+-- Add comment
+COMMENT ON TABLE collections IS 'User-defined collections (boards) of notes';
+COMMENT ON TABLE collection_notes IS 'Collection membership with per-collection ordering';
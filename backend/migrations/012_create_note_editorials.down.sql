@@ -0,0 +1,3 @@
+-- Drop note editorial tables
+DROP TABLE IF EXISTS note_status_changes;
+DROP TABLE IF EXISTS note_editorials;
@@ -0,0 +1,31 @@
+-- Create note editorials table (workflow status, rating and private remarks)
+-- 与 notes 分表存储，重新采集不会覆盖；没有记录的笔记视为 inbox、未评级
+CREATE TABLE IF NOT EXISTS note_editorials (
+    note_id VARCHAR(255) PRIMARY KEY REFERENCES notes(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'inbox',
+    rating SMALLINT NOT NULL DEFAULT 0 CHECK (rating BETWEEN 0 AND 5),
+    remarks TEXT,
+    status_changed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_note_editorials_user_status ON note_editorials(user_id, status);
+
+-- Create note status changes table (timestamped workflow transitions)
+CREATE TABLE IF NOT EXISTS note_status_changes (
+    id VARCHAR(255) PRIMARY KEY,
+    note_id VARCHAR(255) NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_note_status_changes_note_id ON note_status_changes(note_id);
+CREATE INDEX IF NOT EXISTS idx_note_status_changes_user_changed ON note_status_changes(user_id, changed_at);
+
+-- Add comment
+COMMENT ON TABLE note_editorials IS 'Per-note editorial fields, never overwritten by re-capture';
+COMMENT ON TABLE note_status_changes IS 'Timestamped editorial workflow status transitions';
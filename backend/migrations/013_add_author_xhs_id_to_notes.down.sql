@@ -0,0 +1,3 @@
+-- Remove author_xhs_id column from notes
+DROP INDEX IF EXISTS idx_notes_user_author_xhs_id;
+ALTER TABLE notes DROP COLUMN IF EXISTS author_xhs_id;
@@ -0,0 +1,22 @@
+-- Add author_xhs_id column to notes (links a note to Blogger.xhs_id)
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS author_xhs_id VARCHAR(50);
+
+-- Create index for per-blogger note lookups
+CREATE INDEX IF NOT EXISTS idx_notes_user_author_xhs_id ON notes(user_id, author_xhs_id);
+
+-- Backfill: 作者名称与用户的博主名称一致且该名称只对应一个博主时才关联
+UPDATE notes n
+SET author_xhs_id = b.xhs_id
+FROM bloggers b
+WHERE (n.author_xhs_id IS NULL OR n.author_xhs_id = '')
+  AND n.author <> ''
+  AND b.user_id = n.user_id
+  AND b.blogger_name = n.author
+  AND b.deleted_at IS NULL
+  AND (
+      SELECT COUNT(*) FROM bloggers b2
+      WHERE b2.user_id = n.user_id AND b2.blogger_name = n.author AND b2.deleted_at IS NULL
+  ) = 1;
+
+-- Add comment
+COMMENT ON COLUMN notes.author_xhs_id IS 'XHS user ID of the note author, matches bloggers.xhs_id';
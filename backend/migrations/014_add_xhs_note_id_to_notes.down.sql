@@ -0,0 +1,3 @@
+-- Remove xhs_note_id column from notes
+DROP INDEX IF EXISTS idx_notes_user_xhs_note_id;
+ALTER TABLE notes DROP COLUMN IF EXISTS xhs_note_id;
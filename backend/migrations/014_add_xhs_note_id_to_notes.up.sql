@@ -0,0 +1,10 @@
+-- Add xhs_note_id column to notes (note ID extracted from the XHS URL, used for deduplication)
+-- 同一篇笔记的链接可能带不同的 xsec_token 等参数或使用不同路径，去重以笔记 ID 为准
+-- 历史数据回填并合并重复记录：go run ./scripts/merge_duplicate_notes
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS xhs_note_id VARCHAR(32);
+
+-- Create index for upsert lookups
+CREATE INDEX IF NOT EXISTS idx_notes_user_xhs_note_id ON notes(user_id, xhs_note_id);
+
+-- Add comment
+COMMENT ON COLUMN notes.xhs_note_id IS 'XHS note ID extracted from the URL, deduplication key';
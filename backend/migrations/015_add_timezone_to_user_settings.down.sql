@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_bloggers_user_created_at;
+DROP INDEX IF EXISTS idx_notes_user_created_at;
+ALTER TABLE user_settings DROP COLUMN IF EXISTS timezone;
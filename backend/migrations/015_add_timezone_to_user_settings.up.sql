@@ -0,0 +1,9 @@
+-- Add timezone column to user_settings (daily collection quota follows the user's calendar day)
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Shanghai';
+
+-- Index for daily quota counts
+CREATE INDEX IF NOT EXISTS idx_notes_user_created_at ON notes(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_bloggers_user_created_at ON bloggers(user_id, created_at);
+
+-- Add comment
+COMMENT ON COLUMN user_settings.timezone IS '用户时区（IANA 名称），每日采集上限按该时区的自然日计算';
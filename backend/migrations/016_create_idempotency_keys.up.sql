@@ -0,0 +1,19 @@
+-- Create idempotency_keys table (replay plugin sync responses for retried requests)
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id VARCHAR(255) NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    request_hash VARCHAR(64) NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    response_body BYTEA,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (user_id, key)
+);
+
+-- Create index for expiry cleanup
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+
+-- Add comment
+COMMENT ON TABLE idempotency_keys IS 'Idempotency-Key records for plugin sync requests';
+COMMENT ON COLUMN idempotency_keys.request_hash IS 'SHA-256 of method, path and body; reusing a key with a different request is rejected';
+COMMENT ON COLUMN idempotency_keys.status_code IS '0 while the first request is still in progress';
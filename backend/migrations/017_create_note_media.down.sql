@@ -0,0 +1,5 @@
+-- Drop note_media table and archived media columns
+DROP TABLE IF EXISTS note_media;
+ALTER TABLE notes DROP COLUMN IF EXISTS archived_video_url;
+ALTER TABLE notes DROP COLUMN IF EXISTS archived_cover_image_url;
+ALTER TABLE notes DROP COLUMN IF EXISTS archived_image_urls;
@@ -0,0 +1,30 @@
+-- Add archived media columns to notes (stable copies of XHS CDN images and videos)
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS archived_image_urls TEXT[];
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS archived_cover_image_url VARCHAR(500);
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS archived_video_url VARCHAR(500);
+
+-- Create note_media table (archive queue and results, one row per note and source URL)
+CREATE TABLE IF NOT EXISTS note_media (
+    note_id VARCHAR(255) NOT NULL,
+    source_url TEXT NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    archived_url VARCHAR(500),
+    content_type VARCHAR(100),
+    size BIGINT NOT NULL DEFAULT 0,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    archived_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (note_id, source_url)
+);
+
+-- Create index for picking due archive jobs
+CREATE INDEX IF NOT EXISTS idx_note_media_due ON note_media(status, next_attempt_at);
+
+-- Add comment
+COMMENT ON TABLE note_media IS 'Media referenced by notes and their archive status';
+COMMENT ON COLUMN notes.archived_image_urls IS 'Archived copies aligned with image_urls, empty string when not archived';
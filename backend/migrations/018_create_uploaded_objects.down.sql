@@ -0,0 +1,2 @@
+-- Drop uploaded_objects table
+DROP TABLE IF EXISTS uploaded_objects;
@@ -0,0 +1,22 @@
+-- Create uploaded_objects table (files uploaded by users with scoped upload tokens)
+CREATE TABLE IF NOT EXISTS uploaded_objects (
+    id VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    note_id VARCHAR(255),
+    xhs_note_id VARCHAR(64),
+    driver VARCHAR(20) NOT NULL,
+    key VARCHAR(500) NOT NULL,
+    url VARCHAR(1000) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    content_type VARCHAR(100),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE UNIQUE INDEX IF NOT EXISTS idx_uploaded_objects_user_key ON uploaded_objects(user_id, key);
+CREATE INDEX IF NOT EXISTS idx_uploaded_objects_note_id ON uploaded_objects(note_id);
+CREATE INDEX IF NOT EXISTS idx_uploaded_objects_xhs_note_id ON uploaded_objects(xhs_note_id);
+
+-- Add comment
+COMMENT ON TABLE uploaded_objects IS 'Objects uploaded directly to storage, recorded by the upload completion callback';
+COMMENT ON COLUMN uploaded_objects.xhs_note_id IS 'XHS note ID from the note URL, used to attach uploads made before the note was saved';
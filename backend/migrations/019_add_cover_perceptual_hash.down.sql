@@ -0,0 +1,8 @@
+-- Drop cover thumbnail and perceptual hash columns
+DROP INDEX IF EXISTS idx_notes_user_cover_dhash;
+ALTER TABLE notes DROP COLUMN IF EXISTS cover_ahash;
+ALTER TABLE notes DROP COLUMN IF EXISTS cover_dhash;
+ALTER TABLE notes DROP COLUMN IF EXISTS cover_thumbnail_url;
+ALTER TABLE note_media DROP COLUMN IF EXISTS ahash;
+ALTER TABLE note_media DROP COLUMN IF EXISTS dhash;
+ALTER TABLE note_media DROP COLUMN IF EXISTS thumbnail_url;
@@ -0,0 +1,16 @@
+-- Add thumbnails and perceptual hashes of archived images
+ALTER TABLE note_media ADD COLUMN IF NOT EXISTS thumbnail_url VARCHAR(500);
+ALTER TABLE note_media ADD COLUMN IF NOT EXISTS dhash BIGINT;
+ALTER TABLE note_media ADD COLUMN IF NOT EXISTS ahash BIGINT;
+
+-- Add cover thumbnail and hashes to notes (copied from the cover's note_media row)
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS cover_thumbnail_url VARCHAR(500);
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS cover_dhash BIGINT;
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS cover_ahash BIGINT;
+
+-- Create index for loading a user's cover hashes
+CREATE INDEX IF NOT EXISTS idx_notes_user_cover_dhash ON notes(user_id) WHERE cover_dhash IS NOT NULL;
+
+-- Add comment
+COMMENT ON COLUMN notes.cover_dhash IS '64-bit difference hash of the cover image, compared by Hamming distance';
+COMMENT ON COLUMN notes.cover_ahash IS '64-bit average hash of the cover image, compared by Hamming distance';
@@ -0,0 +1,2 @@
+-- Drop note_content_terms table
+DROP TABLE IF EXISTS note_content_terms;
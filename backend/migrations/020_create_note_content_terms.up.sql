@@ -0,0 +1,15 @@
+-- Create note_content_terms table (hashtags, mentions, emoji and keywords extracted from notes)
+CREATE TABLE IF NOT EXISTS note_content_terms (
+    note_id VARCHAR(255) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    term VARCHAR(64) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    count INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (note_id, kind, term)
+);
+
+-- Create index for per-user aggregation
+CREATE INDEX IF NOT EXISTS idx_note_content_terms_user_kind_term ON note_content_terms(user_id, kind, term);
+
+-- Add comment
+COMMENT ON TABLE note_content_terms IS 'Content analysis of notes, rebuilt whenever a note is written; backfill with scripts/reindex_note_search';
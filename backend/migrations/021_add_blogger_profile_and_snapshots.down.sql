@@ -0,0 +1,9 @@
+-- Drop blogger metric snapshots table and profile fields
+DROP TABLE IF EXISTS blogger_metric_snapshots;
+
+ALTER TABLE bloggers DROP COLUMN IF EXISTS category;
+ALTER TABLE bloggers DROP COLUMN IF EXISTS verified_label;
+ALTER TABLE bloggers DROP COLUMN IF EXISTS verified;
+ALTER TABLE bloggers DROP COLUMN IF EXISTS ip_location;
+ALTER TABLE bloggers DROP COLUMN IF EXISTS likes_collects_count;
+ALTER TABLE bloggers DROP COLUMN IF EXISTS following_count;
@@ -0,0 +1,35 @@
+-- Extend bloggers with profile fields captured by the plugin
+ALTER TABLE bloggers ADD COLUMN IF NOT EXISTS following_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE bloggers ADD COLUMN IF NOT EXISTS likes_collects_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE bloggers ADD COLUMN IF NOT EXISTS ip_location VARCHAR(50);
+ALTER TABLE bloggers ADD COLUMN IF NOT EXISTS verified BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE bloggers ADD COLUMN IF NOT EXISTS verified_label VARCHAR(100);
+ALTER TABLE bloggers ADD COLUMN IF NOT EXISTS category VARCHAR(50);
+
+-- Create blogger metric snapshots table
+-- 每次插件采集博主时记录一次粉丝数 / 关注数 / 获赞与收藏，用于计算粉丝增长
+CREATE TABLE IF NOT EXISTS blogger_metric_snapshots (
+    id VARCHAR(255) PRIMARY KEY,
+    blogger_id VARCHAR(255) NOT NULL REFERENCES bloggers(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    followers_count INTEGER NOT NULL DEFAULT 0,
+    following_count INTEGER NOT NULL DEFAULT 0,
+    likes_collects_count INTEGER NOT NULL DEFAULT 0,
+    capture_timestamp BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for history and growth queries
+CREATE INDEX IF NOT EXISTS idx_blogger_metric_snapshots_blogger_created ON blogger_metric_snapshots(blogger_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_blogger_metric_snapshots_user_id ON blogger_metric_snapshots(user_id);
+
+-- Seed one snapshot per existing blogger so growth has a baseline
+INSERT INTO blogger_metric_snapshots (id, blogger_id, user_id, followers_count, capture_timestamp, created_at)
+SELECT gen_random_uuid()::TEXT, id, user_id, followers_count, capture_timestamp, updated_at
+FROM bloggers;
+
+-- Add comments
+COMMENT ON TABLE blogger_metric_snapshots IS 'Per-capture follower snapshots of bloggers';
+COMMENT ON COLUMN bloggers.likes_collects_count IS 'Total likes and collects shown on the profile';
+COMMENT ON COLUMN bloggers.ip_location IS 'IP location shown on the profile';
+COMMENT ON COLUMN bloggers.category IS 'Creator category or niche';
@@ -0,0 +1,6 @@
+-- Restore global blogger uniqueness
+-- 注意：升级时拆分出的博主和升级后多个用户采集的同一博主会使恢复全局唯一索引失败，需要先手动清理重复记录
+DROP TABLE IF EXISTS blogger_profiles;
+
+DROP INDEX IF EXISTS idx_bloggers_user_xhs_id_live;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bloggers_xhs_id_live ON bloggers(xhs_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,64 @@
+-- Make blogger uniqueness per user and share public profile data
+-- 博主按 (user_id, xhs_id) 唯一，不同用户可以各自采集同一博主
+
+-- 1. 全局唯一索引改为按用户唯一（仍只约束未删除的记录）
+DROP INDEX IF EXISTS idx_bloggers_xhs_id_live;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bloggers_user_xhs_id_live ON bloggers(user_id, xhs_id) WHERE deleted_at IS NULL;
+
+-- 2. 按小红书号共享的主页公开数据，以最近一次采集为准（采集时间不晚于迁移时间）
+CREATE TABLE IF NOT EXISTS blogger_profiles (
+    xhs_id VARCHAR(50) PRIMARY KEY,
+    blogger_name VARCHAR(100),
+    avatar_url VARCHAR(500),
+    description TEXT,
+    followers_count INTEGER NOT NULL DEFAULT 0,
+    following_count INTEGER NOT NULL DEFAULT 0,
+    likes_collects_count INTEGER NOT NULL DEFAULT 0,
+    ip_location VARCHAR(50),
+    verified BOOLEAN NOT NULL DEFAULT FALSE,
+    verified_label VARCHAR(100),
+    blogger_url VARCHAR(500),
+    capture_timestamp BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO blogger_profiles (xhs_id, blogger_name, avatar_url, description, followers_count, following_count,
+    likes_collects_count, ip_location, verified, verified_label, blogger_url, capture_timestamp, updated_at)
+SELECT DISTINCT ON (xhs_id) xhs_id, blogger_name, avatar_url, description, followers_count, following_count,
+    likes_collects_count, ip_location, verified, verified_label, blogger_url,
+    LEAST(capture_timestamp, (EXTRACT(EPOCH FROM NOW()) * 1000)::BIGINT), updated_at
+FROM bloggers
+WHERE xhs_id IS NOT NULL AND xhs_id <> ''
+ORDER BY xhs_id, capture_timestamp DESC, updated_at DESC
+ON CONFLICT (xhs_id) DO NOTHING;
+
+-- 3. 拆分被共用的博主：每个采集或关联了该博主的用户各有一行
+-- 全局唯一约束下博主行只属于最先采集的用户（保留原行和其分类、回收站状态、粉丝快照），
+-- 其他用户只能通过笔记的 author_xhs_id 关联到该博主；为这些用户各自建立博主记录（已有自己记录的用户除外，含回收站中的），
+-- 公开数据取自 blogger_profiles，跟踪字段按该用户自己的笔记计算：
+-- 创建时间为最早关联笔记的创建时间（不占用当日采集额度），采集时间为最近一次采集其笔记的时间，分类为空、版本从 1 开始
+INSERT INTO bloggers (id, user_id, xhs_id, blogger_name, avatar_url, description, followers_count, following_count,
+    likes_collects_count, ip_location, verified, verified_label, category, blogger_url, capture_timestamp, version, created_at, updated_at)
+SELECT gen_random_uuid(), t.user_id, p.xhs_id, p.blogger_name, p.avatar_url, p.description, p.followers_count, p.following_count,
+    p.likes_collects_count, p.ip_location, p.verified, p.verified_label, '', p.blogger_url, t.last_capture_timestamp, 1,
+    t.first_created_at, NOW()
+FROM (
+    SELECT n.user_id, n.author_xhs_id, MIN(n.created_at) AS first_created_at, MAX(n.capture_timestamp) AS last_capture_timestamp
+    FROM notes n
+    WHERE n.deleted_at IS NULL AND n.author_xhs_id IS NOT NULL AND n.author_xhs_id <> ''
+    GROUP BY n.user_id, n.author_xhs_id
+) t
+JOIN blogger_profiles p ON p.xhs_id = t.author_xhs_id
+WHERE NOT EXISTS (
+    SELECT 1 FROM bloggers b
+    WHERE b.xhs_id = t.author_xhs_id AND b.user_id::TEXT = t.user_id::TEXT
+);
+
+-- 拆分出的博主各写入一条属于该用户的粉丝快照，作为增长基准
+INSERT INTO blogger_metric_snapshots (id, blogger_id, user_id, followers_count, following_count, likes_collects_count, capture_timestamp, created_at)
+SELECT gen_random_uuid()::TEXT, b.id, b.user_id, b.followers_count, b.following_count, b.likes_collects_count, b.capture_timestamp, b.created_at
+FROM bloggers b
+WHERE NOT EXISTS (SELECT 1 FROM blogger_metric_snapshots s WHERE s.blogger_id::TEXT = b.id::TEXT);
+
+-- Add comment
+COMMENT ON TABLE blogger_profiles IS 'Public blogger profile data shared across users, keyed by xhs_id';
@@ -0,0 +1,14 @@
+-- Create storage_deletions table (stored objects queued for deletion after notes are purged)
+CREATE TABLE IF NOT EXISTS storage_deletions (
+    url VARCHAR(1000) PRIMARY KEY,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_storage_deletions_next_attempt_at ON storage_deletions(next_attempt_at);
+
+-- Add comment
+COMMENT ON TABLE storage_deletions IS 'Archived media and uploaded files no longer referenced by any note, deleted from storage by a background job';
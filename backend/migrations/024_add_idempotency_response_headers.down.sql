@@ -0,0 +1,2 @@
+-- Drop replayable response headers from idempotency keys
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS response_headers;
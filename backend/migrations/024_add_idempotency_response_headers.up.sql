@@ -0,0 +1,4 @@
+-- Store replayable response headers (ETag, X-Quota-*) with idempotency keys
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS response_headers JSONB;
+
+COMMENT ON COLUMN idempotency_keys.response_headers IS 'Response headers replayed with the stored response, e.g. ETag and X-Quota-*';
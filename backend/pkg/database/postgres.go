@@ -51,6 +51,28 @@ func InitDatabase(cfg *config.Config) error {
 	return nil
 }
 
+// Models AutoMigrate 迁移的模型（user_settings、api_keys 只由迁移脚本创建）
+func Models() []interface{} {
+	return []interface{}{
+		&model.User{},
+		&model.Note{},
+		&model.NoteMetricSnapshot{},
+		&model.NoteSearchTerm{},
+		&model.NoteContentTerm{},
+		&model.Blogger{},
+		&model.BloggerMetricSnapshot{},
+		&model.BloggerProfile{},
+		&model.Collection{},
+		&model.CollectionNote{},
+		&model.NoteEditorial{},
+		&model.NoteStatusChange{},
+		&model.IdempotencyKey{},
+		&model.NoteMedia{},
+		&model.UploadedObject{},
+		&model.StorageDeletion{},
+	}
+}
+
 // AutoMigrate 自动迁移数据库表结构
 // 开发环境使用，生产环境应使用迁移脚本
 func AutoMigrate() error {
@@ -59,16 +81,19 @@ func AutoMigrate() error {
 	}
 
 	// 迁移所有模型
-	err := DB.AutoMigrate(
-		&model.User{},
-		&model.Note{},
-		&model.Blogger{},
-	)
+	err := DB.AutoMigrate(Models()...)
 
 	if err != nil {
 		return fmt.Errorf("failed to auto migrate: %w", err)
 	}
 
+	// 博主唯一约束已改为按用户隔离（见 022 迁移），AutoMigrate 不会删除旧的全局唯一索引
+	if DB.Migrator().HasIndex(&model.Blogger{}, "idx_bloggers_xhs_id_live") {
+		if err := DB.Migrator().DropIndex(&model.Blogger{}, "idx_bloggers_xhs_id_live"); err != nil {
+			return fmt.Errorf("failed to drop global blogger xhs_id index: %w", err)
+		}
+	}
+
 	log.Println("Database migration completed successfully")
 	return nil
 }
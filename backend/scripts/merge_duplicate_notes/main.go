@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/keenchase/edit-business/internal/config"
+	"github.com/keenchase/edit-business/internal/model"
+	"github.com/keenchase/edit-business/internal/repository"
+	"github.com/keenchase/edit-business/pkg/database"
+)
+
+// 回填历史笔记的小红书笔记 ID（xhs_note_id），并合并同一篇笔记的重复记录（每组保留最完整的一条）
+// 用法：go run ./scripts/merge_duplicate_notes [-dry-run]
+func main() {
+	dryRun := flag.Bool("dry-run", false, "只打印将要合并的记录，不做修改")
+	flag.Parse()
+
+	cfg := config.LoadConfig()
+	if err := database.InitDatabase(cfg); err != nil {
+		log.Fatalf("Failed to connect: %v", err)
+	}
+	defer database.CloseDatabase()
+
+	db := database.GetDB()
+	noteRepo := repository.NewNoteRepository(db)
+
+	var userIDs []string
+	if err := db.Unscoped().Model(&model.Note{}).Distinct("user_id").Pluck("user_id", &userIDs).Error; err != nil {
+		log.Fatalf("Failed to list users: %v", err)
+	}
+
+	backfilled, merged := 0, 0
+	for _, userID := range userIDs {
+		if !*dryRun {
+			count, err := noteRepo.BackfillXhsNoteIDs(userID)
+			if err != nil {
+				log.Fatalf("Failed to backfill user %s: %v", userID, err)
+			}
+			backfilled += count
+		}
+
+		groups, err := noteRepo.MergeDuplicates(userID, *dryRun)
+		if err != nil {
+			log.Fatalf("Failed to merge user %s: %v", userID, err)
+		}
+		for _, g := range groups {
+			fmt.Printf("用户 %s: 笔记 %s 保留 %s，合并 %v\n", userID, g.XhsNoteID, g.KeepID, g.MergedIDs)
+			merged += len(g.MergedIDs)
+		}
+	}
+
+	if *dryRun {
+		fmt.Printf("=== 预览完成（未回填笔记 ID 的记录不参与预览），将合并 %d 条重复记录 ===\n", merged)
+		return
+	}
+	fmt.Printf("=== 完成，回填 %d 条笔记 ID，合并 %d 条重复记录 ===\n", backfilled, merged)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/keenchase/edit-business/internal/config"
+	"github.com/keenchase/edit-business/internal/model"
+	"github.com/keenchase/edit-business/internal/repository"
+	"github.com/keenchase/edit-business/pkg/database"
+)
+
+// 为历史笔记回填全文检索索引（note_search_terms）和内容分析结果（note_content_terms）
+// 用法：go run ./scripts/reindex_note_search
+func main() {
+	cfg := config.LoadConfig()
+	if err := database.InitDatabase(cfg); err != nil {
+		log.Fatalf("Failed to connect: %v", err)
+	}
+	defer database.CloseDatabase()
+
+	db := database.GetDB()
+	noteRepo := repository.NewNoteRepository(db)
+
+	var userIDs []string
+	if err := db.Model(&model.Note{}).Distinct("user_id").Pluck("user_id", &userIDs).Error; err != nil {
+		log.Fatalf("Failed to list users: %v", err)
+	}
+
+	total := 0
+	for _, userID := range userIDs {
+		count, err := noteRepo.Reindex(userID)
+		if err != nil {
+			log.Fatalf("Failed to reindex user %s: %v", userID, err)
+		}
+		fmt.Printf("用户 %s: 已索引 %d 篇笔记\n", userID, count)
+		total += count
+	}
+	fmt.Printf("=== 完成，共索引 %d 篇笔记 ===\n", total)
+}
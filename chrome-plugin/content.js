@@ -115,6 +115,25 @@ function getElementByXPath(xpath, context = document) {
 }
 
 // 提取创作者信息函数 - 修改为动态提取值
+// 解析元素中的计数文字，支持万、k等单位，未找到时为 0
+function parseCountText(element) {
+  if (!element) {
+    return 0;
+  }
+  const text = element.textContent.trim();
+  const match = text.match(/([\d.]+)/);
+  if (!match || !match[1]) {
+    return 0;
+  }
+  let count = parseFloat(match[1]);
+  if (text.includes('万')) {
+    count *= 10000;
+  } else if (text.includes('k') || text.includes('K')) {
+    count *= 1000;
+  }
+  return Math.floor(count);
+}
+
 async function extractBloggerInfo() {
   console.log('开始提取创作者信息');
   
@@ -227,6 +246,33 @@ async function extractBloggerInfo() {
     }
   }
   
+  // 提取关注数、获赞与收藏 - 与粉丝数同在互动区，依次为 关注 / 粉丝 / 获赞与收藏
+  const followingCount = parseCountText(
+    document.querySelector('.user-interactions > div:nth-child(1) .count') ||
+    document.evaluate("//div[contains(text(), '关注')]", document, null, XPathResult.FIRST_ORDERED_NODE_TYPE, null).singleNodeValue
+  );
+  const likesCollectsCount = parseCountText(
+    document.querySelector('.user-interactions > div:nth-child(3) .count') ||
+    document.evaluate("//div[contains(text(), '获赞与收藏')]", document, null, XPathResult.FIRST_ORDERED_NODE_TYPE, null).singleNodeValue
+  );
+
+  // 提取IP属地 - 形如「IP属地：上海」
+  let ipLocation = '';
+  const ipElement = document.querySelector('.user-IP, .ip-location') ||
+    document.evaluate("//span[contains(text(), 'IP属地')]", document, null, XPathResult.FIRST_ORDERED_NODE_TYPE, null).singleNodeValue;
+  if (ipElement) {
+    ipLocation = ipElement.textContent.replace(/IP属地[：:]?/, '').trim();
+  }
+
+  // 提取认证标识 - 认证说明通常在图标的 title 或相邻文字中
+  let verified = false;
+  let verifiedLabel = '';
+  const verifyElement = document.querySelector('.verify-icon, .user-verify, .verified-icon, .red-verify');
+  if (verifyElement) {
+    verified = true;
+    verifiedLabel = (verifyElement.getAttribute('title') || verifyElement.textContent || '').trim();
+  }
+
   // 构建创作者信息对象 - 确保属性名与sidebar.js中使用的一致
   const bloggerInfo = {
     avatarUrl: avatarUrl,        // 头像URL
@@ -234,6 +280,11 @@ async function extractBloggerInfo() {
     description: bloggerBio,     // 创作者简介
     bloggerId: xhsId,            // 平台号
     followersCount: followersCount, // 粉丝数
+    followingCount: followingCount, // 关注数
+    likesCollectsCount: likesCollectsCount, // 获赞与收藏
+    ipLocation: ipLocation,      // IP属地
+    verified: verified,          // 是否认证
+    verifiedLabel: verifiedLabel, // 认证说明
     bloggerUrl: profileUrl,      // 创作者主页链接
     captureTimestamp: new Date().getTime() // 收藏时间戳
   };
@@ -1163,6 +1163,11 @@ function syncBloggerInfoToFeishu() {
     avatarUrl: capturedBloggerInfo.avatarUrl || '',
     description: capturedBloggerInfo.description || '',
     followersCount: Number(capturedBloggerInfo.followersCount || 0),
+    followingCount: Number(capturedBloggerInfo.followingCount || 0),
+    likesCollectsCount: Number(capturedBloggerInfo.likesCollectsCount || 0),
+    ipLocation: capturedBloggerInfo.ipLocation || '',
+    verified: Boolean(capturedBloggerInfo.verified),
+    verifiedLabel: capturedBloggerInfo.verifiedLabel || '',
     bloggerUrl: capturedBloggerInfo.bloggerUrl || '',
     captureTimestamp: Date.now()
   };
@@ -1345,13 +1350,41 @@ function uploadToQiniu(file, key, token) {
   });
 }
 
+/**
+ * 上传完成回调：后端核对文件并记录到当前用户和笔记下（失败不影响同步）
+ * @param {string} key - 文件key（路径）
+ * @param {string} noteUrl - 所属笔记链接
+ */
+async function reportUploadComplete(key, noteUrl = '') {
+  const apiKey = localStorage.getItem('edit-business-api-key');
+  if (!apiKey) {
+    return;
+  }
+  try {
+    const response = await fetch(API_CONFIG.BASE_URL + '/api/v1/storage/uploads', {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+        'X-API-Key': apiKey
+      },
+      body: JSON.stringify({ key, noteUrl })
+    });
+    if (!response.ok) {
+      console.warn('上传完成回调失败:', key, response.status);
+    }
+  } catch (error) {
+    console.warn('上传完成回调失败:', key, error);
+  }
+}
+
 /**
  * 处理单张图片URL：下载 -> 上传 -> 返回CDN URL
  * @param {string} imageUrl - 原始图片URL
  * @param {number} index - 图片索引（用于生成文件名）
+ * @param {string} noteUrl - 所属笔记链接（用于上传完成回调）
  * @returns {Promise<string>} CDN URL
  */
-async function processSingleImage(imageUrl, index = 0) {
+async function processSingleImage(imageUrl, index = 0, noteUrl = '') {
   try {
     // 1. 下载图片（需保持小红书页面为当前标签页）
     let blob;
@@ -1398,6 +1431,7 @@ async function processSingleImage(imageUrl, index = 0) {
     // 4. 上传到七牛云
     try {
       const cdnUrl = await uploadToQiniu(blob, key, tokenData.uploadToken);
+      reportUploadComplete(key, noteUrl);
       return cdnUrl;
     } catch (e) {
       const msg = e.message || String(e);
@@ -1420,9 +1454,10 @@ async function processSingleImage(imageUrl, index = 0) {
  * 批量处理图片URLs
  * @param {string[]} imageUrls - 图片URL数组
  * @param {function} progressCallback - 进度回调
+ * @param {string} noteUrl - 所属笔记链接（用于上传完成回调）
  * @returns {Promise<string[]>} CDN URL数组
  */
-async function processImageUrls(imageUrls, progressCallback = null) {
+async function processImageUrls(imageUrls, progressCallback = null, noteUrl = '') {
   if (!imageUrls || imageUrls.length === 0) {
     return [];
   }
@@ -1432,7 +1467,7 @@ async function processImageUrls(imageUrls, progressCallback = null) {
 
   for (let i = 0; i < total; i++) {
     try {
-      const cdnUrl = await processSingleImage(imageUrls[i], i);
+      const cdnUrl = await processSingleImage(imageUrls[i], i, noteUrl);
       results.push(cdnUrl);
 
       if (progressCallback) {
@@ -1542,7 +1577,7 @@ async function syncSingleNoteToFeishu() {
 
       cdnImageUrls = await processImageUrls(originalUrls, (current, total, cdnUrl) => {
         showStatus(`正在处理图片 ${current}/${total}...`);
-      });
+      }, capturedNote.url);
 
       // 第一张作为封面
       if (cdnImageUrls.length > 0) {
@@ -1641,7 +1676,7 @@ async function syncToFeishu() {
       let cdnImage = link.image || '';
       if (link.image && link.image.startsWith('http')) {
         try {
-          cdnImage = await processSingleImage(link.image, 0);
+          cdnImage = await processSingleImage(link.image, 0, link.url);
         } catch (error) {
           console.error('处理封面图失败:', error);
           // 使用原图
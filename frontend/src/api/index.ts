@@ -72,6 +72,7 @@ export interface Note {
   publishDate: number
   source: string // 'single' | 'batch'
   captureTimestamp: number
+  version: number // 每次修改递增，更新时作为 If-Match 发送
   createdAt: string
   updatedAt: string
 }
@@ -119,6 +120,7 @@ export interface Blogger {
   followersCount: number
   bloggerUrl: string
   captureTimestamp: number
+  version: number // 每次修改递增，更新时作为 If-Match 发送
   createdAt: string
   updatedAt: string
 }
@@ -175,9 +177,11 @@ export const notesApi = {
   getById: (id: string) =>
     apiClient.get<any, ApiResponse<Note>>(`/notes/${id}`),
 
-  // 更新笔记
-  update: (id: string, data: Partial<Note>) =>
-    apiClient.put<any, ApiResponse<Note>>(`/notes/${id}`, data),
+  // 更新笔记（version 为读取时的版本号，作为 If-Match 发送；已被他人修改时返回 412）
+  update: (id: string, data: Partial<Note>, version: number) =>
+    apiClient.put<any, ApiResponse<Note>>(`/notes/${id}`, data, {
+      headers: { 'If-Match': `"${version}"` },
+    }),
 
   // 删除笔记
   delete: (id: string) =>
@@ -210,9 +214,11 @@ export const bloggersApi = {
   getByXhsId: (xhsId: string) =>
     apiClient.get<any, ApiResponse<Blogger>>(`/bloggers/xhs/${xhsId}`),
 
-  // 更新博主
-  update: (id: string, data: Partial<Blogger>) =>
-    apiClient.put<any, ApiResponse<Blogger>>(`/bloggers/${id}`, data),
+  // 更新博主（version 为读取时的版本号，作为 If-Match 发送；已被他人修改时返回 412）
+  update: (id: string, data: Partial<Blogger>, version: number) =>
+    apiClient.put<any, ApiResponse<Blogger>>(`/bloggers/${id}`, data, {
+      headers: { 'If-Match': `"${version}"` },
+    }),
 
   // 删除博主
   delete: (id: string) =>